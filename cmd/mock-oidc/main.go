@@ -0,0 +1,34 @@
+// Command mock-oidc runs a local OpenID Connect provider for trying out OIDC login.
+// It signs in any user without a password; never expose it outside development.
+//
+//	go run ./cmd/mock-oidc -addr :9400
+//
+// Send users to it with OIDC_PROVIDERS=mock and the OIDC_MOCK_* values from .env.example.
+// A login_hint parameter on the authorization URL chooses the email address.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"go-gin-starter/pkg/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9400", "listen address")
+	issuerURL := flag.String("issuer", "http://localhost:9400", "issuer URL as clients reach it")
+	clientID := flag.String("client-id", "volleymate", "client ID")
+	clientSecret := flag.String("client-secret", "mock-secret", "client secret")
+	email := flag.String("email", "player@example.com", "email signed in without a login_hint")
+	flag.Parse()
+
+	issuer, err := oidctest.NewIssuer(*issuerURL, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+	issuer.DefaultEmail = *email
+
+	log.Printf("Mock OIDC issuer %s listening on %s", issuer.URL, *addr)
+	log.Fatal(http.ListenAndServe(*addr, issuer))
+}
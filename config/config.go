@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 // Global AWS config variables
@@ -14,6 +16,82 @@ var (
 	AssetCloudFrontDomain string
 )
 
+// CloudFront signing config; signing is off while no key pair ID is configured
+var (
+	CloudFrontKeyPairID      string
+	CloudFrontPrivateKey     string // PEM-encoded, takes precedence over the key path
+	CloudFrontPrivateKeyPath string
+	CloudFrontCookieDomain   string
+	SignedURLTTL             time.Duration
+)
+
+// Object storage config; STORAGE_BACKEND is "s3" (default, also MinIO via S3_ENDPOINT) or "local"
+var (
+	StorageBackend     string
+	S3Endpoint         string
+	S3ForcePathStyle   bool
+	StoragePublicURL   string // base URL objects are served from; defaults to the bucket or local handler
+	LocalStorageDir    string
+	LocalStorageSecret string
+	OrphanAction       string // what the orphan collector does after the grace period: "quarantine" or "delete"
+)
+
+// AppBaseURL is the web app that links in emails, e.g. email verification, point at
+var AppBaseURL string
+
+// Access token signing; without a signing key one is derived from JWTSecret
+var (
+	JWTSecret               string // also keys verification links and 2FA challenges
+	JWTSigningKey           string // PEM-encoded RSA or Ed25519 private key, takes precedence over the path
+	JWTSigningKeyPath       string
+	JWTVerificationKeyPaths []string // further keys tokens are accepted from, e.g. the previous signing key
+)
+
+// Brute-force protection for password logins; a threshold of 0 turns that protection off
+var (
+	LoginDelayAfter      int           // failures of an account before each further attempt is delayed
+	LoginDelayBase       time.Duration // first delay, doubled with every further failure
+	LoginDelayMax        time.Duration
+	LoginLockoutFailures int // failures of an account that lock it
+	LoginLockoutDuration time.Duration
+	LoginFailureWindow   time.Duration // failures are counted again from zero after this long without one
+	LoginIPMaxFailures   int           // failures from one IP address, on any accounts, that block it
+	LoginIPBlockDuration time.Duration
+)
+
+// Two-factor authentication
+var (
+	TOTPEncryptionKey      string          // encrypts stored TOTP secrets; defaults to JWT_SECRET
+	TwoFactorRequiredRoles map[string]bool // roles that cannot use the app without 2FA
+)
+
+// OIDCProvider is an OpenID Connect identity provider users can sign in with
+type OIDCProvider struct {
+	Name         string // used in API paths and to tell identities of different providers apart
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// OpenID Connect login; providers are listed in OIDC_PROVIDERS and configured per name
+var (
+	OIDCProviders   []OIDCProvider
+	OIDCRedirectURL string // page of the web app providers send users back to
+	OIDCDefaultRole string // role of users created by their first OIDC login
+)
+
+// Outgoing mail; MAIL_BACKEND is "log" (default), "file" or "smtp"
+var (
+	MailBackend  string
+	MailFrom     string
+	MailDir      string // where the file backend writes .eml files
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+)
+
 // InitConfig initializes all config values after LoadEnv is called
 func InitConfig() {
 	AWSRegion = os.Getenv("AWS_REGION")
@@ -22,6 +100,87 @@ func InitConfig() {
 	VideoCloudFrontDomain = os.Getenv("VIDEO_CLOUDFRONT_DOMAIN")
 	AssetCloudFrontDomain = os.Getenv("ASSET_CLOUDFRONT_DOMAIN")
 
+	CloudFrontKeyPairID = os.Getenv("CLOUDFRONT_KEY_PAIR_ID")
+	CloudFrontPrivateKey = os.Getenv("CLOUDFRONT_PRIVATE_KEY")
+	CloudFrontPrivateKeyPath = os.Getenv("CLOUDFRONT_PRIVATE_KEY_PATH")
+	CloudFrontCookieDomain = os.Getenv("CLOUDFRONT_COOKIE_DOMAIN")
+
+	SignedURLTTL = 15 * time.Minute
+	if ttl, err := time.ParseDuration(os.Getenv("SIGNED_URL_TTL")); err == nil && ttl > 0 {
+		SignedURLTTL = ttl
+	}
+
+	StorageBackend = os.Getenv("STORAGE_BACKEND")
+	S3Endpoint = os.Getenv("S3_ENDPOINT")
+	S3ForcePathStyle = os.Getenv("S3_FORCE_PATH_STYLE") == "true"
+	StoragePublicURL = os.Getenv("STORAGE_PUBLIC_URL")
+	LocalStorageDir = GetEnvWithDefault("LOCAL_STORAGE_DIR", "./storage")
+	LocalStorageSecret = os.Getenv("LOCAL_STORAGE_SECRET")
+	if LocalStorageSecret == "" {
+		LocalStorageSecret = os.Getenv("JWT_SECRET")
+	}
+	OrphanAction = GetEnvWithDefault("ORPHAN_GC_ACTION", "quarantine")
+	if StorageBackend == "local" && StoragePublicURL == "" {
+		StoragePublicURL = "http://localhost:" + GetEnvWithDefault("PORT", "8080") + "/storage"
+	}
+
+	AppBaseURL = strings.TrimRight(GetEnvWithDefault("APP_BASE_URL", "http://localhost:3000"), "/")
+
+	JWTSecret = os.Getenv("JWT_SECRET")
+	JWTSigningKey = os.Getenv("JWT_SIGNING_KEY")
+	JWTSigningKeyPath = os.Getenv("JWT_SIGNING_KEY_PATH")
+	JWTVerificationKeyPaths = nil
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_PATHS"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			JWTVerificationKeyPaths = append(JWTVerificationKeyPaths, path)
+		}
+	}
+
+	LoginDelayAfter = getEnvInt("LOGIN_DELAY_AFTER_FAILURES", 3)
+	LoginDelayBase = getEnvDuration("LOGIN_DELAY_BASE", time.Second)
+	LoginDelayMax = getEnvDuration("LOGIN_DELAY_MAX", time.Minute)
+	LoginLockoutFailures = getEnvInt("LOGIN_LOCKOUT_FAILURES", 10)
+	LoginLockoutDuration = getEnvDuration("LOGIN_LOCKOUT_DURATION", 30*time.Minute)
+	LoginFailureWindow = getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+	LoginIPMaxFailures = getEnvInt("LOGIN_IP_MAX_FAILURES", 50)
+	LoginIPBlockDuration = getEnvDuration("LOGIN_IP_BLOCK_DURATION", 15*time.Minute)
+
+	TOTPEncryptionKey = os.Getenv("TOTP_ENCRYPTION_KEY")
+	if TOTPEncryptionKey == "" {
+		TOTPEncryptionKey = os.Getenv("JWT_SECRET")
+	}
+	TwoFactorRequiredRoles = map[string]bool{}
+	for _, role := range strings.Split(GetEnvWithDefault("TWO_FACTOR_REQUIRED_ROLES", "super_admin,admin"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			TwoFactorRequiredRoles[role] = true
+		}
+	}
+
+	OIDCProviders = nil
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		if name = strings.TrimSpace(strings.ToLower(name)); name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		OIDCProviders = append(OIDCProviders, OIDCProvider{
+			Name:         name,
+			Issuer:       strings.TrimRight(os.Getenv(prefix+"ISSUER"), "/"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(GetEnvWithDefault(prefix+"SCOPES", "openid email profile")),
+		})
+	}
+	OIDCRedirectURL = GetEnvWithDefault("OIDC_REDIRECT_URL", AppBaseURL+"/oidc/callback")
+	OIDCDefaultRole = GetEnvWithDefault("OIDC_DEFAULT_ROLE", "guest")
+
+	MailBackend = GetEnvWithDefault("MAIL_BACKEND", "log")
+	MailFrom = GetEnvWithDefault("MAIL_FROM", "Volleymate <no-reply@localhost>")
+	MailDir = GetEnvWithDefault("MAIL_DIR", "./mail")
+	SMTPHost = os.Getenv("SMTP_HOST")
+	SMTPPort = GetEnvWithDefault("SMTP_PORT", "587")
+	SMTPUsername = os.Getenv("SMTP_USERNAME")
+	SMTPPassword = os.Getenv("SMTP_PASSWORD")
+
 	fmt.Println("DEBUG: Using VIDEO_CLOUDFRONT_DOMAIN =", VideoCloudFrontDomain)
 }
 
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -48,6 +50,24 @@ func GetEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt returns the environment variable as a non-negative integer, or the default if
+// it is not set or invalid
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration returns the environment variable as a positive duration such as "15m", or
+// the default if it is not set or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 // GetRequiredEnv returns the value of the environment variable or panics if not set
 func GetRequiredEnv(key string) string {
 	if value, exists := os.LookupEnv(key); exists && value != "" {
@@ -17,13 +17,19 @@ import (
 
 // AdminUserController handles admin-specific user operations
 type AdminUserController struct {
-	userService services.UserService
+	userService      services.UserService
+	authService      services.AuthService
+	twoFactorService services.TwoFactorService
+	sessionService   services.SessionService
 }
 
 // NewAdminUserController creates a new admin user controller
-func NewAdminUserController(userService services.UserService) *AdminUserController {
+func NewAdminUserController(userService services.UserService, authService services.AuthService, twoFactorService services.TwoFactorService, sessionService services.SessionService) *AdminUserController {
 	return &AdminUserController{
-		userService: userService,
+		userService:      userService,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+		sessionService:   sessionService,
 	}
 }
 
@@ -109,3 +115,156 @@ func (c *AdminUserController) DeleteUserByAdmin(ctx *gin.Context) {
 
 	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgUserDeleted)
 }
+
+// UpdateEmailVerification handles PATCH /api/admin/users/:id/email-verification
+func (c *AdminUserController) UpdateEmailVerification(ctx *gin.Context) {
+	var input dto.UpdateEmailVerificationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUserID)
+		return
+	}
+
+	originalUser, err := c.userService.GetUserByID(userID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+	wasVerified := originalUser.EmailVerifiedAt != nil
+
+	updatedUser, err := c.userService.SetEmailVerified(userID, *input.Verified)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrDatabase)
+		return
+	}
+
+	metadata := auditPkg.BuildEmailVerificationMetadata(updatedUser, wasVerified)
+
+	adminID := ctx.MustGet("user_id").(uuid.UUID)
+	_ = services.LogAdminAction(adminID, "update_email_verification", &userID, nil, nil, nil, metadata)
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, httpPkg.BuildAdminUserResponse(updatedUser), constants.MsgVerificationUpdated)
+}
+
+// ResetTwoFactor handles DELETE /api/admin/users/:id/2fa
+func (c *AdminUserController) ResetTwoFactor(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUserID)
+		return
+	}
+
+	originalUser, err := c.userService.GetUserByID(userID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+
+	if err := c.twoFactorService.Reset(userID); err != nil {
+		switch err.Error() {
+		case constants.ErrTwoFactorDisabled:
+			httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+		case constants.ErrUserNotFound:
+			httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	metadata := auditPkg.BuildTwoFactorResetMetadata(originalUser)
+
+	adminID := ctx.MustGet("user_id").(uuid.UUID)
+	_ = services.LogAdminAction(adminID, "reset_two_factor", &userID, nil, nil, nil, metadata)
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgTwoFactorReset)
+}
+
+// UnlockAccount handles DELETE /api/admin/users/:id/lock
+func (c *AdminUserController) UnlockAccount(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUserID)
+		return
+	}
+
+	originalUser, err := c.userService.GetUserByID(userID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+
+	if err := c.authService.AdminUnlockAccount(userID); err != nil {
+		switch err.Error() {
+		case constants.ErrAccountNotLocked:
+			httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+		case constants.ErrUserNotFound:
+			httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	metadata := auditPkg.BuildAccountUnlockMetadata(originalUser)
+
+	adminID := ctx.MustGet("user_id").(uuid.UUID)
+	_ = services.LogAdminAction(adminID, "unlock_account", &userID, nil, nil, nil, metadata)
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgAccountUnlocked)
+}
+
+// GetUserSessions handles GET /api/admin/users/:id/sessions
+func (c *AdminUserController) GetUserSessions(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUserID)
+		return
+	}
+
+	if _, err := c.userService.GetUserByID(userID); err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+
+	sessions, err := c.sessionService.List(userID, uuid.Nil)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, sessions, constants.MsgSessionsFetched)
+}
+
+// RevokeUserSessions handles DELETE /api/admin/users/:id/sessions
+func (c *AdminUserController) RevokeUserSessions(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUserID)
+		return
+	}
+
+	user, err := c.userService.GetUserByID(userID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrUserNotFound)
+		return
+	}
+
+	revoked, err := c.sessionService.RevokeAll(userID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	metadata := auditPkg.BuildSessionRevokeMetadata(user, revoked)
+
+	adminID := ctx.MustGet("user_id").(uuid.UUID)
+	_ = services.LogAdminAction(adminID, "revoke_sessions", &userID, nil, nil, nil, metadata)
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, dto.RevokedSessionsResponse{Revoked: revoked}, constants.MsgSessionsRevoked)
+}
@@ -1,17 +1,19 @@
 package controllers
 
 import (
-	"fmt"
-	"go-gin-starter/config"
+	"errors"
 	"go-gin-starter/dto"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/auth"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/services"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // AuthController handles authentication-related HTTP requests
@@ -34,7 +36,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.authService.Register(input.Username, input.Email, input.Password, input.Gender)
+	user, err := c.authService.Register(input.Username, input.Email, input.Password, input.Gender, input.Locale)
 	if err != nil {
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -48,10 +50,14 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		Username:         user.Username,
 		Email:            user.Email,
 		Gender:           string(user.Gender),
-		AvatarURL:        fmt.Sprintf("https://%s/avatars/%s", config.AssetCloudFrontDomain, user.Avatar),
+		AvatarURL:        httpPkg.UserAvatarURL(user),
+		AvatarURLs:       assets.VariantURLs(user.AvatarVariants),
 		Role:             string(user.Role),
 		Permissions:      allPermissions,
 		ExtraPermissions: []string{}, // New users have no extra permissions
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TwoFactorEnabledAt != nil,
+		Locale:           user.Locale,
 		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 	}
@@ -66,16 +72,56 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := c.authService.Login(input.Email, input.Password)
+	result, err := c.authService.Login(input.Email, input.Password, clientInfo(ctx))
 	if err != nil {
-		httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
+		setRetryAfter(ctx, err)
+		switch err.Error() {
+		case constants.ErrAccountLocked:
+			httpPkg.RespondError(ctx, http.StatusLocked, err.Error())
+		case constants.ErrLoginThrottled:
+			httpPkg.RespondError(ctx, http.StatusTooManyRequests, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// LoginTwoFactor handles POST /api/login/2fa
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	var input dto.TwoFactorLoginInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := c.authService.CompleteTwoFactorLogin(input.ChallengeToken, input.Code, clientInfo(ctx))
+	if err != nil {
+		setRetryAfter(ctx, err)
+		switch err.Error() {
+		case constants.ErrAccountLocked:
+			httpPkg.RespondError(ctx, http.StatusLocked, err.Error())
+		case constants.ErrTooManyCodeAttempts, constants.ErrLoginThrottled:
+			httpPkg.RespondError(ctx, http.StatusTooManyRequests, err.Error())
+		case constants.ErrInvalidToken, constants.ErrInvalidTwoFactorCode, constants.ErrTwoFactorDisabled:
+			httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-	})
+	ctx.JSON(http.StatusOK, result)
+}
+
+// setRetryAfter tells the client when a throttled or locked login may be tried again
+func setRetryAfter(ctx *gin.Context, err error) {
+	var retry *services.RetryAfterError
+	if errors.As(err, &retry) {
+		ctx.Header("Retry-After", strconv.Itoa(int((retry.RetryAfter+time.Second-1)/time.Second)))
+	}
 }
 
 // RefreshToken handles token refresh
@@ -88,19 +134,16 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := c.authService.RefreshToken(input.RefreshToken)
+	result, err := c.authService.RefreshToken(input.RefreshToken, clientInfo(ctx))
 	if err != nil {
 		httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-	})
+	ctx.JSON(http.StatusOK, result)
 }
 
-// ForgotPassword handles password reset requests
+// ForgotPassword handles password reset requests by emailing a reset link
 func (c *AuthController) ForgotPassword(ctx *gin.Context) {
 	var input dto.ForgotPasswordInput
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -108,13 +151,12 @@ func (c *AuthController) ForgotPassword(ctx *gin.Context) {
 		return
 	}
 
-	resetToken, err := c.authService.ForgotPassword(input.Email)
-	if err != nil {
+	if err := c.authService.ForgotPassword(input.Email); err != nil {
 		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	httpPkg.RespondSuccess(ctx, http.StatusOK, gin.H{"reset_token": resetToken}, constants.MsgResetTokenCreated)
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgResetLinkSent)
 }
 
 // ResetPassword handles password reset using a token
@@ -133,3 +175,65 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 
 	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgPasswordReset)
 }
+
+// VerifyEmail handles POST /api/email/verify
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	var input dto.VerifyEmailInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidToken)
+		return
+	}
+
+	if _, err := c.authService.VerifyEmail(input.Token); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgEmailVerified)
+}
+
+// UnlockAccount handles POST /api/login/unlock
+func (c *AuthController) UnlockAccount(ctx *gin.Context) {
+	var input dto.UnlockAccountInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidToken)
+		return
+	}
+
+	if err := c.authService.UnlockAccount(input.Token); err != nil {
+		switch err.Error() {
+		case constants.ErrInvalidToken:
+			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgAccountUnlocked)
+}
+
+// ResendVerification handles POST /api/email/verify/resend
+func (c *AuthController) ResendVerification(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	if err := c.authService.ResendVerification(userID); err != nil {
+		switch err.Error() {
+		case constants.ErrEmailAlreadyVerified:
+			httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+		case constants.ErrVerificationTooSoon:
+			httpPkg.RespondError(ctx, http.StatusTooManyRequests, err.Error())
+		case constants.ErrUserNotFound:
+			httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgVerificationSent)
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	authPkg "go-gin-starter/pkg/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONWebKeySet handles GET /.well-known/jwks.json, the public keys other services verify
+// our access tokens with. Clients may cache it briefly; new keys are published before use.
+func JSONWebKeySet(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, authPkg.JSONWebKeys())
+}
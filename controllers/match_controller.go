@@ -6,7 +6,7 @@ import (
 	"go-gin-starter/models"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
-	scoutPkg "go-gin-starter/pkg/scout"
+	"go-gin-starter/pkg/upload"
 	"go-gin-starter/services"
 	"net/http"
 
@@ -51,7 +51,8 @@ func (c *MatchController) CreateMatch(ctx *gin.Context) {
 
 // GetAllMatches handles GET /api/admin/matches
 func (c *MatchController) GetAllMatches(ctx *gin.Context) {
-	matches, err := c.matchService.GetAllMatches()
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	matches, err := c.matchService.GetAllMatches(userID)
 	if err != nil {
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrDatabase)
 		return
@@ -67,7 +68,8 @@ func (c *MatchController) GetMatchByID(ctx *gin.Context) {
 		return
 	}
 
-	match, err := c.matchService.GetMatchByID(id)
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	match, err := c.matchService.GetMatchByID(id, userID)
 	if err != nil {
 		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrMatchNotFound)
 		return
@@ -136,8 +138,12 @@ func (c *MatchController) UploadMatchVideo(ctx *gin.Context) {
 	}
 	defer src.Close()
 
-	videoURL, err := c.matchService.UploadMatchVideo(matchID, src, file)
+	videoURL, err := c.matchService.UploadMatchVideo(matchID, ctx.PostForm("angle"), src, file)
 	if err != nil {
+		if err.Error() == constants.ErrInvalidVideoAngle || upload.IsValidationError(err) {
+			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -168,6 +174,10 @@ func (c *MatchController) UploadMatchScout(ctx *gin.Context) {
 
 	jsonURL, err := c.matchService.UploadMatchScout(matchID, src, file)
 	if err != nil {
+		if upload.IsValidationError(err) {
+			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -183,21 +193,17 @@ func (c *MatchController) PreviewScoutMetadata(ctx *gin.Context) {
 		return
 	}
 
-	// Get match to verify it exists and get the scout URL
-	match, err := c.matchService.GetMatchByID(matchID)
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	metadata, err := c.matchService.GetScoutMetadata(matchID, userID)
 	if err != nil {
-		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrMatchNotFound)
-		return
-	}
-
-	if match.ScoutJSON == "" {
-		httpPkg.RespondError(ctx, http.StatusNotFound, "No scout file found for this match")
-		return
-	}
-
-	metadata, err := scoutPkg.ExtractScoutMetadata(match.ScoutJSON)
-	if err != nil {
-		httpPkg.RespondError(ctx, http.StatusInternalServerError, fmt.Sprintf("failed to extract metadata: %v", err))
+		switch err.Error() {
+		case constants.ErrMatchNotFound, constants.ErrScoutNotFound:
+			httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+		case constants.ErrMediaAccessDenied:
+			httpPkg.RespondError(ctx, http.StatusForbidden, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, fmt.Sprintf("failed to extract metadata: %v", err))
+		}
 		return
 	}
 
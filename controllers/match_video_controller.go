@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/cdn"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MatchVideoController handles the camera angles of a match
+type MatchVideoController struct {
+	matchVideoService services.MatchVideoService
+}
+
+// NewMatchVideoController creates a new instance of MatchVideoController
+func NewMatchVideoController(matchVideoService services.MatchVideoService) *MatchVideoController {
+	return &MatchVideoController{
+		matchVideoService: matchVideoService,
+	}
+}
+
+// GetMatchVideos handles GET /api/matches/:id/videos
+func (c *MatchVideoController) GetMatchVideos(ctx *gin.Context) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	videos, err := c.matchVideoService.GetMatchVideos(matchID, userID)
+	if err != nil {
+		c.respondMatchVideoError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, videos, constants.MsgMatchVideosFetched)
+}
+
+// GetMatchPlayback handles GET /api/matches/:id/playback
+func (c *MatchVideoController) GetMatchPlayback(ctx *gin.Context) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	playback, err := c.matchVideoService.GetMatchPlayback(matchID, userID)
+	if err != nil {
+		c.respondMatchVideoError(ctx, err)
+		return
+	}
+
+	// Signed cookies cover files referenced relatively, e.g. sprites listed in the storyboard
+	cookies, err := cdn.SignedCookies(playback.CookieFolder)
+	if err != nil {
+		logger.Error("Failed to sign CloudFront cookies", zap.String("match_id", matchID.String()), zap.Error(err))
+	}
+	for _, cookie := range cookies {
+		cookie.Expires = playback.ExpiresAt
+		http.SetCookie(ctx.Writer, cookie)
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playback, constants.MsgPlaybackAuthorized)
+}
+
+// UpdateMatchVideo handles PATCH /api/admin/matches/:id/videos/:video_id
+func (c *MatchVideoController) UpdateMatchVideo(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateMatchVideoInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	video, err := c.matchVideoService.UpdateMatchVideo(matchID, videoID, &input)
+	if err != nil {
+		c.respondMatchVideoError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, video, constants.MsgMatchVideoUpdated)
+}
+
+// DeleteMatchVideo handles DELETE /api/admin/matches/:id/videos/:video_id
+func (c *MatchVideoController) DeleteMatchVideo(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.matchVideoService.DeleteMatchVideo(matchID, videoID); err != nil {
+		c.respondMatchVideoError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgMatchVideoDeleted)
+}
+
+// parseIDs reads the match and video IDs from the path, responding on failure
+func (c *MatchVideoController) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	videoID, err := uuid.Parse(ctx.Param("video_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchVideoID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return matchID, videoID, true
+}
+
+// respondMatchVideoError maps service errors to HTTP status codes
+func (c *MatchVideoController) respondMatchVideoError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrMatchNotFound, constants.ErrMatchVideoNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrMediaAccessDenied:
+		httpPkg.RespondError(ctx, http.StatusForbidden, err.Error())
+	case constants.ErrDuplicateVideoAngle:
+		httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+	case constants.ErrInvalidVideoAngle, constants.ErrReferenceAngleOffset:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
+	}
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCController handles signing in with OpenID Connect providers
+type OIDCController struct {
+	oidcService services.OIDCService
+}
+
+// NewOIDCController creates a new instance of OIDCController
+func NewOIDCController(oidcService services.OIDCService) *OIDCController {
+	return &OIDCController{
+		oidcService: oidcService,
+	}
+}
+
+// GetProviders handles GET /api/oidc/providers
+func (c *OIDCController) GetProviders(ctx *gin.Context) {
+	httpPkg.RespondSuccess(ctx, http.StatusOK, c.oidcService.Providers(), constants.MsgProvidersFetched)
+}
+
+// Authorize handles GET /api/oidc/:provider/authorize
+func (c *OIDCController) Authorize(ctx *gin.Context) {
+	result, err := c.oidcService.Authorize(ctx.Request.Context(), ctx.Param("provider"))
+	if err != nil {
+		switch err.Error() {
+		case constants.ErrUnknownProvider:
+			httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+		case constants.ErrProviderUnavailable:
+			httpPkg.RespondError(ctx, http.StatusBadGateway, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, result, constants.MsgProviderLoginStarted)
+}
+
+// Callback handles POST /api/oidc/callback
+func (c *OIDCController) Callback(ctx *gin.Context) {
+	var input dto.OIDCCallbackInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := c.oidcService.Callback(ctx.Request.Context(), input.Code, input.State, clientInfo(ctx))
+	if err != nil {
+		switch err.Error() {
+		case constants.ErrInvalidLoginState, constants.ErrUnknownProvider:
+			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		case constants.ErrProviderLoginFailed, constants.ErrProviderEmailMissing, constants.ErrUserNotFound:
+			httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
+		case constants.ErrAccountUnverified:
+			httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+		default:
+			httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
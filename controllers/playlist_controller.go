@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PlaylistController handles clip playlists shared with players
+type PlaylistController struct {
+	playlistService services.PlaylistService
+}
+
+// NewPlaylistController creates a new instance of PlaylistController
+func NewPlaylistController(playlistService services.PlaylistService) *PlaylistController {
+	return &PlaylistController{
+		playlistService: playlistService,
+	}
+}
+
+// CreatePlaylist handles POST /api/playlists
+func (c *PlaylistController) CreatePlaylist(ctx *gin.Context) {
+	var input dto.CreatePlaylistInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.CreatePlaylist(userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusCreated, playlist, constants.MsgPlaylistCreated)
+}
+
+// GetPlaylists handles GET /api/playlists
+func (c *PlaylistController) GetPlaylists(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlists, err := c.playlistService.GetPlaylists(userID)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlists, constants.MsgPlaylistsFetched)
+}
+
+// GetPlaylist handles GET /api/playlists/:id
+func (c *PlaylistController) GetPlaylist(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.GetPlaylist(id, userID)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlist, constants.MsgPlaylistFetched)
+}
+
+// UpdatePlaylist handles PATCH /api/playlists/:id
+func (c *PlaylistController) UpdatePlaylist(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdatePlaylistInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.UpdatePlaylist(id, userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlist, constants.MsgPlaylistUpdated)
+}
+
+// DeletePlaylist handles DELETE /api/playlists/:id
+func (c *PlaylistController) DeletePlaylist(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	if err := c.playlistService.DeletePlaylist(id, userID); err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgPlaylistDeleted)
+}
+
+// AddPlaylistItem handles POST /api/playlists/:id/items
+func (c *PlaylistController) AddPlaylistItem(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.PlaylistItemInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.AddItem(id, userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusCreated, playlist, constants.MsgPlaylistUpdated)
+}
+
+// UpdatePlaylistItem handles PATCH /api/playlists/:id/items/:item_id
+func (c *PlaylistController) UpdatePlaylistItem(ctx *gin.Context) {
+	id, itemID, ok := c.parseItemIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdatePlaylistItemInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.UpdateItem(id, itemID, userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlist, constants.MsgPlaylistUpdated)
+}
+
+// RemovePlaylistItem handles DELETE /api/playlists/:id/items/:item_id
+func (c *PlaylistController) RemovePlaylistItem(ctx *gin.Context) {
+	id, itemID, ok := c.parseItemIDs(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	if err := c.playlistService.RemoveItem(id, itemID, userID); err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgPlaylistUpdated)
+}
+
+// ReorderPlaylistItems handles PUT /api/playlists/:id/items/order
+func (c *PlaylistController) ReorderPlaylistItems(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.ReorderPlaylistItemsInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.ReorderItems(id, userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlist, constants.MsgPlaylistUpdated)
+}
+
+// SharePlaylist handles PUT /api/playlists/:id/shares
+func (c *PlaylistController) SharePlaylist(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.SharePlaylistInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	playlist, err := c.playlistService.SharePlaylist(id, userID, &input)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, playlist, constants.MsgPlaylistShared)
+}
+
+// RecordPlaylistView handles POST /api/playlists/:id/items/:item_id/views
+func (c *PlaylistController) RecordPlaylistView(ctx *gin.Context) {
+	id, itemID, ok := c.parseItemIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.RecordPlaylistViewInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	if err := c.playlistService.RecordView(id, itemID, userID, &input); err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgPlaylistViewRecorded)
+}
+
+// GetPlaylistViews handles GET /api/playlists/:id/views
+func (c *PlaylistController) GetPlaylistViews(ctx *gin.Context) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	views, err := c.playlistService.GetViews(id, userID)
+	if err != nil {
+		c.respondPlaylistError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, views, constants.MsgPlaylistViewsFetched)
+}
+
+// parsePlaylistID reads the playlist ID from the path, responding on failure
+func (c *PlaylistController) parsePlaylistID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidPlaylistID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseItemIDs reads the playlist and item IDs from the path, responding on failure
+func (c *PlaylistController) parseItemIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := c.parsePlaylistID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	itemID, err := uuid.Parse(ctx.Param("item_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidPlaylistItemID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, itemID, true
+}
+
+// respondPlaylistError maps service errors to HTTP status codes
+func (c *PlaylistController) respondPlaylistError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrPlaylistNotFound, constants.ErrPlaylistItemNotFound,
+		constants.ErrMatchVideoNotFound, constants.ErrUserNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrForbidden:
+		httpPkg.RespondError(ctx, http.StatusForbidden, err.Error())
+	case constants.ErrVideoNotReady:
+		httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+	case constants.ErrInvalidClipRange, constants.ErrInvalidItemOrder:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
+	}
+}
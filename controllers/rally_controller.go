@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RallyController handles the rally segments of match videos
+type RallyController struct {
+	rallyService services.RallyService
+}
+
+// NewRallyController creates a new instance of RallyController
+func NewRallyController(rallyService services.RallyService) *RallyController {
+	return &RallyController{
+		rallyService: rallyService,
+	}
+}
+
+// GetRallies handles GET /api/matches/:id/videos/:video_id/rallies
+func (c *RallyController) GetRallies(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	rallies, err := c.rallyService.GetRallies(matchID, videoID)
+	if err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, rallies, constants.MsgRalliesFetched)
+}
+
+// DetectRallies handles POST /api/matches/:id/videos/:video_id/rallies/detect
+func (c *RallyController) DetectRallies(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	rallies, err := c.rallyService.DetectRallies(matchID, videoID)
+	if err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusAccepted, rallies, constants.MsgRallyDetectionQueued)
+}
+
+// CreateRally handles POST /api/matches/:id/videos/:video_id/rallies
+func (c *RallyController) CreateRally(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.CreateRallyInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	rally, err := c.rallyService.CreateRally(matchID, videoID, userID, &input)
+	if err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusCreated, rally, constants.MsgRallyCreated)
+}
+
+// UpdateRally handles PATCH /api/matches/:id/videos/:video_id/rallies/:rally_id
+func (c *RallyController) UpdateRally(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	rallyID, err := uuid.Parse(ctx.Param("rally_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidRallyID)
+		return
+	}
+
+	var input dto.UpdateRallyInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	rally, err := c.rallyService.UpdateRally(matchID, videoID, rallyID, userID, &input)
+	if err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, rally, constants.MsgRallyUpdated)
+}
+
+// DeleteRally handles DELETE /api/matches/:id/videos/:video_id/rallies/:rally_id
+func (c *RallyController) DeleteRally(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	rallyID, err := uuid.Parse(ctx.Param("rally_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidRallyID)
+		return
+	}
+
+	if err := c.rallyService.DeleteRally(matchID, videoID, rallyID); err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgRallyDeleted)
+}
+
+// GenerateCondensedVideo handles POST /api/matches/:id/videos/:video_id/condensed
+func (c *RallyController) GenerateCondensedVideo(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	condensed, err := c.rallyService.GenerateCondensedVideo(matchID, videoID)
+	if err != nil {
+		c.respondRallyError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusAccepted, condensed, constants.MsgCondensedQueued)
+}
+
+// parseVideoIDs reads the match and video IDs from the path, responding on failure
+func (c *RallyController) parseVideoIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	videoID, err := uuid.Parse(ctx.Param("video_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchVideoID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return matchID, videoID, true
+}
+
+// respondRallyError maps service errors to HTTP status codes
+func (c *RallyController) respondRallyError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrMatchVideoNotFound, constants.ErrRallyNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrVideoNotReady, constants.ErrNoAudioStream:
+		httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+	case constants.ErrInvalidRallyTime:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
+	}
+}
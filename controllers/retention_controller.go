@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetentionController handles raw video retention policies and storage reports
+type RetentionController struct {
+	retentionService services.RetentionService
+}
+
+// NewRetentionController creates a new instance of RetentionController
+func NewRetentionController(retentionService services.RetentionService) *RetentionController {
+	return &RetentionController{
+		retentionService: retentionService,
+	}
+}
+
+// GetSeasonRetentionPolicy handles GET /api/admin/seasons/:id/retention-policy
+func (c *RetentionController) GetSeasonRetentionPolicy(ctx *gin.Context) {
+	seasonID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidSeasonID)
+		return
+	}
+
+	policy, err := c.retentionService.GetSeasonPolicy(seasonID)
+	if err != nil {
+		c.respondRetentionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, policy, constants.MsgRetentionPolicyFetched)
+}
+
+// UpdateSeasonRetentionPolicy handles PUT /api/admin/seasons/:id/retention-policy
+func (c *RetentionController) UpdateSeasonRetentionPolicy(ctx *gin.Context) {
+	seasonID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidSeasonID)
+		return
+	}
+
+	var input dto.UpdateRetentionPolicyInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy, err := c.retentionService.UpdateSeasonPolicy(seasonID, &input)
+	if err != nil {
+		c.respondRetentionError(ctx, err)
+		return
+	}
+
+	adminID := ctx.MustGet("user_id").(uuid.UUID)
+	metadata := models.JSONBMap{
+		"raw_action":            policy.RawAction,
+		"raw_grace_days":        policy.RawGraceDays,
+		"archive_storage_class": policy.ArchiveStorageClass,
+		"failed_raw_flag_days":  policy.FailedRawFlagDays,
+	}
+	if err := services.LogAdminAction(adminID, "update_retention_policy", nil, nil, &seasonID, nil, metadata); err != nil {
+		logger.Warn("LogAdminAction failed", zap.Error(err))
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, policy, constants.MsgRetentionPolicyUpdated)
+}
+
+// GetStorageReport handles GET /api/admin/storage/report
+func (c *RetentionController) GetStorageReport(ctx *gin.Context) {
+	var seasonID *uuid.UUID
+	if raw := ctx.Query("season_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidSeasonID)
+			return
+		}
+		seasonID = &parsed
+	}
+
+	report, err := c.retentionService.GetStorageReport(seasonID)
+	if err != nil {
+		c.respondRetentionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, report, constants.MsgStorageReportFetched)
+}
+
+// GetOrphanReport handles GET /api/admin/storage/orphans
+func (c *RetentionController) GetOrphanReport(ctx *gin.Context) {
+	report, err := c.retentionService.GetOrphanReport()
+	if err != nil {
+		logger.Error("Orphan report failed", zap.Error(err))
+		c.respondRetentionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, report, constants.MsgOrphanReportFetched)
+}
+
+// respondRetentionError maps service errors to HTTP status codes
+func (c *RetentionController) respondRetentionError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrSeasonNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrInvalidRawAction:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
+	}
+}
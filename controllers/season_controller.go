@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/pkg/logger"
@@ -119,12 +121,10 @@ func (c *SeasonController) UploadSeasonLogo(ctx *gin.Context) {
 		return
 	}
 
-	// Use the file upload service to validate and upload the file
-	logoURL, err := c.uploadService.ValidateAndUploadFile(ctx, "logo", upload.SeasonLogo, constants.MaxLogoFileSize)
+	// Decode, resize and upload every rendition of the logo
+	logo, err := c.uploadService.ValidateAndUploadImage(ctx, "logo", upload.SeasonLogo, constants.MaxLogoFileSize)
 	if err != nil {
-		if err.Error() == constants.ErrLogoTooLarge ||
-			err.Error() == constants.ErrFileUploadRequired ||
-			err.Error() == constants.ErrInvalidFileType {
+		if upload.IsValidationError(err) {
 			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -133,13 +133,14 @@ func (c *SeasonController) UploadSeasonLogo(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.seasonService.UpdateSeasonLogo(id, logoURL); err != nil {
+	if err := c.seasonService.UpdateSeasonLogo(id, logo); err != nil {
 		logger.Error("Failed to update season logo in database", zap.Error(err), zap.String("seasonID", id.String()))
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
 		return
 	}
 
 	httpPkg.RespondSuccess(ctx, http.StatusOK, gin.H{
-		"logo_url": logoURL,
+		"logo_url":  assets.URL(logo[models.ImageSizeLarge]),
+		"logo_urls": assets.VariantURLs(logo),
 	}, constants.MsgLogoUploaded)
 }
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionController handles the sessions a user is logged in with
+type SessionController struct {
+	sessionService services.SessionService
+}
+
+// NewSessionController creates a new instance of SessionController
+func NewSessionController(sessionService services.SessionService) *SessionController {
+	return &SessionController{
+		sessionService: sessionService,
+	}
+}
+
+// GetSessions handles GET /api/profile/sessions
+func (c *SessionController) GetSessions(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	sessionID := ctx.MustGet("session_id").(uuid.UUID)
+
+	sessions, err := c.sessionService.List(userID, sessionID)
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, sessions, constants.MsgSessionsFetched)
+}
+
+// RevokeSession handles DELETE /api/profile/sessions/:id
+func (c *SessionController) RevokeSession(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusNotFound, constants.ErrSessionNotFound)
+		return
+	}
+
+	if err := c.sessionService.Revoke(userID, sessionID); err != nil {
+		respondSessionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgSessionRevoked)
+}
+
+// RevokeOtherSessions handles DELETE /api/profile/sessions
+func (c *SessionController) RevokeOtherSessions(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	sessionID := ctx.MustGet("session_id").(uuid.UUID)
+
+	revoked, err := c.sessionService.RevokeOthers(userID, sessionID)
+	if err != nil {
+		respondSessionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, dto.RevokedSessionsResponse{Revoked: revoked}, constants.MsgSessionsRevoked)
+}
+
+// Logout handles POST /api/logout by revoking the session of the request
+func (c *SessionController) Logout(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+	sessionID := ctx.MustGet("session_id").(uuid.UUID)
+
+	if err := c.sessionService.Revoke(userID, sessionID); err != nil {
+		respondSessionError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgLoggedOut)
+}
+
+// respondSessionError maps session service errors to HTTP responses
+func respondSessionError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrSessionNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// clientInfo describes the device a request comes from, for the session it starts or uses
+func clientInfo(ctx *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+}
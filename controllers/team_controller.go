@@ -3,6 +3,7 @@ package controllers
 import (
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/pkg/logger"
@@ -136,12 +137,10 @@ func (c *TeamController) UploadTeamLogo(ctx *gin.Context) {
 		return
 	}
 
-	// Use the file upload service to validate and upload the file
-	logoURL, err := c.uploadService.ValidateAndUploadFile(ctx, "logo", upload.TeamLogo, constants.MaxLogoFileSize)
+	// Decode, resize and upload every rendition of the logo
+	logo, err := c.uploadService.ValidateAndUploadImage(ctx, "logo", upload.TeamLogo, constants.MaxLogoFileSize)
 	if err != nil {
-		if err.Error() == constants.ErrLogoTooLarge ||
-			err.Error() == constants.ErrFileUploadRequired ||
-			err.Error() == constants.ErrInvalidFileType {
+		if upload.IsValidationError(err) {
 			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -150,14 +149,14 @@ func (c *TeamController) UploadTeamLogo(ctx *gin.Context) {
 		return
 	}
 
-	// Update only the filename in DB
-	if err := c.teamService.UpdateTeamLogo(teamID, logoURL); err != nil {
+	if err := c.teamService.UpdateTeamLogo(teamID, logo); err != nil {
 		logger.Error("Failed to update team logo in database", zap.Error(err), zap.String("teamID", teamID.String()))
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrDatabase)
 		return
 	}
 
 	httpPkg.RespondSuccess(ctx, http.StatusOK, gin.H{
-		"logo_url": logoURL,
+		"logo_url":  assets.URL(logo[models.ImageSizeLarge]),
+		"logo_urls": assets.VariantURLs(logo),
 	}, constants.MsgLogoUploaded)
 }
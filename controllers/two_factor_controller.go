@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TwoFactorController handles a user's own two-factor authentication settings
+type TwoFactorController struct {
+	twoFactorService services.TwoFactorService
+}
+
+// NewTwoFactorController creates a new instance of TwoFactorController
+func NewTwoFactorController(twoFactorService services.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// GetStatus handles GET /api/profile/2fa
+func (c *TwoFactorController) GetStatus(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	status, err := c.twoFactorService.GetStatus(userID)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, status, constants.MsgTwoFactorFetched)
+}
+
+// Setup handles POST /api/profile/2fa/setup
+func (c *TwoFactorController) Setup(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	setup, err := c.twoFactorService.Setup(userID)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, setup, constants.MsgTwoFactorSetupStarted)
+}
+
+// Enable handles POST /api/profile/2fa/enable
+func (c *TwoFactorController) Enable(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	var input dto.TwoFactorCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := c.twoFactorService.Enable(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, codes, constants.MsgTwoFactorEnabled)
+}
+
+// Disable handles POST /api/profile/2fa/disable
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	var input dto.DisableTwoFactorInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.twoFactorService.Disable(userID, input.Password, input.Code); err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgTwoFactorDisabled)
+}
+
+// RegenerateRecoveryCodes handles POST /api/profile/2fa/recovery-codes
+func (c *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userID, ok := ctx.MustGet("user_id").(uuid.UUID)
+	if !ok {
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+		return
+	}
+
+	var input dto.TwoFactorCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := c.twoFactorService.RegenerateRecoveryCodes(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, codes, constants.MsgRecoveryCodesRenewed)
+}
+
+// respondTwoFactorError maps two-factor service errors to status codes
+func respondTwoFactorError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrUserNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrTwoFactorEnabled, constants.ErrTwoFactorDisabled, constants.ErrTwoFactorNotSetUp:
+		httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+	case constants.ErrTwoFactorRequired:
+		httpPkg.RespondError(ctx, http.StatusForbidden, err.Error())
+	case constants.ErrInvalidTwoFactorCode, constants.ErrInvalidCredentials:
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	}
+}
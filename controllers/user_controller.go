@@ -1,9 +1,9 @@
 package controllers
 
 import (
-	"fmt"
-	"go-gin-starter/config"
 	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/auth"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
@@ -82,6 +82,10 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 	}
 
 	if err := c.userService.UpdateUserProfile(userID, input); err != nil {
+		if err.Error() == constants.ErrUnsupportedLocale {
+			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -103,7 +107,8 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.userService.ChangeUserPassword(userID, input.OldPassword, input.NewPassword); err != nil {
+	sessionID := ctx.MustGet("session_id").(uuid.UUID)
+	if err := c.userService.ChangeUserPassword(userID, sessionID, input.OldPassword, input.NewPassword); err != nil {
 		if err.Error() == constants.ErrPasswordMismatch {
 			httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrPasswordMismatch)
 			return
@@ -139,12 +144,10 @@ func (c *UserController) UploadAvatar(ctx *gin.Context) {
 		return
 	}
 
-	// Use the file upload service to validate and upload the file
-	avatarURL, err := c.uploadService.ValidateAndUploadFile(ctx, "avatar", upload.UserAvatar, constants.MaxAvatarFileSize)
+	// Decode, resize and upload every rendition of the avatar
+	avatar, err := c.uploadService.ValidateAndUploadImage(ctx, "avatar", upload.UserAvatar, constants.MaxAvatarFileSize)
 	if err != nil {
-		if err.Error() == constants.ErrLogoTooLarge ||
-			err.Error() == constants.ErrFileUploadRequired ||
-			err.Error() == constants.ErrInvalidFileType {
+		if upload.IsValidationError(err) {
 			httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -153,21 +156,24 @@ func (c *UserController) UploadAvatar(ctx *gin.Context) {
 		return
 	}
 
-	// Update the user's avatar URL in the database
+	// Store the object keys of the renditions
 	user, err := c.userService.GetUserByID(userID)
 	if err != nil {
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	user.Avatar = avatarURL
+	user.Avatar = avatar[models.ImageSizeLarge]
+	user.AvatarVariants = avatar
 	if err := c.userService.UpdateUser(user); err != nil {
 		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	cloudFrontAvatarURL := fmt.Sprintf("https://%s/avatars/%s", config.AssetCloudFrontDomain, avatarURL)
-	httpPkg.RespondSuccess(ctx, http.StatusOK, gin.H{"avatar_url": cloudFrontAvatarURL}, constants.MsgAvatarUploaded)
+	httpPkg.RespondSuccess(ctx, http.StatusOK, gin.H{
+		"avatar_url":  httpPkg.UserAvatarURL(user),
+		"avatar_urls": assets.VariantURLs(avatar),
+	}, constants.MsgAvatarUploaded)
 }
 
 // GetAllUsers handles retrieving all users with pagination
@@ -200,10 +206,14 @@ func (c *UserController) GetAllUsers(ctx *gin.Context) {
 			Username:         user.Username,
 			Email:            user.Email,
 			Gender:           string(user.Gender),
-			AvatarURL:        fmt.Sprintf("https://%s/avatars/%s", config.AssetCloudFrontDomain, user.Avatar),
+			AvatarURL:        httpPkg.UserAvatarURL(&user),
+			AvatarURLs:       assets.VariantURLs(user.AvatarVariants),
 			Role:             string(user.Role),
 			Permissions:      allPermissions,
 			ExtraPermissions: extraPermissions,
+			EmailVerified:    user.EmailVerifiedAt != nil,
+			TwoFactorEnabled: user.TwoFactorEnabledAt != nil,
+			Locale:           user.Locale,
 			CreatedAt:        user.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 		})
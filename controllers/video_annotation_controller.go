@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VideoAnnotationController handles timecoded annotations on match videos
+type VideoAnnotationController struct {
+	annotationService services.VideoAnnotationService
+}
+
+// NewVideoAnnotationController creates a new instance of VideoAnnotationController
+func NewVideoAnnotationController(annotationService services.VideoAnnotationService) *VideoAnnotationController {
+	return &VideoAnnotationController{
+		annotationService: annotationService,
+	}
+}
+
+// CreateAnnotation handles POST /api/matches/:id/videos/:video_id/annotations
+func (c *VideoAnnotationController) CreateAnnotation(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input dto.CreateAnnotationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	annotation, err := c.annotationService.CreateAnnotation(matchID, videoID, userID, &input)
+	if err != nil {
+		c.respondAnnotationError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusCreated, annotation, constants.MsgAnnotationCreated)
+}
+
+// GetAnnotations handles GET /api/matches/:id/videos/:video_id/annotations
+func (c *VideoAnnotationController) GetAnnotations(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var filter dto.AnnotationFilterInput
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	annotations, err := c.annotationService.GetAnnotations(matchID, videoID, userID, &filter)
+	if err != nil {
+		c.respondAnnotationError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, annotations, constants.MsgAnnotationsFetched)
+}
+
+// JumpToAnnotation handles GET /api/matches/:id/videos/:video_id/annotations/jump?at=<ms>&direction=next|previous
+func (c *VideoAnnotationController) JumpToAnnotation(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	atMs, err := strconv.ParseInt(ctx.DefaultQuery("at", "0"), 10, 64)
+	if err != nil || atMs < 0 {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	var filter dto.AnnotationFilterInput
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	annotation, err := c.annotationService.GetAdjacentAnnotation(matchID, videoID, userID, atMs, ctx.Query("direction"), &filter)
+	if err != nil {
+		c.respondAnnotationError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, annotation, constants.MsgAnnotationFetched)
+}
+
+// UpdateAnnotation handles PATCH /api/matches/:id/videos/:video_id/annotations/:annotation_id
+func (c *VideoAnnotationController) UpdateAnnotation(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	annotationID, err := uuid.Parse(ctx.Param("annotation_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidAnnotationID)
+		return
+	}
+
+	var input dto.UpdateAnnotationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	annotation, err := c.annotationService.UpdateAnnotation(matchID, videoID, annotationID, userID, &input)
+	if err != nil {
+		c.respondAnnotationError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, annotation, constants.MsgAnnotationUpdated)
+}
+
+// DeleteAnnotation handles DELETE /api/matches/:id/videos/:video_id/annotations/:annotation_id
+func (c *VideoAnnotationController) DeleteAnnotation(ctx *gin.Context) {
+	matchID, videoID, ok := c.parseVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	annotationID, err := uuid.Parse(ctx.Param("annotation_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidAnnotationID)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	if err := c.annotationService.DeleteAnnotation(matchID, videoID, annotationID, userID); err != nil {
+		c.respondAnnotationError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgAnnotationDeleted)
+}
+
+// parseVideoIDs reads the match and video IDs from the path, responding on failure
+func (c *VideoAnnotationController) parseVideoIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	videoID, err := uuid.Parse(ctx.Param("video_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchVideoID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return matchID, videoID, true
+}
+
+// respondAnnotationError maps service errors to HTTP status codes
+func (c *VideoAnnotationController) respondAnnotationError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrMatchVideoNotFound, constants.ErrAnnotationNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrForbidden:
+		httpPkg.RespondError(ctx, http.StatusForbidden, err.Error())
+	case constants.ErrUserNotFound:
+		httpPkg.RespondError(ctx, http.StatusUnauthorized, constants.ErrUnauthorized)
+	case constants.ErrInvalidAnnotationTime, constants.ErrInvalidVisibility, constants.ErrInvalidAnnotationDraw,
+		constants.ErrInvalidJumpDirection, constants.ErrInvalidUserID:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, constants.ErrInternalServer)
+	}
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"go-gin-starter/dto"
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VideoUploadController handles presigned direct-to-S3 video uploads
+type VideoUploadController struct {
+	videoUploadService services.VideoUploadService
+}
+
+// NewVideoUploadController creates a new instance of VideoUploadController
+func NewVideoUploadController(videoUploadService services.VideoUploadService) *VideoUploadController {
+	return &VideoUploadController{
+		videoUploadService: videoUploadService,
+	}
+}
+
+// InitiateVideoUpload handles POST /api/admin/matches/:id/video-uploads
+func (c *VideoUploadController) InitiateVideoUpload(ctx *gin.Context) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return
+	}
+
+	var input dto.InitiateVideoUploadInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(uuid.UUID)
+
+	upload, err := c.videoUploadService.InitiateUpload(matchID, userID, &input)
+	if err != nil {
+		c.respondUploadError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusCreated, upload, constants.MsgVideoUploadInitiated)
+}
+
+// CompleteVideoUpload handles POST /api/admin/matches/:id/video-uploads/:upload_id/complete
+func (c *VideoUploadController) CompleteVideoUpload(ctx *gin.Context) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return
+	}
+
+	uploadID, err := uuid.Parse(ctx.Param("upload_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUploadID)
+		return
+	}
+
+	var input dto.CompleteVideoUploadInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidInput)
+		return
+	}
+
+	result, err := c.videoUploadService.CompleteUpload(matchID, uploadID, &input)
+	if err != nil {
+		c.respondUploadError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, result, constants.MsgVideoUploadCompleted)
+}
+
+// AbortVideoUpload handles DELETE /api/admin/matches/:id/video-uploads/:upload_id
+func (c *VideoUploadController) AbortVideoUpload(ctx *gin.Context) {
+	matchID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidMatchID)
+		return
+	}
+
+	uploadID, err := uuid.Parse(ctx.Param("upload_id"))
+	if err != nil {
+		httpPkg.RespondError(ctx, http.StatusBadRequest, constants.ErrInvalidUploadID)
+		return
+	}
+
+	if err := c.videoUploadService.AbortUpload(matchID, uploadID); err != nil {
+		c.respondUploadError(ctx, err)
+		return
+	}
+
+	httpPkg.RespondSuccess(ctx, http.StatusOK, nil, constants.MsgVideoUploadAborted)
+}
+
+// respondUploadError maps service errors to HTTP status codes
+func (c *VideoUploadController) respondUploadError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case constants.ErrMatchNotFound, constants.ErrSeasonNotFound, constants.ErrVideoUploadNotFound:
+		httpPkg.RespondError(ctx, http.StatusNotFound, err.Error())
+	case constants.ErrVideoUploadNotPending, constants.ErrVideoUploadExpired:
+		httpPkg.RespondError(ctx, http.StatusConflict, err.Error())
+	case constants.ErrInvalidVideoType, constants.ErrVideoTooLarge,
+		constants.ErrVideoSizeMismatch, constants.ErrVideoObjectMissing, constants.ErrInvalidVideoAngle,
+		constants.ErrFileContentMismatch:
+		httpPkg.RespondError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		httpPkg.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	}
+}
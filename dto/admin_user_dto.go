@@ -7,15 +7,19 @@ import (
 )
 
 type AdminUserResponse struct {
-	ID        uuid.UUID         `json:"id"`
-	Username  string            `json:"username"`
-	Email     string            `json:"email"`
-	Gender    models.GenderEnum `json:"gender"`
-	Role      models.RoleEnum   `json:"role"`
-	AvatarURL string            `json:"avatar_url"`
-	CreatedAt string            `json:"created_at"`
-	UpdatedAt string            `json:"updated_at"`
-	DeletedAt string            `json:"deleted_at,omitempty"`
+	ID               uuid.UUID         `json:"id"`
+	Username         string            `json:"username"`
+	Email            string            `json:"email"`
+	Gender           models.GenderEnum `json:"gender"`
+	Role             models.RoleEnum   `json:"role"`
+	AvatarURL        string            `json:"avatar_url"`
+	AvatarURLs       map[string]string `json:"avatar_urls,omitempty"`
+	EmailVerified    bool              `json:"email_verified"`
+	EmailVerifiedAt  string            `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled bool              `json:"two_factor_enabled"`
+	CreatedAt        string            `json:"created_at"`
+	UpdatedAt        string            `json:"updated_at"`
+	DeletedAt        string            `json:"deleted_at,omitempty"`
 }
 
 type AdminUpdateUserInput struct {
@@ -24,3 +28,7 @@ type AdminUpdateUserInput struct {
 	Gender   models.GenderEnum `json:"gender" binding:"omitempty"`
 	Role     models.RoleEnum   `json:"role" binding:"omitempty"`
 }
+
+type UpdateEmailVerificationInput struct {
+	Verified *bool `json:"verified" binding:"required"`
+}
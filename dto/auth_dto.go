@@ -5,6 +5,7 @@ type RegisterInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 	Gender   string `json:"gender" binding:"required,oneof=male female other"`
+	Locale   string `json:"locale" binding:"omitempty"`
 }
 
 type LoginInput struct {
@@ -21,6 +22,42 @@ type ResetPasswordInput struct {
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type UnlockAccountInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// LoginResult is either a token pair or, for accounts with two-factor authentication,
+// a challenge to complete with POST /login/2fa
+type LoginResult struct {
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
 type LoginResponse struct {
 	Token string `json:"token"`
 }
+
+type OIDCProviderResponse struct {
+	Name string `json:"name"`
+}
+
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackInput carries the parameters the provider appended to the redirect URL
+type OIDCCallbackInput struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
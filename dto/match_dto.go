@@ -25,25 +25,36 @@ type UpdateMatchInput struct {
 }
 
 type MatchResponse struct {
-	ID             uuid.UUID         `json:"id"`
-	SeasonID       uuid.UUID         `json:"season_id"`
-	SeasonName     string            `json:"season_name"`
-	HomeTeamID     uuid.UUID         `json:"home_team_id"`
-	HomeTeamName   string            `json:"home_team_name"`
-	AwayTeamID     uuid.UUID         `json:"away_team_id"`
-	AwayTeamName   string            `json:"away_team_name"`
-	Round          models.RoundEnum  `json:"round"`
-	Location       string            `json:"location"`
-	VideoURL       string            `json:"video_url"`
-	VideoQualities map[string]string `json:"video_urls"`
-	ThumbnailURL   string            `json:"thumbnail_url"`
-	ScoutJSON      string            `json:"scout_json_url"`
-	JsonData       interface{}       `json:"json_data"`
+	ID             uuid.UUID            `json:"id"`
+	SeasonID       uuid.UUID            `json:"season_id"`
+	SeasonName     string               `json:"season_name"`
+	HomeTeamID     uuid.UUID            `json:"home_team_id"`
+	HomeTeamName   string               `json:"home_team_name"`
+	AwayTeamID     uuid.UUID            `json:"away_team_id"`
+	AwayTeamName   string               `json:"away_team_name"`
+	Round          models.RoundEnum     `json:"round"`
+	Location       string               `json:"location"`
+	VideoURL       string               `json:"video_url"`
+	VideoQualities map[string]string    `json:"video_urls"`
+	Videos         []MatchVideoResponse `json:"videos"`
+	ThumbnailURL   string               `json:"thumbnail_url"`
+	ScoutJSON      string               `json:"scout_json_url"`
+	JsonData       interface{}          `json:"json_data"`
 	// JsonData     map[string]interface{} `json:"json_data"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type VideoMetadataResponse struct {
+	DurationMs int64   `json:"duration_ms"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FrameRate  float64 `json:"frame_rate"`
+	VideoCodec string  `json:"video_codec"`
+	AudioCodec string  `json:"audio_codec,omitempty"`
+	FileSize   int64   `json:"file_size"`
+}
+
 type MatchListResponse struct {
 	ID           uuid.UUID        `json:"id"`
 	SeasonID     uuid.UUID        `json:"season_id"`
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateMatchVideoInput struct {
+	Angle       *string `json:"angle" binding:"omitempty,min=1,max=50"`
+	OffsetMs    *int64  `json:"offset_ms" binding:"omitempty"`
+	IsReference *bool   `json:"is_reference" binding:"omitempty"`
+}
+
+type MatchVideoResponse struct {
+	ID             uuid.UUID               `json:"id"`
+	MatchID        uuid.UUID               `json:"match_id"`
+	Angle          string                  `json:"angle"`
+	IsReference    bool                    `json:"is_reference"`
+	OffsetMs       int64                   `json:"offset_ms"`
+	VideoURL       string                  `json:"video_url"`
+	VideoQualities map[string]string       `json:"video_urls"` // only renditions that exist
+	ManifestURL    string                  `json:"manifest_url,omitempty"`
+	Status         string                  `json:"status"`
+	Error          string                  `json:"error,omitempty"`
+	Metadata       *VideoMetadataResponse  `json:"metadata,omitempty"`
+	ThumbnailURL   string                  `json:"thumbnail_url"`
+	StoryboardURL  string                  `json:"storyboard_url,omitempty"`
+	SpriteURLs     []string                `json:"sprite_urls,omitempty"`
+	Condensed      *CondensedVideoResponse `json:"condensed,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+type CondensedVideoResponse struct {
+	Status        string `json:"status"` // pending, processing, completed, failed
+	Error         string `json:"error,omitempty"`
+	URL           string `json:"url,omitempty"`
+	ChaptersURL   string `json:"chapters_url,omitempty"`    // WebVTT chapter track, one cue per rally
+	ChapterMapURL string `json:"chapter_map_url,omitempty"` // JSON mapping condensed time to original time
+}
+
+type MatchPlaybackResponse struct {
+	MatchID      uuid.UUID            `json:"match_id"`
+	Videos       []MatchVideoResponse `json:"videos"`
+	ScoutJSONURL string               `json:"scout_json_url,omitempty"`
+	ExpiresAt    time.Time            `json:"expires_at"` // signed URLs and cookies stop working after this
+	CookieFolder string               `json:"-"`          // video folder the signed cookies are scoped to
+}
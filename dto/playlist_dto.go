@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PlaylistItemInput struct {
+	MatchVideoID uuid.UUID `json:"match_video_id" binding:"required"`
+	StartMs      int64     `json:"start_ms" binding:"min=0"`
+	EndMs        int64     `json:"end_ms" binding:"required,gt=0"`
+	Comment      string    `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type CreatePlaylistInput struct {
+	Title       string              `json:"title" binding:"required,max=150"`
+	Description string              `json:"description" binding:"omitempty,max=2000"`
+	Items       []PlaylistItemInput `json:"items" binding:"omitempty,max=500,dive"`
+}
+
+type UpdatePlaylistInput struct {
+	Title       *string `json:"title" binding:"omitempty,min=1,max=150"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+}
+
+type UpdatePlaylistItemInput struct {
+	StartMs *int64  `json:"start_ms" binding:"omitempty,min=0"`
+	EndMs   *int64  `json:"end_ms" binding:"omitempty,gt=0"`
+	Comment *string `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type ReorderPlaylistItemsInput struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required,min=1"`
+}
+
+type SharePlaylistInput struct {
+	UserIDs    []uuid.UUID `json:"user_ids" binding:"omitempty,max=500"`
+	AllPlayers bool        `json:"all_players"`
+}
+
+type RecordPlaylistViewInput struct {
+	WatchedMs int64 `json:"watched_ms" binding:"min=0"`
+	Completed bool  `json:"completed"`
+}
+
+type PlaylistItemResponse struct {
+	ID           uuid.UUID         `json:"id"`
+	MatchID      uuid.UUID         `json:"match_id"`
+	MatchVideoID uuid.UUID         `json:"match_video_id"`
+	Angle        string            `json:"angle"`
+	Position     int               `json:"position"`
+	StartMs      int64             `json:"start_ms"`
+	EndMs        int64             `json:"end_ms"`
+	Comment      string            `json:"comment"`
+	PlaybackURLs map[string]string `json:"playback_urls"` // rendition URLs with a #t=start,end media fragment
+	ThumbnailURL string            `json:"thumbnail_url"`
+}
+
+type PlaylistResponse struct {
+	ID                uuid.UUID              `json:"id"`
+	OwnerID           uuid.UUID              `json:"owner_id"`
+	Title             string                 `json:"title"`
+	Description       string                 `json:"description"`
+	SharedWithPlayers bool                   `json:"shared_with_players"`
+	SharedWith        []uuid.UUID            `json:"shared_with,omitempty"` // only returned to the owner
+	ItemCount         int                    `json:"item_count"`
+	TotalDurationMs   int64                  `json:"total_duration_ms"`
+	Items             []PlaylistItemResponse `json:"items,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+type PlaylistViewResponse struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Username       string    `json:"username"`
+	PlaylistItemID uuid.UUID `json:"playlist_item_id"`
+	WatchedMs      int64     `json:"watched_ms"`
+	Completed      bool      `json:"completed"`
+	ViewCount      int       `json:"view_count"`
+	LastViewedAt   time.Time `json:"last_viewed_at"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateRallyInput struct {
+	StartMs int64 `json:"start_ms" binding:"min=0"`
+	EndMs   int64 `json:"end_ms" binding:"min=0"`
+}
+
+type UpdateRallyInput struct {
+	StartMs *int64 `json:"start_ms" binding:"omitempty,min=0"`
+	EndMs   *int64 `json:"end_ms" binding:"omitempty,min=0"`
+}
+
+type RallyResponse struct {
+	ID           uuid.UUID              `json:"id"`
+	MatchID      uuid.UUID              `json:"match_id"`
+	MatchVideoID uuid.UUID              `json:"match_video_id"`
+	Number       int                    `json:"number,omitempty"` // 1-based position in playing order, set in listings
+	StartMs      int64                  `json:"start_ms"`
+	EndMs        int64                  `json:"end_ms"`
+	Source       models.RallySourceEnum `json:"source"`
+	Confidence   float64                `json:"confidence"`
+	Reviewed     bool                   `json:"reviewed"`
+	UpdatedByID  *uuid.UUID             `json:"updated_by_id,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+type MatchVideoRalliesResponse struct {
+	MatchVideoID    uuid.UUID       `json:"match_video_id"`
+	DetectionStatus string          `json:"detection_status,omitempty"` // pending, processing, completed, failed
+	DetectionError  string          `json:"detection_error,omitempty"`
+	Rallies         []RallyResponse `json:"rallies"`
+}
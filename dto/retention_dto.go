@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateRetentionPolicyInput struct {
+	RawAction           string `json:"raw_action" binding:"required,oneof=keep archive delete"`
+	RawGraceDays        int    `json:"raw_grace_days" binding:"min=0,max=3650"`
+	ArchiveStorageClass string `json:"archive_storage_class" binding:"omitempty,oneof=STANDARD_IA ONEZONE_IA INTELLIGENT_TIERING GLACIER_IR GLACIER DEEP_ARCHIVE"`
+	FailedRawFlagDays   int    `json:"failed_raw_flag_days" binding:"min=0,max=365"`
+}
+
+type RetentionPolicyResponse struct {
+	SeasonID            uuid.UUID  `json:"season_id"`
+	RawAction           string     `json:"raw_action"`
+	RawGraceDays        int        `json:"raw_grace_days"`
+	ArchiveStorageClass string     `json:"archive_storage_class"`
+	FailedRawFlagDays   int        `json:"failed_raw_flag_days"`
+	IsDefault           bool       `json:"is_default"` // no policy stored for the season yet
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+type StorageCategoryResponse struct {
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+type FlaggedRawVideoResponse struct {
+	MatchVideoID uuid.UUID  `json:"match_video_id"`
+	MatchID      uuid.UUID  `json:"match_id"`
+	Angle        string     `json:"angle"`
+	RawKey       string     `json:"raw_key"`
+	Bytes        int64      `json:"bytes"`
+	Reason       string     `json:"reason"`
+	FlaggedAt    *time.Time `json:"flagged_at"`
+}
+
+type StorageReportResponse struct {
+	SeasonID    *uuid.UUID                         `json:"season_id,omitempty"`
+	Categories  map[string]StorageCategoryResponse `json:"categories"`  // raw_stored, raw_archived, raw_flagged, raw_deleted and one per artifact kind
+	TotalBytes  int64                              `json:"total_bytes"` // bytes still held in storage
+	FlaggedRaw  []FlaggedRawVideoResponse          `json:"flagged_raw"`
+	GeneratedAt time.Time                          `json:"generated_at"`
+}
+
+type OrphanPrefixResponse struct {
+	Objects       int   `json:"objects"`
+	Bytes         int64 `json:"bytes"`
+	Orphans       int   `json:"orphans"`
+	OrphanBytes   int64 `json:"orphan_bytes"`
+	EligibleNow   int   `json:"eligible_now"` // past their grace period, collected by the next run
+	EligibleBytes int64 `json:"eligible_bytes"`
+}
+
+type OrphanedObjectResponse struct {
+	Key          string     `json:"key"`
+	Bytes        int64      `json:"bytes"`
+	LastModified time.Time  `json:"last_modified"`
+	FirstSeenAt  *time.Time `json:"first_seen_at"` // null until a collection run has seen the object
+	EligibleAt   time.Time  `json:"eligible_at"`
+}
+
+type OrphanReportResponse struct {
+	Action      string                          `json:"action"` // quarantine or delete
+	GraceDays   int                             `json:"grace_days"`
+	Prefixes    map[string]OrphanPrefixResponse `json:"prefixes"`
+	Orphans     []OrphanedObjectResponse        `json:"orphans"` // soonest eligible first, capped at OrphanReportLimit
+	TotalCount  int                             `json:"total_count"`
+	Truncated   bool                            `json:"truncated"`
+	GeneratedAt time.Time                       `json:"generated_at"`
+}
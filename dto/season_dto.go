@@ -40,6 +40,7 @@ type SeasonResponse struct {
 	StartDate  *time.Time            `json:"start_date,omitempty"`
 	EndDate    *time.Time            `json:"end_date,omitempty"`
 	LogoURL    string                `json:"logo_url"`
+	LogoURLs   map[string]string     `json:"logo_urls,omitempty"`
 	CreatedAt  time.Time             `json:"created_at"`
 	UpdatedAt  time.Time             `json:"updated_at"`
 }
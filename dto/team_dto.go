@@ -27,6 +27,7 @@ type TeamResponse struct {
 	Country   models.CountryEnum `json:"country"`
 	SeasonID  uuid.UUID          `json:"season_id"`
 	LogoURL   string             `json:"logo_url"`
+	LogoURLs  map[string]string  `json:"logo_urls,omitempty"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool   `json:"enabled"`
+	Required          bool   `json:"required"` // the user's role requires 2FA
+	EnabledAt         string `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int64  `json:"recovery_codes_left"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCode     string `json:"qr_code"` // PNG data URI of OTPAuthURL
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+type DisableTwoFactorInput struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
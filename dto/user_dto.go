@@ -3,22 +3,27 @@ package dto
 import "github.com/google/uuid"
 
 type UserResponse struct {
-	ID               uuid.UUID `json:"id"`
-	Username         string    `json:"username"`
-	Email            string    `json:"email"`
-	Gender           string    `json:"gender"`
-	AvatarURL        string    `json:"avatar_url"`
-	Role             string    `json:"role"`
-	Permissions      []string  `json:"permissions"`
-	ExtraPermissions []string  `json:"extra_permissions"`
-	CreatedAt        string    `json:"created_at"`
-	UpdatedAt        string    `json:"updated_at"`
-	DeletedAt        string    `json:"deleted_at,omitempty"`
+	ID               uuid.UUID         `json:"id"`
+	Username         string            `json:"username"`
+	Email            string            `json:"email"`
+	Gender           string            `json:"gender"`
+	AvatarURL        string            `json:"avatar_url"`
+	AvatarURLs       map[string]string `json:"avatar_urls,omitempty"`
+	Role             string            `json:"role"`
+	Permissions      []string          `json:"permissions"`
+	ExtraPermissions []string          `json:"extra_permissions"`
+	EmailVerified    bool              `json:"email_verified"`
+	TwoFactorEnabled bool              `json:"two_factor_enabled"`
+	Locale           string            `json:"locale,omitempty"`
+	CreatedAt        string            `json:"created_at"`
+	UpdatedAt        string            `json:"updated_at"`
+	DeletedAt        string            `json:"deleted_at,omitempty"`
 }
 
 type UpdateUserInput struct {
 	Username string `json:"username" binding:"omitempty,min=3,max=20"`
 	Email    string `json:"email" binding:"omitempty,email"`
+	Locale   string `json:"locale" binding:"omitempty"`
 }
 
 type ChangePasswordInput struct {
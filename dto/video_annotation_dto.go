@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnnotationPointInput struct {
+	X float64 `json:"x" binding:"min=0,max=1"`
+	Y float64 `json:"y" binding:"min=0,max=1"`
+}
+
+type AnnotationShapeInput struct {
+	Type   models.AnnotationShapeEnum `json:"type" binding:"required"`
+	Points []AnnotationPointInput     `json:"points" binding:"required,min=1,max=2,dive"`
+	Radius float64                    `json:"radius" binding:"omitempty,gt=0,lte=1"`
+	Color  string                     `json:"color" binding:"omitempty,max=20"`
+}
+
+type CreateAnnotationInput struct {
+	StartMs    int64                           `json:"start_ms" binding:"min=0"`
+	EndMs      int64                           `json:"end_ms" binding:"min=0"`
+	Note       string                          `json:"note" binding:"omitempty,max=2000"`
+	Tags       []string                        `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Shapes     []AnnotationShapeInput          `json:"shapes" binding:"omitempty,max=20,dive"`
+	Visibility models.AnnotationVisibilityEnum `json:"visibility" binding:"omitempty"` // defaults to team
+}
+
+type UpdateAnnotationInput struct {
+	StartMs    *int64                           `json:"start_ms" binding:"omitempty,min=0"`
+	EndMs      *int64                           `json:"end_ms" binding:"omitempty,min=0"`
+	Note       *string                          `json:"note" binding:"omitempty,max=2000"`
+	Tags       *[]string                        `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Shapes     *[]AnnotationShapeInput          `json:"shapes" binding:"omitempty,max=20,dive"`
+	Visibility *models.AnnotationVisibilityEnum `json:"visibility" binding:"omitempty"`
+}
+
+type AnnotationFilterInput struct {
+	AuthorID   string   `form:"author_id"`
+	Visibility string   `form:"visibility"`
+	Tags       []string `form:"tag"`
+	FromMs     *int64   `form:"from_ms" binding:"omitempty,min=0"`
+	ToMs       *int64   `form:"to_ms" binding:"omitempty,min=0"`
+}
+
+type AnnotationPointResponse struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type AnnotationShapeResponse struct {
+	Type   models.AnnotationShapeEnum `json:"type"`
+	Points []AnnotationPointResponse  `json:"points"`
+	Radius float64                    `json:"radius,omitempty"`
+	Color  string                     `json:"color,omitempty"`
+}
+
+type AnnotationResponse struct {
+	ID           uuid.UUID                       `json:"id"`
+	MatchID      uuid.UUID                       `json:"match_id"`
+	MatchVideoID uuid.UUID                       `json:"match_video_id"`
+	AuthorID     uuid.UUID                       `json:"author_id"`
+	StartMs      int64                           `json:"start_ms"`
+	EndMs        int64                           `json:"end_ms"`
+	Note         string                          `json:"note"`
+	Tags         []string                        `json:"tags"`
+	Shapes       []AnnotationShapeResponse       `json:"shapes"`
+	Visibility   models.AnnotationVisibilityEnum `json:"visibility"`
+	CreatedAt    time.Time                       `json:"created_at"`
+	UpdatedAt    time.Time                       `json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InitiateVideoUploadInput struct {
+	FileName    string `json:"file_name" binding:"required"`
+	FileSize    int64  `json:"file_size" binding:"required,gt=0"`
+	ContentType string `json:"content_type" binding:"required"`
+	Angle       string `json:"angle" binding:"omitempty,max=50"` // defaults to "main"
+}
+
+type PresignedPartResponse struct {
+	PartNumber int64  `json:"part_number"`
+	URL        string `json:"url"`
+}
+
+type VideoUploadResponse struct {
+	UploadID  uuid.UUID               `json:"upload_id"`
+	MatchID   uuid.UUID               `json:"match_id"`
+	Angle     string                  `json:"angle"`
+	ObjectKey string                  `json:"object_key"`
+	PartSize  int64                   `json:"part_size"`
+	Parts     []PresignedPartResponse `json:"parts"`
+	ExpiresAt time.Time               `json:"expires_at"`
+}
+
+type CompletedPartInput struct {
+	PartNumber int64  `json:"part_number" binding:"required,gt=0"`
+	ETag       string `json:"etag" binding:"required"`
+}
+
+type CompleteVideoUploadInput struct {
+	Parts []CompletedPartInput `json:"parts" binding:"required,min=1,dive"`
+}
+
+type CompletedVideoUploadResponse struct {
+	UploadID     uuid.UUID `json:"upload_id"`
+	MatchID      uuid.UUID `json:"match_id"`
+	MatchVideoID uuid.UUID `json:"match_video_id"`
+	Angle        string    `json:"angle"`
+	VideoURL     string    `json:"video_url"`
+	Status       string    `json:"status"`
+}
@@ -6,17 +6,26 @@ import (
 	"go-gin-starter/database"
 	"go-gin-starter/middleware"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/cdn"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/di"
 	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/mail"
+	"go-gin-starter/pkg/orphans"
+	storagePkg "go-gin-starter/pkg/storage"
 	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
 	"go-gin-starter/routes"
 	"log"
+	"net/http"
 	"os"
 
 	_ "go-gin-starter/docs" // swagger docs
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -37,8 +46,39 @@ func main() {
 	logger.Init()
 	defer logger.Sync()
 
+	// First-time OIDC users get this role, so a typo must not go unnoticed
+	if !models.IsValidRole(models.RoleEnum(config.OIDCDefaultRole)) {
+		logger.Fatal("Invalid OIDC_DEFAULT_ROLE", zap.String("role", config.OIDCDefaultRole))
+	}
+	for _, provider := range config.OIDCProviders {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			logger.Warn("OIDC provider is missing its issuer or client ID and stays disabled", zap.String("provider", provider.Name))
+		}
+	}
+
+	// Load the CloudFront key used to sign protected video and scout URLs
+	if err := cdn.Init(); err != nil {
+		logger.Fatal("Failed to initialize CloudFront URL signing", zap.Error(err))
+	}
+
+	// Load the keys access tokens are signed and verified with
+	if err := authPkg.InitKeys(); err != nil {
+		logger.Fatal("Failed to initialize JWT signing keys", zap.Error(err))
+	}
+
 	// Connect to the database
 	database.ConnectDB()
+	introducingVerification := !database.DB.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
+	// Sessions stored their refresh tokens in plaintext before tokens were hashed
+	hashed, err := repositories.NewSessionRepository().HashStoredRefreshTokens()
+	if err != nil {
+		logger.Fatal("Failed to hash stored refresh tokens", zap.Error(err))
+	}
+	if hashed > 0 {
+		logger.Info("Stored refresh tokens hashed", zap.Int64("sessions", hashed))
+	}
+
 	if err := database.DB.AutoMigrate(
 		&models.User{},
 		&models.WaitlistEntry{},
@@ -46,11 +86,54 @@ func main() {
 		&models.Team{},
 		&models.Match{},
 		&models.AdminActionLog{},
+		&models.VideoUpload{},
+		&models.MatchVideo{},
+		&models.VideoArtifact{},
+		&models.VideoAnnotation{},
+		&models.Playlist{},
+		&models.PlaylistItem{},
+		&models.PlaylistShare{},
+		&models.PlaylistView{},
+		&models.RetentionPolicy{},
+		&models.RallySegment{},
+		&models.OrphanedObject{},
+		&models.RecoveryCode{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
+		&models.Session{},
+		&models.RotatedRefreshToken{},
 		// &models.UserActionLog{},
 	); err != nil {
 		logger.Fatal("Failed to auto-migrate database", zap.Error(err))
 	}
 
+	// Accounts created before email verification existed count as verified
+	if introducingVerification {
+		verified, err := repositories.NewAuthRepository().BackfillEmailVerification()
+		if err != nil {
+			logger.Fatal("Failed to backfill email verification", zap.Error(err))
+		}
+		logger.Info("Existing accounts marked as verified", zap.Int64("users", verified))
+	}
+
+	// Refresh tokens stored on users before sessions existed become one session each
+	imported, err := repositories.NewSessionRepository().ImportLegacyRefreshTokens()
+	if err != nil {
+		logger.Fatal("Failed to import legacy refresh tokens", zap.Error(err))
+	}
+	if imported > 0 {
+		logger.Info("Legacy refresh tokens imported as sessions", zap.Int64("sessions", imported))
+	}
+
+	// Reset tokens were stored in plaintext before tokens were hashed
+	resetHashed, err := repositories.NewAuthRepository().HashStoredResetTokens()
+	if err != nil {
+		logger.Fatal("Failed to hash stored reset tokens", zap.Error(err))
+	}
+	if resetHashed > 0 {
+		logger.Info("Stored reset tokens hashed", zap.Int64("users", resetHashed))
+	}
+
 	// Initialize AWS services
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(os.Getenv("AWS_REGION")),
@@ -59,12 +142,31 @@ func main() {
 		logger.Fatal("Failed to create AWS session", zap.Error(err))
 	}
 
-	// Initialize S3 and SQS clients
-	s3Client := s3.New(sess)
+	// Initialize object storage and SQS client
+	store, err := storagePkg.NewStore(sess)
+	if err != nil {
+		logger.Fatal("Failed to create object store", zap.Error(err))
+	}
 	sqsClient := sqs.New(sess)
 
+	// Render every asset URL through the store and convert rows that still hold URLs to keys
+	assets.Init(store)
+	assets.MigrateReferences()
+
+	// Videos stored on matches before camera angles existed become their reference angle
+	video.ImportLegacyVideos(store)
+
+	// Send mail in the background through the configured backend
+	mailer, err := mail.NewMailer()
+	if err != nil {
+		logger.Fatal("Failed to create mailer", zap.Error(err))
+	}
+	outbox := mail.NewOutbox(mailer, constants.MailWorkers, constants.MailQueueSize)
+	mail.Init(outbox)
+	go outbox.Start()
+
 	// Initialize video processor
-	videoProcessor := video.NewVideoProcessor(sess, s3Client, os.Getenv("AWS_BUCKET_NAME"))
+	videoProcessor := video.NewVideoProcessor(store)
 
 	// Initialize video queue manager
 	videoQueue := video.NewQueueManager(
@@ -80,6 +182,27 @@ func main() {
 	// Start video processing worker in a goroutine
 	go videoQueue.StartProcessing()
 
+	// Abort presigned video uploads that were abandoned or expired
+	uploadJanitor := video.NewUploadJanitor(store, constants.VideoUploadCleanupTick)
+	go uploadJanitor.Start()
+
+	// Record artifacts of videos processed before they were tracked in the database
+	go video.BackfillArtifacts(store)
+
+	// Delete or archive raw uploads once their renditions are verified
+	rawRetention := video.NewRawRetention(store, constants.RawRetentionTick)
+	go rawRetention.Start()
+
+	// Quarantine or delete stored objects no row references any more
+	orphanCollector := orphans.NewCollector(store, constants.OrphanGCTick, config.OrphanAction)
+	go orphanCollector.Start()
+
+	// Build the controllers once; both route groups share them
+	container, err := di.NewContainer(store, videoQueue, orphanCollector)
+	if err != nil {
+		logger.Fatal("Failed to create dependency container", zap.Error(err))
+	}
+
 	// Set Gin mode based on environment
 	if gin.Mode() == gin.DebugMode {
 		gin.SetMode(gin.DebugMode)
@@ -110,6 +233,14 @@ func main() {
 	r.GET("/readiness", controllers.ReadinessCheck)
 	r.GET("/liveness", controllers.LivenessCheck)
 
+	// Public keys for services that verify our access tokens themselves
+	r.GET("/.well-known/jwks.json", controllers.JSONWebKeySet)
+
+	// The local storage backend serves its own public and presigned URLs
+	if local, ok := store.(*storagePkg.LocalStore); ok {
+		r.Any("/storage/*key", gin.WrapH(http.StripPrefix("/storage", local)))
+	}
+
 	// Setup API versioning - V1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -117,11 +248,11 @@ func main() {
 		v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 		// Setup all API routes under versioned path
-		routes.SetupRoutes(v1)
+		routes.SetupRoutes(v1, container)
 	}
 
 	// Keep legacy routes for backward compatibility
-	routes.SetupRoutes(r.Group("/api"))
+	routes.SetupRoutes(r.Group("/api"), container)
 
 	// Start the server on the specified port
 	port := config.GetEnvWithDefault("PORT", "8080")
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequireAccountSetup limits users who have not confirmed their email address, or whose role
+// requires two-factor authentication they have not enabled, to the routes registered before
+// it: their own profile, resending the verification link and the 2FA settings. Completed
+// setups are cached with the token version checked by JWTAuth, so most requests cost no query.
+func RequireAccountSetup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.MustGet("user_id").(uuid.UUID)
+		if !ok {
+			httpPkg.RespondError(c, http.StatusInternalServerError, constants.ErrInvalidUserID)
+			c.Abort()
+			return
+		}
+
+		if err := services.CheckAccountSetup(userID); err != nil {
+			status := http.StatusForbidden
+			if err.Error() == constants.ErrUnauthorized {
+				status = http.StatusUnauthorized
+			}
+			httpPkg.RespondError(c, status, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -7,8 +7,10 @@ import (
 	authPkg "go-gin-starter/pkg/auth"
 	"go-gin-starter/pkg/constants"
 	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func JWTAuth() gin.HandlerFunc {
@@ -29,8 +31,18 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// Access tokens die with the session they were issued for, and when the user's token
+		// version is bumped by a password, role or permission change or by deletion
+		if claims.SessionID == uuid.Nil || !services.IsSessionActive(claims.SessionID) ||
+			!services.IsTokenVersionCurrent(claims.UserID, claims.TokenVersion) {
+			httpPkg.RespondError(c, http.StatusUnauthorized, constants.ErrInvalidToken)
+			c.Abort()
+			return
+		}
+
 		// Store UUID user ID in context directly
 		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
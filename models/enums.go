@@ -25,6 +25,9 @@ var RolePermissions = map[RoleEnum][]string{
 		"manage_matches",
 		"upload_video",
 		"upload_scout",
+		"annotate_video",
+		"manage_playlists",
+		"edit_rallies",
 		"manage_season",
 		"manage_waitlist",
 		"manage_roles",
@@ -72,16 +75,22 @@ var RolePermissions = map[RoleEnum][]string{
 		"upload_scout",
 		"view_match",
 		"view_scout_data",
+		"annotate_video",
+		"manage_playlists",
+		"edit_rallies",
 	},
 	RoleAssistantCoach: {
 		"view_team",
 		"view_match",
 		"view_scout_data",
+		"annotate_video",
+		"manage_playlists",
 	},
 	RoleScoutman: {
 		"upload_video",
 		"upload_scout",
 		"view_match",
+		"edit_rallies",
 	},
 	RolePlayer: {
 		"view_own_stats",
@@ -152,6 +161,81 @@ const (
 	RoundSuperFinal        RoundEnum = "Super Final"
 )
 
+// --- Video Upload Status ---
+type VideoUploadStatusEnum string
+
+const (
+	VideoUploadPending   VideoUploadStatusEnum = "pending"
+	VideoUploadCompleted VideoUploadStatusEnum = "completed"
+	VideoUploadFailed    VideoUploadStatusEnum = "failed"
+	VideoUploadAborted   VideoUploadStatusEnum = "aborted"
+	VideoUploadExpired   VideoUploadStatusEnum = "expired"
+)
+
+// --- Video Artifact ---
+type VideoArtifactKindEnum string
+
+const (
+	ArtifactRendition  VideoArtifactKindEnum = "rendition"
+	ArtifactThumbnail  VideoArtifactKindEnum = "thumbnail"
+	ArtifactStoryboard VideoArtifactKindEnum = "storyboard"
+	ArtifactSprite     VideoArtifactKindEnum = "sprite"
+	ArtifactManifest   VideoArtifactKindEnum = "manifest"
+	ArtifactCondensed  VideoArtifactKindEnum = "condensed" // rallies-only rendition
+	ArtifactChapters   VideoArtifactKindEnum = "chapters"  // condensed to source time mapping
+)
+
+type VideoArtifactStatusEnum string
+
+const (
+	ArtifactReady  VideoArtifactStatusEnum = "ready"
+	ArtifactFailed VideoArtifactStatusEnum = "failed"
+)
+
+// --- Raw Video Retention ---
+type RawRetentionActionEnum string
+
+const (
+	RawRetentionKeep    RawRetentionActionEnum = "keep"
+	RawRetentionArchive RawRetentionActionEnum = "archive" // transition to a cold storage class
+	RawRetentionDelete  RawRetentionActionEnum = "delete"
+)
+
+type RawFileStatusEnum string
+
+const (
+	RawFileStored   RawFileStatusEnum = "stored"
+	RawFileArchived RawFileStatusEnum = "archived"
+	RawFileDeleted  RawFileStatusEnum = "deleted"
+	RawFileFlagged  RawFileStatusEnum = "flagged" // needs a human: processing failed or renditions are incomplete
+)
+
+// --- Rally Segment Source ---
+type RallySourceEnum string
+
+const (
+	RallyDetected RallySourceEnum = "detected" // proposed by audio analysis
+	RallyManual   RallySourceEnum = "manual"   // added by hand
+)
+
+// --- Annotation Visibility ---
+type AnnotationVisibilityEnum string
+
+const (
+	AnnotationPrivate       AnnotationVisibilityEnum = "private"        // author only
+	AnnotationCoachingStaff AnnotationVisibilityEnum = "coaching_staff" // users allowed to annotate
+	AnnotationTeam          AnnotationVisibilityEnum = "team"           // everyone who can watch the match
+)
+
+// --- Annotation Shape ---
+type AnnotationShapeEnum string
+
+const (
+	AnnotationShapeArrow  AnnotationShapeEnum = "arrow"
+	AnnotationShapeCircle AnnotationShapeEnum = "circle"
+	AnnotationShapeLine   AnnotationShapeEnum = "line"
+)
+
 // --- Validations ---
 func IsValidRole(r RoleEnum) bool {
 	switch r {
@@ -200,3 +284,30 @@ func IsValidSeasonName(s SeasonNameEnum) bool {
 		return false
 	}
 }
+
+func IsValidAnnotationVisibility(v AnnotationVisibilityEnum) bool {
+	switch v {
+	case AnnotationPrivate, AnnotationCoachingStaff, AnnotationTeam:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidAnnotationShape(t AnnotationShapeEnum) bool {
+	switch t {
+	case AnnotationShapeArrow, AnnotationShapeCircle, AnnotationShapeLine:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidRawRetentionAction(a RawRetentionActionEnum) bool {
+	switch a {
+	case RawRetentionKeep, RawRetentionArchive, RawRetentionDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -18,9 +18,9 @@ type Match struct {
 	Gender      GenderEnum `gorm:"type:varchar(10);not null"`
 
 	Location     string `gorm:"type:varchar(100)"`
-	VideoURL     string `gorm:"type:text"` // optional
-	ThumbnailURL string `gorm:"type:text"` // optional
-	ScoutJSON    string `gorm:"type:text"` // optional
+	VideoURL     string `gorm:"type:text"` // optional, object key mirroring the reference angle
+	ThumbnailURL string `gorm:"type:text"` // optional, object key mirroring the reference angle
+	ScoutJSON    string `gorm:"type:text"` // optional, object key of the parsed scout file
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MatchVideo is one camera angle of a match, processed independently of the others
+type MatchVideo struct {
+	ID      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MatchID uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	Angle       string `gorm:"type:varchar(50);not null"` // e.g. main, end_line, side
+	IsReference bool   `gorm:"not null;default:false"`
+	OffsetMs    int64  `gorm:"not null;default:0"` // position in this angle = reference position + OffsetMs
+
+	// Object keys; the *URL fields are named after their columns, responses render them through pkg/assets
+	RawKey        string      `gorm:"type:text;not null"`
+	OutputKey     string      `gorm:"type:text;not null"`
+	VideoURL      string      `gorm:"type:text"`
+	ThumbnailURL  string      `gorm:"type:text"`
+	StoryboardURL string      `gorm:"type:text"`               // WebVTT track for scrubbing previews
+	SpriteURLs    StringArray `gorm:"type:jsonb;default:null"` // sprite sheets referenced by the storyboard
+
+	Status   string        `gorm:"type:varchar(20)"` // pending, processing, completed, failed, rejected
+	Error    string        `gorm:"type:text"`
+	Metadata VideoMetadata `gorm:"embedded;embeddedPrefix:video_"`
+
+	// Audio-based rally detection, run after processing or on request
+	RallyStatus string `gorm:"type:varchar(20)"` // pending, processing, completed, failed
+	RallyError  string `gorm:"type:text"`
+
+	// Rallies-only rendition, cut from scout timecodes or rally segments
+	CondensedStatus string `gorm:"type:varchar(20)"` // pending, processing, completed, failed
+	CondensedError  string `gorm:"type:text"`
+
+	// Lifecycle of the raw upload, managed by the retention job
+	RawStatus       RawFileStatusEnum `gorm:"type:varchar(20);not null;default:'stored'"`
+	RawStatusReason string            `gorm:"type:text"`
+	RawStatusAt     *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
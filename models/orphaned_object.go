@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// OrphanedObject is a stored object no database row references any more. The orphan
+// collector records when it first noticed the object so the grace period has a start.
+type OrphanedObject struct {
+	Key          string    `gorm:"type:text;primaryKey"`
+	Size         int64     `gorm:"not null;default:0"`
+	LastModified time.Time `gorm:"not null"`
+	FirstSeenAt  time.Time `gorm:"not null;index"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
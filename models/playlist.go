@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Playlist is a coach-curated, ordered list of match video clips
+type Playlist struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OwnerID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Title       string    `gorm:"type:varchar(150);not null"`
+	Description string    `gorm:"type:text"`
+
+	SharedWithPlayers bool `gorm:"not null;default:false"` // visible to every user with the player role
+
+	Items  []PlaylistItem  `gorm:"foreignKey:PlaylistID"`
+	Shares []PlaylistShare `gorm:"foreignKey:PlaylistID"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// PlaylistItem is one segment of a match video inside a playlist
+type PlaylistItem struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PlaylistID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	MatchID      uuid.UUID `gorm:"type:uuid;not null"`
+	MatchVideoID uuid.UUID `gorm:"type:uuid;not null"`
+	Position     int       `gorm:"not null"`
+
+	StartMs int64  `gorm:"not null"`
+	EndMs   int64  `gorm:"not null"`
+	Comment string `gorm:"type:text"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PlaylistShare grants one user access to a playlist
+type PlaylistShare struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PlaylistID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_share_user"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_share_user;index"`
+
+	CreatedAt time.Time
+}
+
+// PlaylistView tracks how much of a playlist item a user has watched
+type PlaylistView struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PlaylistID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	PlaylistItemID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_view_item_user"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_playlist_view_item_user"`
+
+	WatchedMs    int64     `gorm:"not null;default:0"`
+	Completed    bool      `gorm:"not null;default:false"`
+	ViewCount    int       `gorm:"not null;default:0"`
+	LastViewedAt time.Time `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RallySegment is one rally of a match video, proposed by audio analysis or entered by hand
+type RallySegment struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MatchID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	MatchVideoID uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	StartMs    int64           `gorm:"not null"`
+	EndMs      int64           `gorm:"not null"`
+	Source     RallySourceEnum `gorm:"type:varchar(20);not null"`
+	Confidence float64         `gorm:"not null;default:0"`     // 0..1 for detected segments
+	Reviewed   bool            `gorm:"not null;default:false"` // confirmed or corrected by hand; kept when detection runs again
+
+	UpdatedByID *uuid.UUID `gorm:"type:uuid"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
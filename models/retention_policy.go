@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go-gin-starter/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy controls what happens to the raw uploads of a season's matches
+type RetentionPolicy struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	SeasonID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+
+	RawAction           RawRetentionActionEnum `gorm:"type:varchar(20);not null"`
+	RawGraceDays        int                    `gorm:"not null"`           // days after successful processing before RawAction applies
+	ArchiveStorageClass string                 `gorm:"type:varchar(30)"`   // S3 storage class used by archive, e.g. GLACIER, DEEP_ARCHIVE
+	FailedRawFlagDays   int                    `gorm:"not null;default:3"` // days after a failed job before its raw file is flagged
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DefaultRetentionPolicy returns the policy applied to seasons that have none stored
+func DefaultRetentionPolicy(seasonID uuid.UUID) *RetentionPolicy {
+	return &RetentionPolicy{
+		SeasonID:            seasonID,
+		RawAction:           RawRetentionActionEnum(constants.DefaultRawRetentionAction),
+		RawGraceDays:        constants.DefaultRawGraceDays,
+		ArchiveStorageClass: constants.DefaultArchiveStorageClass,
+		FailedRawFlagDays:   constants.DefaultFailedRawFlagDays,
+	}
+}
@@ -19,7 +19,8 @@ type Season struct {
 	StartDate *time.Time `gorm:"type:timestamp"`
 	EndDate   *time.Time `gorm:"type:timestamp"`
 
-	Logo string `gorm:"type:varchar(255);default:'defaults/default-season-logo.png'"`
+	Logo         string        `gorm:"type:varchar(255);default:'defaults/default-season-logo.png'"`
+	LogoVariants ImageVariants `gorm:"type:jsonb;default:null"` // processed renditions of uploaded logos
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one signed-in device and the family of refresh tokens issued to it. Its
+// refresh token is replaced on every refresh; access tokens carry the session ID, so they
+// stop working when the session is revoked.
+type Session struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID           uuid.UUID `gorm:"type:uuid;not null;index"`
+	RefreshTokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 of the current token
+	UserAgent        string    `gorm:"type:varchar(255)"`
+	IPAddress        string    `gorm:"type:varchar(45)"`
+	ExpiresAt        time.Time `gorm:"type:timestamp;not null"`
+	LastUsedAt       time.Time `gorm:"type:timestamp;not null"`
+	CreatedAt        time.Time
+}
+
+// RotatedRefreshToken is a refresh token that was already exchanged. Presenting it again
+// means it was copied, so the session it belonged to is revoked.
+type RotatedRefreshToken struct {
+	TokenHash string    `gorm:"type:varchar(64);primaryKey"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	RotatedAt time.Time `gorm:"type:timestamp;not null"`
+	ExpiresAt time.Time `gorm:"type:timestamp;not null"`
+}
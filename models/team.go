@@ -17,6 +17,8 @@ type Team struct {
 	SeasonID uuid.UUID   `gorm:"type:uuid;not null"` // FK to Season
 	Logo     string      `gorm:"type:varchar(255);default:'defaults/default-team.png'"`
 
+	LogoVariants ImageVariants `gorm:"type:jsonb;default:null"` // processed renditions of uploaded logos
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
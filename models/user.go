@@ -46,12 +46,28 @@ type User struct {
 	Avatar               string      `gorm:"default:'default_avatar.png'"`
 	Gender               GenderEnum  `gorm:"type:varchar(10)"`
 	Role                 RoleEnum    `gorm:"type:varchar(20);default:'player'"`
+	Locale               string      `gorm:"type:varchar(10)"` // language of emails; empty for the default
 	ExtraPermissions     StringArray `gorm:"type:jsonb;default:null" json:"extra_permissions"`
-	ResetPasswordToken   *string     `gorm:"type:text"`
+	ResetPasswordToken   *string     `gorm:"type:text"` // SHA-256 of the emailed token
 	ResetPasswordExpires *time.Time  `gorm:"type:timestamp"`
 
-	RefreshToken       *string    `gorm:"type:text"`
-	RefreshTokenExpiry *time.Time `gorm:"type:timestamp"`
+	EmailVerifiedAt    *time.Time `gorm:"type:timestamp"` // nil until the user follows a verification link
+	VerificationSentAt *time.Time `gorm:"type:timestamp"` // last verification link, throttles resends
+
+	TOTPSecret         *string    `gorm:"type:text"`      // encrypted; pending until TwoFactorEnabledAt is set
+	TwoFactorEnabledAt *time.Time `gorm:"type:timestamp"` // nil while login is password-only
+	TOTPLastStep       int64      // last accepted TOTP time step, so each code works once
+
+	AvatarVariants ImageVariants `gorm:"type:jsonb;default:null"` // processed renditions of uploaded avatars
+
+	// Failed password logins; saving a user never writes them, so a stale copy cannot undo a failure
+	FailedLoginCount  int        `gorm:"not null;default:0;<-:false"`
+	LastFailedLoginAt *time.Time `gorm:"type:timestamp;<-:false"`
+	LockedUntil       *time.Time `gorm:"type:timestamp;<-:false"` // password logins are refused until then
+
+	// Access tokens carry the version they were issued at; bumping it invalidates them.
+	// Saving a user never writes it, so a stale copy cannot undo a bump.
+	TokenVersion int `gorm:"not null;default:0;<-:false"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
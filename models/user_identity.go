@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to their account at an OpenID Connect provider
+type UserIdentity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Provider    string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_subject"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_subject"` // the provider's "sub" claim
+	Email       string     `gorm:"type:varchar(255)"`                                                  // as last reported by the provider
+	LastLoginAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// OIDCLoginState remembers a login that was sent to a provider until it comes back.
+// The code verifier never leaves the server, so an intercepted code is useless.
+type OIDCLoginState struct {
+	State        string    `gorm:"type:varchar(64);primaryKey"`
+	Provider     string    `gorm:"type:varchar(50);not null"`
+	Nonce        string    `gorm:"type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"`
+	ExpiresAt    time.Time `gorm:"type:timestamp;not null;index"`
+	CreatedAt    time.Time
+}
+
+// TableName keeps GORM from splitting the acronym into "o_id_c"
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnnotationPoint is a position on the video frame in normalized coordinates (0..1 from the top-left)
+type AnnotationPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// AnnotationShape is a drawing overlaid on the video while the annotation is active
+type AnnotationShape struct {
+	Type   AnnotationShapeEnum `json:"type"`
+	Points []AnnotationPoint   `json:"points"`           // arrow/line: from, to; circle: center
+	Radius float64             `json:"radius,omitempty"` // circle only, relative to frame width
+	Color  string              `json:"color,omitempty"`
+}
+
+// AnnotationShapes is stored as a JSONB array
+type AnnotationShapes []AnnotationShape
+
+// Value implements the driver.Valuer interface
+func (s AnnotationShapes) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (s *AnnotationShapes) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// VideoAnnotation marks a timecode range of a match video with a note, tags and drawings
+type VideoAnnotation struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MatchID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	MatchVideoID uuid.UUID `gorm:"type:uuid;not null;index"`
+	AuthorID     uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	StartMs int64 `gorm:"not null;index"`
+	EndMs   int64 `gorm:"not null"`
+
+	Note       string                   `gorm:"type:text"`
+	Tags       StringArray              `gorm:"type:jsonb;default:null"`
+	Shapes     AnnotationShapes         `gorm:"type:jsonb;default:null"`
+	Visibility AnnotationVisibilityEnum `gorm:"type:varchar(20);not null;default:'team'"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
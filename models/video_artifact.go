@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoArtifact is one object the video processor wrote to S3 for a match video
+type VideoArtifact struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MatchID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	MatchVideoID uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	Kind        VideoArtifactKindEnum   `gorm:"type:varchar(20);not null"`
+	Label       string                  `gorm:"type:varchar(50);not null"` // e.g. 1080p, poster, sprite_001.jpg
+	S3Key       string                  `gorm:"type:text;not null"`
+	ContentType string                  `gorm:"type:varchar(100)"`
+	Size        int64                   `gorm:"not null;default:0"`
+	Width       int                     `gorm:"default:0"`
+	Height      int                     `gorm:"default:0"`
+	Status      VideoArtifactStatusEnum `gorm:"type:varchar(20);not null"`
+	Error       string                  `gorm:"type:text"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
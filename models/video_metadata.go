@@ -0,0 +1,12 @@
+package models
+
+// VideoMetadata describes a source video as reported by ffprobe on ingest
+type VideoMetadata struct {
+	DurationMs int64   `gorm:"default:0"`
+	Width      int     `gorm:"default:0"`
+	Height     int     `gorm:"default:0"`
+	FrameRate  float64 `gorm:"default:0"`
+	VideoCodec string  `gorm:"type:varchar(50)"`
+	AudioCodec string  `gorm:"type:varchar(50)"`
+	FileSize   int64   `gorm:"default:0"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoUpload tracks a presigned multipart upload of a raw match video
+type VideoUpload struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MatchID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Angle      string    `gorm:"type:varchar(50);not null;default:'main'"` // camera angle the video is attached to
+	UploadedBy uuid.UUID `gorm:"type:uuid;not null"`
+
+	S3UploadID string `gorm:"type:text;not null"`
+	ObjectKey  string `gorm:"type:text;not null"` // raw video key
+	OutputKey  string `gorm:"type:text;not null"` // compressed video key used once processing starts
+
+	FileName    string `gorm:"type:varchar(255)"`
+	ContentType string `gorm:"type:varchar(100)"`
+	FileSize    int64  `gorm:"not null"`
+	PartSize    int64  `gorm:"not null"`
+	PartCount   int    `gorm:"not null"`
+
+	Status      VideoUploadStatusEnum `gorm:"type:varchar(20);not null;default:'pending'"`
+	Error       string                `gorm:"type:text"`
+	ExpiresAt   time.Time             `gorm:"not null;index"`
+	CompletedAt *time.Time            `gorm:"type:timestamp"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,42 @@
+package assets
+
+import (
+	"strings"
+
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/repositories"
+
+	"go.uber.org/zap"
+)
+
+// legacyFolders holds the folder older rows stored bare file names of, per table
+var legacyFolders = map[string]string{
+	"users":   "avatars/",
+	"teams":   "logos/teams/",
+	"seasons": "logos/seasons/",
+}
+
+// MigrateReferences rewrites references stored before the database held object keys:
+// URLs of our storage or CDN become their key and bare image file names get the folder
+// they were served from. It is idempotent and runs at startup before requests are served.
+func MigrateReferences() {
+	changed, err := repositories.NewAssetReferenceRepository().RewriteReferences(migrateReference)
+	if err != nil {
+		logger.Error("Asset reference migration failed", zap.Int("rows_changed", changed), zap.Error(err))
+		return
+	}
+	if changed > 0 {
+		logger.Info("Asset references migrated to object keys", zap.Int("rows_changed", changed))
+	}
+}
+
+// migrateReference converts one stored reference to the form it is stored in today
+func migrateReference(table, ref string) string {
+	if strings.Contains(ref, "://") {
+		return StoredRef(ref)
+	}
+	if folder, ok := legacyFolders[table]; ok && !strings.Contains(ref, "/") {
+		return folder + ref
+	}
+	return ref
+}
@@ -0,0 +1,105 @@
+package assets
+
+import (
+	"net/url"
+	"strings"
+
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+	storagePkg "go-gin-starter/pkg/storage"
+)
+
+// store renders keys as URLs; it is set once at startup by Init
+var store storagePkg.ObjectStore
+
+// Init sets the store whose public URLs every response renders. The store picks the CDN
+// per asset class: videos, scout files and everything else (logos, avatars, defaults)
+// each have their own CloudFront distribution unless STORAGE_PUBLIC_URL overrides them.
+func Init(objectStore storagePkg.ObjectStore) {
+	store = objectStore
+}
+
+// URL renders a stored reference as the URL clients load it from. References are object
+// keys; URLs pointing at our own storage are re-rendered from their key so a changed CDN
+// domain applies to them too, and URLs of other hosts are returned unchanged.
+func URL(ref string) string {
+	if ref == "" || store == nil {
+		return ref
+	}
+
+	key, ok := Key(ref)
+	if !ok {
+		return ref
+	}
+	return store.PublicURL(key)
+}
+
+// URLs renders a list of references
+func URLs(refs []string) []string {
+	if refs == nil {
+		return nil
+	}
+	urls := make([]string, len(refs))
+	for i, ref := range refs {
+		urls[i] = URL(ref)
+	}
+	return urls
+}
+
+// VariantURLs renders the renditions of a processed image by name
+func VariantURLs(variants models.ImageVariants) map[string]string {
+	if variants == nil {
+		return nil
+	}
+	urls := make(map[string]string, len(variants))
+	for name, ref := range variants {
+		urls[name] = URL(ref)
+	}
+	return urls
+}
+
+// Key returns the object key of a reference. Keys are returned as they are; URLs are only
+// converted when they point at our storage or CDN, otherwise ok is false.
+func Key(ref string) (key string, ok bool) {
+	if !strings.Contains(ref, "://") {
+		return ref, ref != ""
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil || !isOwnHost(parsed.Host) || store == nil {
+		return "", false
+	}
+
+	key, err = store.ObjectKey(ref)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// StoredRef returns what to store for a reference supplied by a client: the object key
+// for our own URLs, anything else unchanged
+func StoredRef(ref string) string {
+	if key, ok := Key(ref); ok {
+		return key
+	}
+	return ref
+}
+
+// isOwnHost reports whether a URL host serves objects of our bucket
+func isOwnHost(host string) bool {
+	if host == "" {
+		return false
+	}
+
+	for _, domain := range []string{config.AssetCloudFrontDomain, config.VideoCloudFrontDomain, config.ScoutCloudFrontDomain} {
+		if domain != "" && host == domain {
+			return true
+		}
+	}
+	if base, err := url.Parse(config.StoragePublicURL); err == nil && base.Host != "" && host == base.Host {
+		return true
+	}
+	// Direct bucket URLs, e.g. <bucket>.s3.<region>.amazonaws.com
+	return config.AWSBucketName != "" && strings.HasPrefix(host, config.AWSBucketName+".s3")
+}
@@ -3,6 +3,7 @@ package audit
 import (
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"time"
 )
 
 // BuildUserUpdateMetadata builds the audit log metadata for user update actions
@@ -63,3 +64,83 @@ func BuildUserDeleteMetadata(user *models.User) models.JSONBMap {
 		"deleted_at": user.DeletedAt,
 	}
 }
+
+// BuildEmailVerificationMetadata builds audit log metadata for an admin verification override
+func BuildEmailVerificationMetadata(user *models.User, wasVerified bool) models.JSONBMap {
+	return models.JSONBMap{
+		"username":     user.Username,
+		"email":        user.Email,
+		"old_verified": wasVerified,
+		"new_verified": user.EmailVerifiedAt != nil,
+	}
+}
+
+// BuildTwoFactorResetMetadata builds audit log metadata for an admin 2FA reset
+func BuildTwoFactorResetMetadata(user *models.User) models.JSONBMap {
+	return models.JSONBMap{
+		"username":    user.Username,
+		"email":       user.Email,
+		"was_enabled": user.TwoFactorEnabledAt != nil,
+	}
+}
+
+// BuildSessionRevokeMetadata builds audit log metadata for an admin revoking a user's sessions
+func BuildSessionRevokeMetadata(user *models.User, revoked int64) models.JSONBMap {
+	return models.JSONBMap{
+		"username": user.Username,
+		"email":    user.Email,
+		"revoked":  revoked,
+	}
+}
+
+// BuildRefreshTokenReuseMetadata builds audit log metadata for a rotated refresh token
+// presented again, and the client that presented it
+func BuildRefreshTokenReuseMetadata(rotated *models.RotatedRefreshToken, revoked bool, userAgent, ipAddress string) models.JSONBMap {
+	return models.JSONBMap{
+		"session_id":      rotated.SessionID,
+		"rotated_at":      rotated.RotatedAt,
+		"session_revoked": revoked,
+		"user_agent":      userAgent,
+		"ip_address":      ipAddress,
+	}
+}
+
+// BuildFailedLoginMetadata builds audit log metadata for a failed password login. The
+// email is the one entered, which may belong to no user.
+func BuildFailedLoginMetadata(email string, failures int, userAgent, ipAddress string) models.JSONBMap {
+	return models.JSONBMap{
+		"email":      email,
+		"failures":   failures,
+		"user_agent": userAgent,
+		"ip_address": ipAddress,
+	}
+}
+
+// BuildAccountLockedMetadata builds audit log metadata for an account locked after failed logins
+func BuildAccountLockedMetadata(user *models.User, failures int, lockedUntil time.Time, ipAddress string) models.JSONBMap {
+	return models.JSONBMap{
+		"username":     user.Username,
+		"email":        user.Email,
+		"failures":     failures,
+		"locked_until": lockedUntil,
+		"ip_address":   ipAddress,
+	}
+}
+
+// BuildLoginIPBlockedMetadata builds audit log metadata for an IP address blocked after failed logins
+func BuildLoginIPBlockedMetadata(ipAddress string, blockedFor time.Duration) models.JSONBMap {
+	return models.JSONBMap{
+		"ip_address":          ipAddress,
+		"blocked_for_seconds": int(blockedFor.Seconds()),
+	}
+}
+
+// BuildAccountUnlockMetadata builds audit log metadata for lifting a lockout
+func BuildAccountUnlockMetadata(user *models.User) models.JSONBMap {
+	return models.JSONBMap{
+		"username":     user.Username,
+		"email":        user.Email,
+		"failures":     user.FailedLoginCount,
+		"locked_until": user.LockedUntil,
+	}
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// accessTokenAlgs are the algorithms access tokens may be signed with
+var accessTokenAlgs = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+// signingKey signs new access tokens
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+// verificationKey is a public key access tokens are accepted from, until expiresAt if set
+type verificationKey struct {
+	alg       string
+	key       crypto.PublicKey
+	expiresAt time.Time
+}
+
+func (k verificationKey) expired() bool {
+	return !k.expiresAt.IsZero() && time.Now().After(k.expiresAt)
+}
+
+var (
+	signer    *signingKey
+	verifiers map[string]verificationKey
+	keySet    oidc.JSONWebKeySet
+)
+
+// InitKeys loads the access token keyring from config. The signing key comes from
+// JWT_SIGNING_KEY or JWT_SIGNING_KEY_PATH; keys in JWT_VERIFICATION_KEY_PATHS are accepted
+// and published as well, so keys can be rotated without invalidating issued tokens.
+// Without a signing key, an Ed25519 key derived from JWT_SECRET is used. Once a signing key
+// is configured, that derived key stays accepted for one access token lifetime, so the
+// switch to a configured key logs nobody out either.
+func InitKeys() error {
+	if config.JWTSecret == "" {
+		return errors.New("JWT_SECRET is not set")
+	}
+
+	derived := ed25519.NewKeyFromSeed(derivedKey("access-token"))
+	var (
+		private crypto.PrivateKey
+		err     error
+	)
+	switch {
+	case config.JWTSigningKey != "":
+		private, err = parsePrivateKey([]byte(config.JWTSigningKey))
+	case config.JWTSigningKeyPath != "":
+		private, err = loadPrivateKey(config.JWTSigningKeyPath)
+	default:
+		logger.Warn("JWT_SIGNING_KEY not set, access tokens are signed with a key derived from JWT_SECRET")
+		private = derived
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load JWT signing key: %w", err)
+	}
+
+	key, ok := private.(crypto.Signer)
+	if !ok {
+		return errors.New("JWT signing key cannot sign")
+	}
+
+	ring := map[string]verificationKey{}
+	var set oidc.JSONWebKeySet
+	add := func(public crypto.PublicKey, expiresAt time.Time) (string, jwt.SigningMethod, error) {
+		method, err := signingMethod(public)
+		if err != nil {
+			return "", nil, err
+		}
+		jwk, err := oidc.NewJSONWebKey("", method.Alg(), public)
+		if err != nil {
+			return "", nil, err
+		}
+		jwk.Kid = thumbprint(jwk)
+		if _, ok := ring[jwk.Kid]; !ok {
+			ring[jwk.Kid] = verificationKey{alg: method.Alg(), key: public, expiresAt: expiresAt}
+			set.Keys = append(set.Keys, jwk)
+		}
+		return jwk.Kid, method, nil
+	}
+
+	kid, method, err := add(key.Public(), time.Time{})
+	if err != nil {
+		return fmt.Errorf("invalid JWT signing key: %w", err)
+	}
+	for _, path := range config.JWTVerificationKeyPaths {
+		public, err := loadPublicKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to load JWT verification key %s: %w", path, err)
+		}
+		if _, _, err := add(public, time.Time{}); err != nil {
+			return fmt.Errorf("invalid JWT verification key %s: %w", path, err)
+		}
+	}
+	// Tokens the derived key signed before a signing key was configured stay valid until they expire
+	if _, _, err := add(derived.Public(), time.Now().Add(constants.AccessTokenTTL)); err != nil {
+		return fmt.Errorf("invalid derived JWT key: %w", err)
+	}
+
+	signer = &signingKey{kid: kid, method: method, key: key}
+	verifiers = ring
+	keySet = set
+	return nil
+}
+
+// JSONWebKeys returns the public keys access tokens may be signed with, for the JWKS endpoint
+func JSONWebKeys() oidc.JSONWebKeySet {
+	set := oidc.JSONWebKeySet{Keys: []oidc.JSONWebKey{}}
+	for _, jwk := range keySet.Keys {
+		if !verifiers[jwk.Kid].expired() {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// signingMethod picks the JWT algorithm for a public key
+func signingMethod(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys need at least 2048 bits")
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint, so every instance derives the same kid
+func thumbprint(jwk oidc.JSONWebKey) string {
+	var members string
+	switch jwk.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func loadPrivateKey(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(data)
+}
+
+// parsePrivateKey reads a PKCS#8 or PKCS#1 PEM private key
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// loadPublicKey reads a PEM public key, or the public half of a PEM private key
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	private, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+	return key.Public(), nil
+}
@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// useKeyConfig sets the key configuration for one test and loads the keyring
+func useKeyConfig(t *testing.T, signingKeyPath string, verificationKeyPaths ...string) {
+	t.Helper()
+	logger.Init()
+
+	previous := struct {
+		secret, key, path string
+		paths             []string
+	}{config.JWTSecret, config.JWTSigningKey, config.JWTSigningKeyPath, config.JWTVerificationKeyPaths}
+	t.Cleanup(func() {
+		config.JWTSecret, config.JWTSigningKey = previous.secret, previous.key
+		config.JWTSigningKeyPath, config.JWTVerificationKeyPaths = previous.path, previous.paths
+	})
+
+	config.JWTSecret = "test-jwt-secret"
+	config.JWTSigningKey = ""
+	config.JWTSigningKeyPath = signingKeyPath
+	config.JWTVerificationKeyPaths = verificationKeyPaths
+	if err := InitKeys(); err != nil {
+		t.Fatalf("InitKeys: %v", err)
+	}
+}
+
+// writeKey stores a private key as PKCS#8 PEM and returns its path
+func writeKey(t *testing.T, name string, key crypto.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func issue(t *testing.T) (string, string) {
+	t.Helper()
+	token, err := GenerateJWT(uuid.New(), uuid.New(), 1)
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+	return token, tokenKid(t, token)
+}
+
+func tokenKid(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func publishedKids() map[string]bool {
+	kids := map[string]bool{}
+	for _, jwk := range JSONWebKeys().Keys {
+		kids[jwk.Kid] = true
+	}
+	return kids
+}
+
+func expectValid(t *testing.T, token, label string) {
+	t.Helper()
+	if _, err := ParseJWT(token); err != nil {
+		t.Fatalf("%s rejected: %v", label, err)
+	}
+}
+
+func expectRejected(t *testing.T, token, label string) {
+	t.Helper()
+	if _, err := ParseJWT(token); err == nil {
+		t.Fatalf("%s accepted", label)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldPath := writeKey(t, "old.pem", newEd25519Key(t))
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	newPath := writeKey(t, "new.pem", rsaKey)
+
+	// Before the rotation
+	useKeyConfig(t, oldPath)
+	oldToken, oldKid := issue(t)
+
+	// Step 1: the new key is published but does not sign yet
+	useKeyConfig(t, oldPath, newPath)
+	if token, kid := issue(t); kid != oldKid {
+		t.Fatalf("new tokens carry kid %s before the new key signs", kid)
+	} else {
+		expectValid(t, token, "token of the old key")
+	}
+	if kids := publishedKids(); len(kids) != 3 || !kids[oldKid] {
+		t.Fatalf("published %v, want the old, new and derived keys", kids)
+	}
+
+	// Step 3: the new key signs, the old one only verifies
+	useKeyConfig(t, newPath, oldPath)
+	expectValid(t, oldToken, "token signed before the rotation")
+	newToken, newKid := issue(t)
+	if newKid == oldKid || newKid == "" {
+		t.Fatalf("new tokens carry kid %q, old tokens %q", newKid, oldKid)
+	}
+	if alg := jwt.SigningMethodRS256.Alg(); tokenAlg(t, newToken) != alg {
+		t.Fatalf("new tokens are signed with %s, want %s", tokenAlg(t, newToken), alg)
+	}
+	expectValid(t, newToken, "token of the new key")
+
+	// Step 4: the old key is removed
+	useKeyConfig(t, newPath)
+	expectRejected(t, oldToken, "token of a removed key")
+	expectValid(t, newToken, "token of the new key")
+	if publishedKids()[oldKid] {
+		t.Fatal("removed key is still published")
+	}
+}
+
+func TestDerivedKeyStaysAcceptedForOneTokenLifetime(t *testing.T) {
+	useKeyConfig(t, "")
+	derivedToken, derivedKid := issue(t)
+	expectValid(t, derivedToken, "token of the derived key")
+
+	signingPath := writeKey(t, "signing.pem", newEd25519Key(t))
+	useKeyConfig(t, signingPath)
+	expectValid(t, derivedToken, "token signed with the derived key before the switch")
+	if !publishedKids()[derivedKid] {
+		t.Fatal("derived key is not published during the switch")
+	}
+	if _, kid := issue(t); kid == derivedKid {
+		t.Fatal("new tokens are still signed with the derived key")
+	}
+
+	key := verifiers[derivedKid]
+	if until := time.Until(key.expiresAt); until <= 14*time.Minute || until > 15*time.Minute {
+		t.Fatalf("derived key accepted for %v, want one access token lifetime", until)
+	}
+	key.expiresAt = time.Now().Add(-time.Second)
+	verifiers[derivedKid] = key
+	expectRejected(t, derivedToken, "token of the expired derived key")
+	if publishedKids()[derivedKid] {
+		t.Fatal("expired derived key is still published")
+	}
+}
+
+func TestVerificationOnlyKey(t *testing.T) {
+	signingPath := writeKey(t, "signing.pem", newEd25519Key(t))
+	otherKey := newEd25519Key(t)
+	otherPath := writeKey(t, "other.pem", otherKey)
+	unknownKey := newEd25519Key(t)
+
+	otherJWK, err := oidc.NewJSONWebKey("", jwt.SigningMethodEdDSA.Alg(), otherKey.Public())
+	if err != nil {
+		t.Fatalf("NewJSONWebKey: %v", err)
+	}
+	otherKid := thumbprint(otherJWK)
+
+	useKeyConfig(t, signingPath, otherPath)
+	if !publishedKids()[otherKid] {
+		t.Fatal("verification key is not published")
+	}
+	if _, kid := issue(t); kid == otherKid {
+		t.Fatal("a verification-only key signs new tokens")
+	}
+
+	sign := func(key ed25519.PrivateKey, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &Claims{
+			UserID: uuid.New(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return signed
+	}
+
+	expectValid(t, sign(otherKey, otherKid), "token of a verification key")
+	expectRejected(t, sign(unknownKey, otherKid), "token of an unknown key under a known kid")
+	expectRejected(t, sign(otherKey, "unknown"), "token with an unknown kid")
+	expectRejected(t, sign(otherKey, ""), "token without a kid")
+}
+
+func TestInitKeysRejectsWeakRSAKeys(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	logger.Init()
+	previousSecret, previousPath := config.JWTSecret, config.JWTSigningKeyPath
+	t.Cleanup(func() { config.JWTSecret, config.JWTSigningKeyPath = previousSecret, previousPath })
+
+	config.JWTSecret = "test-jwt-secret"
+	config.JWTSigningKeyPath = writeKey(t, "weak.pem", weak)
+	if err := InitKeys(); err == nil {
+		t.Fatal("1024 bit RSA signing key accepted")
+	}
+}
+
+func tokenAlg(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	return parsed.Method.Alg()
+}
@@ -2,14 +2,18 @@ package auth
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 	"unicode"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// passwordCost is the bcrypt cost of stored password hashes
+const passwordCost = 14
+
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
 	return string(bytes), err
 }
 
@@ -18,6 +22,18 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
+// dummyHash is a hash no password is checked against successfully
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte(GenerateRandomPassword()), passwordCost)
+	return hash
+})
+
+// CheckDummyPassword takes as long as CheckPasswordHash but has no stored hash to compare with,
+// so a login with an unknown email cannot be told from a wrong password by its response time
+func CheckDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+}
+
 func IsStrongPassword(pw string) bool {
 	var hasUpper, hasLower, hasDigit, hasSpecial bool
 
@@ -2,42 +2,57 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 
+	"go-gin-starter/pkg/constants"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-var jwtKey = []byte(os.Getenv("JWT_SECRET"))
-
 type Claims struct {
-	UserID uuid.UUID
+	UserID       uuid.UUID
+	SessionID    uuid.UUID // the session the token was issued to
+	TokenVersion int       // the user's token version when the token was issued
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID uuid.UUID) (string, error) {
-	expirationTime := time.Now().Add(15 * time.Minute)
+func GenerateJWT(userID, sessionID uuid.UUID, tokenVersion int) (string, error) {
+	expirationTime := time.Now().Add(constants.AccessTokenTTL)
 
 	claims := &Claims{
-		UserID: userID,
+		UserID:       userID,
+		SessionID:    sessionID,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+	if signer == nil {
+		return "", errors.New("signing keys are not loaded")
+	}
+
+	token := jwt.NewWithClaims(signer.method, claims)
+	token.Header["kid"] = signer.kid
+	return token.SignedString(signer.key)
 }
 
 func ParseJWT(tokenStr string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := verifiers[kid]
+		if !ok || token.Method.Alg() != key.alg || key.expired() {
+			return nil, errors.New("unknown signing key")
+		}
+		return key.key, nil
+	}, jwt.WithValidMethods(accessTokenAlgs), jwt.WithExpirationRequired())
 
 	if err != nil || !token.Valid {
 		return nil, fmt.Errorf("invalid or expired token")
@@ -54,3 +69,10 @@ func GenerateSecureToken(n int) (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// HashToken returns the SHA-256 hex digest under which an opaque token is stored, so a
+// database leak does not reveal usable tokens
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890", in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestValidateTOTPRFC6238 uses the SHA1 test vectors of RFC 6238 appendix B. The RFC lists
+// eight digit codes; six digit codes are their last six digits.
+func TestValidateTOTPRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		step int64
+		code string
+	}{
+		{unix: 59, step: 0x1, code: "94287082"},
+		{unix: 1111111109, step: 0x23523EC, code: "07081804"},
+		{unix: 1111111111, step: 0x23523ED, code: "14050471"},
+		{unix: 1234567890, step: 0x273EF07, code: "89005924"},
+		{unix: 2000000000, step: 0x3F940AA, code: "69279037"},
+		{unix: 20000000000, step: 0x27BC86AA, code: "65353130"},
+	}
+
+	for _, tt := range tests {
+		code := tt.code[len(tt.code)-totpDigits:]
+		key, _ := base32NoPadding.DecodeString(rfc6238Secret)
+		if got := hotp(key, tt.step); got != code {
+			t.Errorf("hotp at step %#x = %s, want %s", tt.step, got, code)
+		}
+
+		step, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.step {
+			t.Errorf("ValidateTOTP(%s) at %d = (%#x, %v), want (%#x, true)", code, tt.unix, step, ok, tt.step)
+		}
+	}
+}
+
+// TestValidateTOTPDriftWindow accepts codes one step either side of the current one and
+// reports the step that matched, which is what AcceptStep records against replays
+func TestValidateTOTPDriftWindow(t *testing.T) {
+	key, _ := base32NoPadding.DecodeString(rfc6238Secret)
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	for offset := int64(-3); offset <= 3; offset++ {
+		step, ok := ValidateTOTP(rfc6238Secret, hotp(key, current+offset), now)
+		accepted := offset >= -totpSkew && offset <= totpSkew
+		if ok != accepted {
+			t.Errorf("code of step %+d accepted = %v, want %v", offset, ok, accepted)
+		}
+		if ok && step != current+offset {
+			t.Errorf("code of step %+d matched step %+d", offset, step-current)
+		}
+	}
+
+	// The first and last instant of a step both belong to it
+	start := time.Unix(current*totpPeriod, 0)
+	for _, at := range []time.Time{start, start.Add(totpPeriod*time.Second - time.Nanosecond)} {
+		if step, ok := ValidateTOTP(rfc6238Secret, hotp(key, current), at); !ok || step != current {
+			t.Errorf("current code at %v = (%+d, %v), want (0, true)", at, step-current, ok)
+		}
+		if _, ok := ValidateTOTP(rfc6238Secret, hotp(key, current+totpSkew+1), at); ok {
+			t.Errorf("code beyond the window accepted at %v", at)
+		}
+	}
+}
+
+func TestValidateTOTPMalformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{name: "lowercase secret", secret: strings.ToLower(rfc6238Secret), code: "287082", ok: true},
+		{name: "padded secret", secret: rfc6238Secret + "====", code: "287082", ok: true},
+		{name: "invalid secret", secret: "not base32!", code: "287082"},
+		{name: "short code", secret: rfc6238Secret, code: "28708"},
+		{name: "long code", secret: rfc6238Secret, code: "4287082"},
+		{name: "empty code", secret: rfc6238Secret, code: ""},
+		{name: "wrong code", secret: rfc6238Secret, code: "287083"},
+	}
+
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, %v", secret, len(key), err)
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(TOTPProvisioningURI("Volley Club", "coach@example.com", rfc6238Secret))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Volley Club:coach@example.com" {
+		t.Errorf("unexpected URI %s", uri)
+	}
+	query := uri.Query()
+	for key, want := range map[string]string{"secret": rfc6238Secret, "issuer": "Volley Club", "algorithm": "SHA1", "digits": "6", "period": "30"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
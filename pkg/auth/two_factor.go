@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// recoveryAlphabet avoids characters that are easily confused when typed from paper
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// TwoFactorRequired reports whether a user's role may only use the app with 2FA enabled
+func TwoFactorRequired(user *models.User) bool {
+	return config.TwoFactorRequiredRoles[string(user.Role)]
+}
+
+// ChallengeClaims identify a user who passed the password step of a two-factor login
+type ChallengeClaims struct {
+	UserID uuid.UUID
+	jwt.RegisteredClaims
+}
+
+// GenerateTwoFactorChallenge signs a short-lived token that stands in for the password
+// while the user enters their second factor
+func GenerateTwoFactorChallenge(userID uuid.UUID, ttl time.Duration) (string, error) {
+	claims := &ChallengeClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(derivedKey("two-factor-challenge"))
+}
+
+// ParseTwoFactorChallenge checks the signature and expiry of a challenge token
+func ParseTwoFactorChallenge(tokenStr string) (*ChallengeClaims, error) {
+	claims := &ChallengeClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return derivedKey("two-factor-challenge"), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid or expired challenge token")
+	}
+
+	return claims, nil
+}
+
+// GenerateRecoveryCodes creates one-time codes formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	// Bytes at or above limit are skipped so every character is equally likely
+	limit := 256 - 256%len(recoveryAlphabet)
+
+	codes := make([]string, count)
+	random := make([]byte, 1)
+	for i := range codes {
+		code := make([]byte, 0, 10)
+		for len(code) < cap(code) {
+			if _, err := rand.Read(random); err != nil {
+				return nil, err
+			}
+			if int(random[0]) < limit {
+				code = append(code, recoveryAlphabet[int(random[0])%len(recoveryAlphabet)])
+			}
+		}
+		codes[i] = string(code[:5]) + "-" + string(code[5:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode hashes a recovery code for storage, ignoring case, spaces and dashes.
+// Codes are random enough that a fast hash is sufficient.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// EncryptSecret encrypts a TOTP secret for storage with AES-256-GCM
+func EncryptSecret(plaintext string) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts a secret stored by EncryptSecret
+func DecryptSecret(ciphertext string) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted secret")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// secretCipher derives the AES key from TOTP_ENCRYPTION_KEY
+func secretCipher() (cipher.AEAD, error) {
+	if config.TOTPEncryptionKey == "" {
+		return nil, errors.New("TOTP_ENCRYPTION_KEY is not configured")
+	}
+	key := sha256.Sum256([]byte(config.TOTPEncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,137 @@
+package cdn
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/pkg/logger"
+
+	"github.com/aws/aws-sdk-go/service/cloudfront/sign"
+	"go.uber.org/zap"
+)
+
+// protectedPrefixes lists the path prefixes per CloudFront domain that need a signature.
+// Logos and avatars share the scout distribution but stay public.
+func protectedPrefixes() map[string][]string {
+	return map[string][]string{
+		config.VideoCloudFrontDomain: {"/videos/"},
+		config.ScoutCloudFrontDomain: {"/scout-files/"},
+	}
+}
+
+var (
+	keyPairID  string
+	privateKey *rsa.PrivateKey
+)
+
+// Init loads the CloudFront signing key from config. Without a key pair ID signing stays disabled
+// and protected URLs are returned as-is, which only works while the distributions are public.
+func Init() error {
+	if config.CloudFrontKeyPairID == "" {
+		logger.Warn("CLOUDFRONT_KEY_PAIR_ID not set, video and scout URLs are served unsigned")
+		return nil
+	}
+
+	var (
+		key *rsa.PrivateKey
+		err error
+	)
+	switch {
+	case config.CloudFrontPrivateKey != "":
+		key, err = sign.LoadPEMPrivKey(strings.NewReader(config.CloudFrontPrivateKey))
+	case config.CloudFrontPrivateKeyPath != "":
+		key, err = sign.LoadPEMPrivKeyFile(config.CloudFrontPrivateKeyPath)
+	default:
+		return fmt.Errorf("CLOUDFRONT_KEY_PAIR_ID is set but no private key is configured")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load CloudFront private key: %w", err)
+	}
+
+	keyPairID = config.CloudFrontKeyPairID
+	privateKey = key
+	return nil
+}
+
+// Enabled reports whether protected URLs are signed
+func Enabled() bool {
+	return privateKey != nil
+}
+
+// IsProtected reports whether the URL points at a protected video or scout object
+func IsProtected(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	for _, prefix := range protectedPrefixes()[parsed.Host] {
+		if strings.HasPrefix(parsed.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiresAt returns when URLs signed now stop working
+func ExpiresAt() time.Time {
+	return time.Now().Add(config.SignedURLTTL)
+}
+
+// SignURL returns a short-lived signed URL for protected objects. Public URLs, URLs that
+// are already signed and all URLs while signing is disabled are returned unchanged.
+func SignURL(rawURL string) string {
+	if !Enabled() || rawURL == "" || !IsProtected(rawURL) || strings.Contains(rawURL, "Signature=") {
+		return rawURL
+	}
+
+	signed, err := sign.NewURLSigner(keyPairID, privateKey).Sign(rawURL, ExpiresAt())
+	if err != nil {
+		logger.Error("Failed to sign CloudFront URL", zap.String("url", rawURL), zap.Error(err))
+		return rawURL
+	}
+	return signed
+}
+
+// SignURLs signs every URL of a slice, see SignURL
+func SignURLs(rawURLs []string) []string {
+	if len(rawURLs) == 0 {
+		return rawURLs
+	}
+
+	signed := make([]string, len(rawURLs))
+	for i, rawURL := range rawURLs {
+		signed[i] = SignURL(rawURL)
+	}
+	return signed
+}
+
+// SignedCookies returns CloudFront cookies granting access to every object below folder on
+// the video domain. They are only set when CLOUDFRONT_COOKIE_DOMAIN is configured, because
+// the browser only sends them to CloudFront if the API and CDN share that parent domain.
+func SignedCookies(folder string) ([]*http.Cookie, error) {
+	if !Enabled() || config.CloudFrontCookieDomain == "" || folder == "" {
+		return nil, nil
+	}
+
+	resource := fmt.Sprintf("https://%s/%s/*", config.VideoCloudFrontDomain, strings.Trim(folder, "/"))
+	policy := &sign.Policy{
+		Statements: []sign.Statement{{
+			Resource: resource,
+			Condition: sign.Condition{
+				DateLessThan: sign.NewAWSEpochTime(ExpiresAt()),
+			},
+		}},
+	}
+
+	signer := sign.NewCookieSigner(keyPairID, privateKey, func(o *sign.CookieOptions) {
+		o.Domain = config.CloudFrontCookieDomain
+		o.Secure = true
+	})
+	return signer.SignWithPolicy(policy)
+}
@@ -0,0 +1,44 @@
+package constants
+
+import "time"
+
+const (
+	// Email verification links
+	EmailVerificationTTL       = 48 * time.Hour  // how long a verification link stays valid
+	VerificationResendCooldown = 2 * time.Minute // minimum time between two links to the same user
+)
+
+const (
+	// Links to set a password; invitations use the same token as a password reset
+	PasswordResetTTL = 12 * time.Hour
+	InvitationTTL    = 7 * 24 * time.Hour
+)
+
+const (
+	// Two-factor authentication
+	TOTPIssuer            = "Volleymate"    // account name shown in authenticator apps
+	TOTPQRCodeScale       = 6               // pixels per module of the provisioning QR code
+	TwoFactorChallengeTTL = 5 * time.Minute // time to enter the code after the password
+	TwoFactorMaxAttempts  = 5               // wrong codes per challenge before logging in again; they also count as failed logins
+	RecoveryCodeCount     = 10
+)
+
+const (
+	// OpenID Connect login
+	OIDCLoginTTL       = 10 * time.Minute // time to sign in at the provider and come back
+	OIDCRequestTimeout = 10 * time.Second // discovery, key and token requests to a provider
+	OIDCMetadataTTL    = 24 * time.Hour   // how long discovery documents and keys are cached
+	OIDCKeyRefreshWait = time.Minute      // minimum time between key refetches for unknown key IDs
+)
+
+const (
+	// Access tokens; the key derived from JWT_SECRET stays accepted this long once a signing key is configured
+	AccessTokenTTL = 15 * time.Minute
+)
+
+const (
+	// Sessions, one per signed-in device
+	SessionTTL         = 7 * 24 * time.Hour // a session ends if it is not refreshed for this long
+	MaxSessionsPerUser = 50                 // the least recently used are dropped beyond this
+	SessionCheckTTL    = 30 * time.Second   // how long JWTAuth trusts that a session is still active
+)
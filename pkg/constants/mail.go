@@ -0,0 +1,13 @@
+package constants
+
+import "time"
+
+const (
+	// Outgoing mail is queued and sent in the background; failed sends are retried
+	// with exponential backoff starting at MailRetryBaseDelay
+	MailQueueSize      = 1000
+	MailWorkers        = 2
+	MailMaxAttempts    = 5
+	MailRetryBaseDelay = 30 * time.Second
+	MailSendTimeout    = 30 * time.Second
+)
@@ -28,7 +28,7 @@ const (
 	ErrTeamNotFound          = "team not found"
 	ErrTeamAlreadyExists     = "team already exists"
 	ErrFileUploadRequired    = "file upload is required"
-	ErrLogoTooLarge          = "logo file is too large. Max size is 2MB"
+	ErrLogoTooLarge          = "logo file is too large. Max size is 5MB"
 	ErrMatchNotFound         = "match not found"
 	ErrInvalidMatchRound     = "Invalid round provided"
 	ErrInvalidMatchID        = "invalid match ID"
@@ -52,6 +52,70 @@ const (
 	ErrVideoProcessing       = "failed to process video"
 	ErrInvalidFormat         = "invalid video format"
 	ErrQueueOperation        = "queue operation failed"
+	ErrInvalidUploadID       = "invalid upload ID"
+	ErrVideoUploadNotFound   = "video upload not found"
+	ErrVideoUploadExpired    = "video upload has expired"
+	ErrVideoUploadNotPending = "video upload is no longer pending"
+	ErrVideoTooLarge         = "video file is too large. Max size is 4GB"
+	ErrInvalidVideoType      = "only .mp4, .mov and .mkv videos are allowed"
+	ErrVideoSizeMismatch     = "uploaded video size does not match the declared size"
+	ErrVideoObjectMissing    = "uploaded video was not found in storage"
+	ErrNoVideoStream         = "video file contains no video stream"
+	ErrUnsupportedVideoCodec = "unsupported video codec"
+	ErrInvalidVideoDuration  = "video file has no playable duration"
+	ErrInvalidMatchVideoID   = "invalid match video ID"
+	ErrMatchVideoNotFound    = "match video not found"
+	ErrDuplicateVideoAngle   = "match already has a video for this angle"
+	ErrInvalidVideoAngle     = "angle may only contain letters, numbers, '-' and '_'"
+	ErrReferenceAngleOffset  = "the reference angle always has a zero offset"
+	ErrInvalidAnnotationID   = "invalid annotation ID"
+	ErrAnnotationNotFound    = "annotation not found"
+	ErrInvalidAnnotationTime = "annotation must end after it starts and stay within the video"
+	ErrInvalidVisibility     = "visibility must be one of: private, coaching_staff, team"
+	ErrInvalidAnnotationDraw = "shapes need a valid type; arrows and lines take two points, circles a center and radius"
+	ErrInvalidJumpDirection  = "direction must be either 'next' or 'previous'"
+	ErrInvalidPlaylistID     = "invalid playlist ID"
+	ErrPlaylistNotFound      = "playlist not found"
+	ErrInvalidPlaylistItemID = "invalid playlist item ID"
+	ErrPlaylistItemNotFound  = "playlist item not found"
+	ErrInvalidClipRange      = "clip must end after it starts and stay within the video"
+	ErrVideoNotReady         = "video has not finished processing yet"
+	ErrInvalidItemOrder      = "item order must list every playlist item exactly once"
+	ErrMediaAccessDenied     = "you are not allowed to watch this match"
+	ErrInvalidRawAction      = "raw_action must be one of: keep, archive, delete"
+	ErrInvalidRallyID        = "invalid rally ID"
+	ErrRallyNotFound         = "rally not found"
+	ErrInvalidRallyTime      = "rally must end after it starts and stay within the video"
+	ErrNoAudioStream         = "video has no audio track to detect rallies from"
+	ErrScoutNotFound         = "no scout file found for this match"
+	ErrInvalidImage          = "file is not a valid JPEG or PNG image"
+	ErrImageDimensions       = "image must be between 32 and 8000 pixels on each side"
+	ErrScoutTooLarge         = "scout file is too large. Max size is 1MB"
+	ErrFileContentMismatch   = "file content does not match the expected file type"
+	ErrInvalidVideoFile      = "video file is not a readable MP4, MOV or MKV container"
+	ErrInvalidScoutFile      = "scout file is not a valid DataVolley .dvw file"
+	ErrEmailNotVerified      = "please verify your email address first"
+	ErrEmailAlreadyVerified  = "email address is already verified"
+	ErrVerificationTooSoon   = "a verification email was sent recently, please try again later"
+	ErrVerificationFailed    = "failed to send verification email"
+	ErrMailFailed            = "failed to send email"
+	ErrUnsupportedLocale     = "locale must be one of: en, de"
+	ErrTwoFactorNotSetUp     = "two-factor authentication has not been set up"
+	ErrTwoFactorEnabled      = "two-factor authentication is already enabled"
+	ErrTwoFactorDisabled     = "two-factor authentication is not enabled"
+	ErrTwoFactorRequired     = "your role requires two-factor authentication"
+	ErrInvalidTwoFactorCode  = "invalid authentication code"
+	ErrTooManyCodeAttempts   = "too many invalid codes, please log in again"
+	ErrUnknownProvider       = "unknown identity provider"
+	ErrProviderUnavailable   = "the identity provider could not be reached"
+	ErrInvalidLoginState     = "login expired or was already completed, please start again"
+	ErrProviderLoginFailed   = "sign-in with the identity provider failed"
+	ErrProviderEmailMissing  = "the identity provider did not confirm an email address"
+	ErrAccountUnverified     = "verify the email address of your existing account before signing in with this provider"
+	ErrSessionNotFound       = "session not found"
+	ErrLoginThrottled        = "too many failed login attempts, please try again later"
+	ErrAccountLocked         = "account is temporarily locked after too many failed logins; check your email to unlock it"
+	ErrAccountNotLocked      = "account is not locked"
 )
 
 // Success messages
@@ -64,7 +128,7 @@ const (
 	MsgUserDeleted            = "user deleted successfully"
 	MsgPasswordChanged        = "password changed successfully"
 	MsgAvatarUploaded         = "avatar uploaded successfully"
-	MsgResetTokenCreated      = "reset token generated successfully"
+	MsgResetLinkSent          = "password reset link sent"
 	MsgPasswordReset          = "password reset successfully"
 	MsgUsersFetched           = "users fetched successfully"
 	MsgUserFetched            = "user fetched successfully"
@@ -104,4 +168,50 @@ const (
 	MsgAuditLogsFetched       = "audit logs fetched successfully"
 	MsgWaitlistEntryRejected  = "waitlist entry rejected successfully"
 	MsgWaitlistSuccess        = "waitlist entry successfully processed"
+	MsgVideoUploadInitiated   = "video upload initiated successfully"
+	MsgVideoUploadCompleted   = "video upload completed and queued for processing"
+	MsgVideoUploadAborted     = "video upload aborted successfully"
+	MsgMatchVideosFetched     = "match videos fetched successfully"
+	MsgMatchVideoUpdated      = "match video updated successfully"
+	MsgMatchVideoDeleted      = "match video deleted successfully"
+	MsgAnnotationCreated      = "annotation created successfully"
+	MsgAnnotationsFetched     = "annotations fetched successfully"
+	MsgAnnotationFetched      = "annotation fetched successfully"
+	MsgAnnotationUpdated      = "annotation updated successfully"
+	MsgAnnotationDeleted      = "annotation deleted successfully"
+	MsgPlaylistCreated        = "playlist created successfully"
+	MsgPlaylistsFetched       = "playlists fetched successfully"
+	MsgPlaylistFetched        = "playlist fetched successfully"
+	MsgPlaylistUpdated        = "playlist updated successfully"
+	MsgPlaylistDeleted        = "playlist deleted successfully"
+	MsgPlaylistShared         = "playlist sharing updated successfully"
+	MsgPlaylistViewRecorded   = "playlist view recorded successfully"
+	MsgPlaylistViewsFetched   = "playlist views fetched successfully"
+	MsgPlaybackAuthorized     = "playback URLs issued successfully"
+	MsgRetentionPolicyFetched = "retention policy fetched successfully"
+	MsgRetentionPolicyUpdated = "retention policy updated successfully"
+	MsgStorageReportFetched   = "storage report generated successfully"
+	MsgOrphanReportFetched    = "orphaned object report generated successfully"
+	MsgRalliesFetched         = "rallies fetched successfully"
+	MsgRallyDetectionQueued   = "rally detection queued successfully"
+	MsgRallyCreated           = "rally created successfully"
+	MsgRallyUpdated           = "rally updated successfully"
+	MsgRallyDeleted           = "rally deleted successfully"
+	MsgCondensedQueued        = "condensed video queued successfully"
+	MsgEmailVerified          = "email address verified successfully"
+	MsgVerificationSent       = "verification email sent"
+	MsgVerificationUpdated    = "email verification status updated successfully"
+	MsgTwoFactorFetched       = "two-factor status fetched successfully"
+	MsgTwoFactorSetupStarted  = "scan the QR code and confirm with a code to enable two-factor authentication"
+	MsgTwoFactorEnabled       = "two-factor authentication enabled; store the recovery codes safely"
+	MsgTwoFactorDisabled      = "two-factor authentication disabled"
+	MsgRecoveryCodesRenewed   = "recovery codes regenerated; the previous codes no longer work"
+	MsgTwoFactorReset         = "two-factor authentication reset successfully"
+	MsgProvidersFetched       = "identity providers fetched successfully"
+	MsgProviderLoginStarted   = "redirect the user to the authorization URL"
+	MsgSessionsFetched        = "sessions fetched successfully"
+	MsgSessionRevoked         = "session revoked successfully"
+	MsgSessionsRevoked        = "sessions revoked successfully"
+	MsgLoggedOut              = "logged out successfully"
+	MsgAccountUnlocked        = "account unlocked successfully"
 )
@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 const (
 	// File size limits (in bytes)
 	MaxAvatarFileSize = 2 * 1024 * 1024        // 2MB
@@ -7,3 +9,45 @@ const (
 	MaxVideoFileSize  = 4 * 1024 * 1024 * 1024 // 4GB
 	MaxScoutFileSize  = 1 * 1024 * 1024        // 1MB
 )
+
+const (
+	// Logo and avatar processing: uploads outside these bounds are rejected,
+	// accepted ones are re-encoded into fixed renditions
+	MinImageSide     = 32
+	MaxImageSide     = 8000
+	MaxImagePixels   = 40_000_000
+	ImageJPEGQuality = 85
+)
+
+const (
+	// Presigned multipart video uploads
+	VideoUploadPartSize    = 64 * 1024 * 1024 // 64MB per part
+	VideoUploadMaxParts    = 10000            // S3 multipart limit
+	VideoUploadExpiry      = 24 * time.Hour   // how long presigned part URLs stay valid
+	VideoUploadCleanupTick = 1 * time.Hour    // how often abandoned uploads are swept
+)
+
+const (
+	// DefaultVideoAngle labels uploads that do not name a camera angle
+	DefaultVideoAngle = "main"
+	MaxVideoAngleLen  = 50
+)
+
+const (
+	// Raw video retention defaults for seasons without their own policy
+	DefaultRawRetentionAction  = "archive"
+	DefaultRawGraceDays        = 7
+	DefaultArchiveStorageClass = "DEEP_ARCHIVE"
+	DefaultFailedRawFlagDays   = 3
+	RawRetentionTick           = 6 * time.Hour
+)
+
+const (
+	// Orphaned object collection: unreferenced objects are acted on once they have been
+	// orphaned for the grace period; quarantined copies are purged after the retention
+	OrphanGCTick              = 24 * time.Hour
+	OrphanGracePeriod         = 7 * 24 * time.Hour
+	OrphanQuarantineRetention = 30 * 24 * time.Hour
+	OrphanQuarantinePrefix    = "quarantine/"
+	OrphanReportLimit         = 1000 // orphans listed by the dry-run report
+)
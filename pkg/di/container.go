@@ -1,17 +1,16 @@
 package di
 
 import (
+	"errors"
+	"go-gin-starter/config"
 	"go-gin-starter/controllers"
+	"go-gin-starter/pkg/oidc"
+	"go-gin-starter/pkg/orphans"
+	storagePkg "go-gin-starter/pkg/storage"
 	"go-gin-starter/pkg/upload"
 	"go-gin-starter/pkg/video"
 	"go-gin-starter/repositories"
 	"go-gin-starter/services"
-	"os"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/sqs"
 )
 
 // Container holds all the dependency instances
@@ -26,11 +25,32 @@ type Container struct {
 	MatchController                *controllers.MatchController
 	SeasonController               *controllers.SeasonController
 	HealthController               *controllers.HealthController
+	VideoUploadController          *controllers.VideoUploadController
+	MatchVideoController           *controllers.MatchVideoController
+	VideoAnnotationController      *controllers.VideoAnnotationController
+	PlaylistController             *controllers.PlaylistController
+	RetentionController            *controllers.RetentionController
+	RallyController                *controllers.RallyController
+	TwoFactorController            *controllers.TwoFactorController
+	OIDCController                 *controllers.OIDCController
+	SessionController              *controllers.SessionController
 	// Add other controllers here as needed
 }
 
-// NewContainer initializes and returns a new dependency container
-func NewContainer() *Container {
+// NewContainer initializes and returns a new dependency container. The object store, video
+// queue and orphan collector are shared with the background workers main starts, so they
+// are passed in rather than built here.
+func NewContainer(store storagePkg.Store, videoQueue *video.QueueManager, orphanCollector *orphans.Collector) (*Container, error) {
+	if store == nil {
+		return nil, errors.New("dependency container requires an object store")
+	}
+	if videoQueue == nil {
+		return nil, errors.New("dependency container requires a video queue")
+	}
+	if orphanCollector == nil {
+		return nil, errors.New("dependency container requires an orphan collector")
+	}
+
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository()
 	waitlistRepo := repositories.NewWaitlistRepository()
@@ -38,42 +58,45 @@ func NewContainer() *Container {
 	teamRepo := repositories.NewTeamRepository()
 	matchRepo := repositories.NewMatchRepository()
 	seasonRepo := repositories.NewSeasonRepository()
+	videoUploadRepo := repositories.NewVideoUploadRepository()
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+	artifactRepo := repositories.NewVideoArtifactRepository()
+	annotationRepo := repositories.NewVideoAnnotationRepository()
+	playlistRepo := repositories.NewPlaylistRepository()
+	retentionPolicyRepo := repositories.NewRetentionPolicyRepository()
+	rallyRepo := repositories.NewRallySegmentRepository()
+	twoFactorRepo := repositories.NewTwoFactorRepository()
+	identityRepo := repositories.NewIdentityRepository()
+	sessionRepo := repositories.NewSessionRepository()
 
 	// Add other repositories here as needed
 
 	// Initialize utility services
-	uploadService := upload.NewFileUploadService()
-
-	// Initialize AWS/video queue for match service
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(os.Getenv("AWS_REGION")),
-	})
-	if err != nil {
-		panic("Failed to create AWS session: " + err.Error())
-	}
-	s3Client := s3.New(sess)
-	sqsClient := sqs.New(sess)
-	videoProcessor := video.NewVideoProcessor(sess, s3Client, os.Getenv("AWS_BUCKET_NAME"))
-	videoQueue := video.NewQueueManager(
-		sqsClient,
-		os.Getenv("VIDEO_PROCESSING_QUEUE_URL"),
-		videoProcessor,
-	)
+	uploadService := upload.NewFileUploadService(store)
 
 	// Initialize services
-	userService := services.NewUserService(userRepo)
-	waitlistService := services.NewWaitlistService(waitlistRepo, userService)
-	authService := services.NewAuthService(authRepo, userRepo)
+	sessionService := services.NewSessionService(sessionRepo, userRepo)
+	userService := services.NewUserService(userRepo, sessionService)
+	authService := services.NewAuthService(authRepo, userRepo, twoFactorRepo, sessionService)
+	waitlistService := services.NewWaitlistService(waitlistRepo, userService, authService)
 	teamService := services.NewTeamService(teamRepo, uploadService)
-	matchService := services.NewMatchService(matchRepo, teamRepo, seasonRepo, videoQueue)
+	matchService := services.NewMatchService(matchRepo, matchVideoRepo, artifactRepo, teamRepo, seasonRepo, userRepo, videoQueue, store)
 	seasonService := services.NewSeasonService(seasonRepo, uploadService)
+	videoUploadService := services.NewVideoUploadService(videoUploadRepo, matchRepo, matchVideoRepo, artifactRepo, seasonRepo, store, videoQueue)
+	matchVideoService := services.NewMatchVideoService(matchVideoRepo, matchRepo, artifactRepo, userRepo)
+	annotationService := services.NewVideoAnnotationService(annotationRepo, matchVideoRepo, userRepo)
+	playlistService := services.NewPlaylistService(playlistRepo, matchVideoRepo, artifactRepo, userRepo)
+	retentionService := services.NewRetentionService(retentionPolicyRepo, seasonRepo, matchVideoRepo, artifactRepo, orphanCollector)
+	rallyService := services.NewRallyService(rallyRepo, matchVideoRepo, artifactRepo, videoQueue)
+	twoFactorService := services.NewTwoFactorService(userRepo, twoFactorRepo)
+	oidcService := services.NewOIDCService(oidc.NewRegistry(config.OIDCProviders), identityRepo, authRepo, userRepo, authService)
 
 	// Initialize global service references for backward compatibility
-	services.InitGlobalServices(userService)
+	services.InitGlobalServices(userService, sessionService)
 
 	// Initialize controllers
 	userController := controllers.NewUserController(userService, uploadService)
-	adminUserController := controllers.NewAdminUserController(userService)
+	adminUserController := controllers.NewAdminUserController(userService, authService, twoFactorService, sessionService)
 	adminUserPermissionsController := controllers.NewAdminUserPermissionsController(userService)
 	adminAuditController := controllers.NewAdminAuditController()
 	waitlistController := controllers.NewWaitlistController(waitlistService)
@@ -82,6 +105,15 @@ func NewContainer() *Container {
 	matchController := controllers.NewMatchController(matchService)
 	seasonController := controllers.NewSeasonController(seasonService, uploadService)
 	healthController := controllers.NewHealthController()
+	videoUploadController := controllers.NewVideoUploadController(videoUploadService)
+	matchVideoController := controllers.NewMatchVideoController(matchVideoService)
+	annotationController := controllers.NewVideoAnnotationController(annotationService)
+	playlistController := controllers.NewPlaylistController(playlistService)
+	retentionController := controllers.NewRetentionController(retentionService)
+	rallyController := controllers.NewRallyController(rallyService)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService)
+	oidcController := controllers.NewOIDCController(oidcService)
+	sessionController := controllers.NewSessionController(sessionService)
 
 	return &Container{
 		UserController:                 userController,
@@ -94,6 +126,15 @@ func NewContainer() *Container {
 		MatchController:                matchController,
 		SeasonController:               seasonController,
 		HealthController:               healthController,
+		VideoUploadController:          videoUploadController,
+		MatchVideoController:           matchVideoController,
+		VideoAnnotationController:      annotationController,
+		PlaylistController:             playlistController,
+		RetentionController:            retentionController,
+		RallyController:                rallyController,
+		TwoFactorController:            twoFactorController,
+		OIDCController:                 oidcController,
+		SessionController:              sessionController,
 		// Add other controllers here as needed
-	}
+	}, nil
 }
@@ -1,10 +1,9 @@
 package http
 
 import (
-	"fmt"
-	"go-gin-starter/config"
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	auth "go-gin-starter/pkg/auth"
 
 	"github.com/gin-gonic/gin"
@@ -12,16 +11,23 @@ import (
 
 // BuildAdminUserResponse constructs AdminUserResponse DTO from User model
 func BuildAdminUserResponse(user *models.User) dto.AdminUserResponse {
-	return dto.AdminUserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Gender:    user.Gender,
-		Role:      user.Role,
-		AvatarURL: fmt.Sprintf("https://%s/avatars/%s", config.AssetCloudFrontDomain, user.Avatar),
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
+	response := dto.AdminUserResponse{
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Gender:           user.Gender,
+		Role:             user.Role,
+		AvatarURL:        UserAvatarURL(user),
+		AvatarURLs:       assets.VariantURLs(user.AvatarVariants),
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TwoFactorEnabledAt != nil,
+		CreatedAt:        user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        user.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
+	if user.EmailVerifiedAt != nil {
+		response.EmailVerifiedAt = user.EmailVerifiedAt.Format("2006-01-02 15:04:05")
+	}
+	return response
 }
 
 // BuildUserPermissionsResponse builds the API response for user permissions update
@@ -60,18 +66,41 @@ func BuildUserResetPermissionsResponse(user *models.User, emptyPermissions []str
 
 // BuildTeamResponse builds TeamResponse DTO from Team model
 func BuildTeamResponse(team *models.Team) dto.TeamResponse {
-	logo := "defaults/default-team-logo.png"
-	if team.Logo != "" {
-		logo = team.Logo
-	}
-
 	return dto.TeamResponse{
 		ID:        team.ID,
 		Name:      team.Name,
 		Country:   team.Country,
 		SeasonID:  team.SeasonID,
-		LogoURL:   fmt.Sprintf("https://%s/logos/teams/%s", config.AssetCloudFrontDomain, logo),
+		LogoURL:   TeamLogoURL(team),
+		LogoURLs:  assets.VariantURLs(team.LogoVariants),
 		CreatedAt: team.CreatedAt,
 		UpdatedAt: team.UpdatedAt,
 	}
 }
+
+// UserAvatarURL returns the URL of a user's avatar, preferring the large processed rendition
+func UserAvatarURL(user *models.User) string {
+	return imageURL(user.AvatarVariants, user.Avatar)
+}
+
+// TeamLogoURL returns the URL of a team logo, preferring the large processed rendition
+func TeamLogoURL(team *models.Team) string {
+	logo := team.Logo
+	if logo == "" {
+		logo = "defaults/default-team-logo.png"
+	}
+	return imageURL(team.LogoVariants, logo)
+}
+
+// SeasonLogoURL returns the URL of a season logo, preferring the large processed rendition
+func SeasonLogoURL(season *models.Season) string {
+	return imageURL(season.LogoVariants, season.Logo)
+}
+
+// imageURL resolves a stored image, preferring its large rendition over the original key
+func imageURL(variants models.ImageVariants, stored string) string {
+	if key := variants[models.ImageSizeLarge]; key != "" {
+		return assets.URL(key)
+	}
+	return assets.URL(stored)
+}
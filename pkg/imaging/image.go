@@ -0,0 +1,119 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+var (
+	// ErrNotAnImage is returned when the data is not a JPEG or PNG image
+	ErrNotAnImage = errors.New("not a JPEG or PNG image")
+	// ErrDimensions is returned when the image is smaller or larger than the limits allow
+	ErrDimensions = errors.New("image dimensions out of range")
+)
+
+// Limits bound the images Process accepts. MaxPixels is checked before decoding,
+// so oversized images are rejected without allocating their pixels.
+type Limits struct {
+	MinSide   int
+	MaxSide   int
+	MaxPixels int
+}
+
+// Size is a named rendition that fits inside a MaxSide x MaxSide box
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Rendition is one encoded size of a processed image
+type Rendition struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Process decodes an uploaded image, applies its EXIF orientation and encodes one rendition
+// per size. Images are never scaled up. Re-encoding drops all metadata, including EXIF.
+// Images with transparency are encoded as PNG, everything else as JPEG.
+func Process(data []byte, limits Limits, sizes []Size, jpegQuality int) ([]Rendition, error) {
+	img, err := decode(data, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	renditions := make([]Rendition, 0, len(sizes))
+	for _, size := range sizes {
+		resized := Fit(img, size.MaxSide)
+
+		rendition, err := encode(resized, jpegQuality)
+		if err != nil {
+			return nil, err
+		}
+		rendition.Name = size.Name
+		renditions = append(renditions, *rendition)
+	}
+	return renditions, nil
+}
+
+// decode validates the header, then decodes the pixels into an upright NRGBA image
+func decode(data []byte, limits Limits) (*image.NRGBA, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, ErrNotAnImage
+	}
+
+	if config.Width < limits.MinSide || config.Height < limits.MinSide ||
+		config.Width > limits.MaxSide || config.Height > limits.MaxSide ||
+		config.Width*config.Height > limits.MaxPixels {
+		return nil, ErrDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotAnImage
+	}
+
+	nrgba := toNRGBA(img)
+	if format == "jpeg" {
+		nrgba = orient(nrgba, jpegOrientation(data))
+	}
+	return nrgba, nil
+}
+
+// encode writes PNG for images with transparency and JPEG otherwise
+func encode(img *image.NRGBA, jpegQuality int) (*Rendition, error) {
+	var buf bytes.Buffer
+	rendition := &Rendition{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		rendition.ContentType, rendition.Ext = "image/jpeg", ".jpg"
+	} else {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		rendition.ContentType, rendition.Ext = "image/png", ".png"
+	}
+
+	rendition.Data = buf.Bytes()
+	return rendition, nil
+}
+
+// toNRGBA copies any image into a zero-origin NRGBA image
+func toNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
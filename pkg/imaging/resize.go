@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"image"
+	"math"
+)
+
+// Fit scales an image down to fit inside a maxSide x maxSide box, keeping its aspect ratio.
+// Images that already fit are returned unchanged.
+func Fit(img *image.NRGBA, maxSide int) *image.NRGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	scale := float64(maxSide) / float64(max(w, h))
+	dw := max(1, int(math.Round(float64(w)*scale)))
+	dh := max(1, int(math.Round(float64(h)*scale)))
+	return resize(img, dw, dh)
+}
+
+// contribution is the share of one source pixel in a destination pixel
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// areaWeights computes, for every destination pixel along one axis, which source pixels
+// it covers and by how much. Weights of each destination pixel sum to 1.
+func areaWeights(src, dst int) [][]contribution {
+	scale := float64(src) / float64(dst)
+	weights := make([][]contribution, dst)
+
+	for d := 0; d < dst; d++ {
+		start, end := float64(d)*scale, float64(d+1)*scale
+		for s := int(start); s < src && float64(s) < end; s++ {
+			covered := math.Min(end, float64(s+1)) - math.Max(start, float64(s))
+			if covered > 0 {
+				weights[d] = append(weights[d], contribution{index: s, weight: covered / scale})
+			}
+		}
+	}
+	return weights
+}
+
+// resize downsamples with an area-averaging filter, applied horizontally then vertically.
+// Colour is weighted by alpha so transparent pixels do not bleed into their neighbours.
+func resize(img *image.NRGBA, dw, dh int) *image.NRGBA {
+	sw, sh := img.Bounds().Dx(), img.Bounds().Dy()
+	xWeights := areaWeights(sw, dw)
+	yWeights := areaWeights(sh, dh)
+
+	// Horizontal pass into premultiplied floats
+	rows := make([]float64, sh*dw*4)
+	for y := 0; y < sh; y++ {
+		for x, contributions := range xWeights {
+			var r, g, b, a float64
+			for _, c := range contributions {
+				p := img.PixOffset(c.index, y)
+				alpha := float64(img.Pix[p+3]) * c.weight
+				r += float64(img.Pix[p]) * alpha
+				g += float64(img.Pix[p+1]) * alpha
+				b += float64(img.Pix[p+2]) * alpha
+				a += alpha
+			}
+			o := (y*dw + x) * 4
+			rows[o], rows[o+1], rows[o+2], rows[o+3] = r, g, b, a
+		}
+	}
+
+	// Vertical pass, un-premultiplying into the destination
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y, contributions := range yWeights {
+		for x := 0; x < dw; x++ {
+			var r, g, b, a float64
+			for _, c := range contributions {
+				o := (c.index*dw + x) * 4
+				r += rows[o] * c.weight
+				g += rows[o+1] * c.weight
+				b += rows[o+2] * c.weight
+				a += rows[o+3] * c.weight
+			}
+
+			p := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[p] = clampByte(r / a)
+				dst.Pix[p+1] = clampByte(g / a)
+				dst.Pix[p+2] = clampByte(b / a)
+			}
+			dst.Pix[p+3] = clampByte(a)
+		}
+	}
+	return dst
+}
+
+func clampByte(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"go-gin-starter/config"
+)
+
+// Message is a rendered email with a plain text and an HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers a single message. Implementations may block; requests never call them
+// directly but go through the Outbox, which sends in the background and retries.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Mail backends selected with MAIL_BACKEND
+const (
+	BackendSMTP = "smtp"
+	BackendLog  = "log"
+	BackendFile = "file"
+)
+
+// NewMailer creates the mailer configured by MAIL_BACKEND: "smtp", "file" (writes .eml
+// files to MAIL_DIR) or "log" (default, development), which only writes to the log
+func NewMailer() (Mailer, error) {
+	switch config.MailBackend {
+	case BackendSMTP:
+		if config.SMTPHost == "" {
+			return nil, errors.New("SMTP_HOST is required for the smtp mail backend")
+		}
+		return NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom), nil
+	case BackendFile:
+		return NewSinkMailer(config.MailDir)
+	case BackendLog, "":
+		return NewSinkMailer("")
+	default:
+		return nil, fmt.Errorf("unknown MAIL_BACKEND %q", config.MailBackend)
+	}
+}
+
+// validRecipient rejects addresses that could inject headers or name more than one mailbox
+func validRecipient(to string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+	return nil
+}
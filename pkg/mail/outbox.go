@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"errors"
+	"time"
+
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// ErrOutboxFull is returned when messages are queued faster than they can be sent
+var ErrOutboxFull = errors.New("mail outbox is full")
+
+// outbox is the queue Send uses; it is set once at startup by Init
+var outbox *Outbox
+
+// delivery is a queued message and how often sending it has failed
+type delivery struct {
+	msg      Message
+	attempts int
+}
+
+// Outbox sends messages in the background so slow or unavailable mail servers never hold
+// up a request. Failed sends are retried with exponential backoff, then dropped and logged.
+type Outbox struct {
+	mailer  Mailer
+	workers int
+	queue   chan delivery
+}
+
+// NewOutbox creates a new outbox in front of a mailer
+func NewOutbox(mailer Mailer, workers, size int) *Outbox {
+	return &Outbox{
+		mailer:  mailer,
+		workers: workers,
+		queue:   make(chan delivery, size),
+	}
+}
+
+// Init sets the outbox Send queues messages on
+func Init(o *Outbox) {
+	outbox = o
+}
+
+// Enqueue queues a message without waiting for it to be sent
+func (o *Outbox) Enqueue(msg Message) error {
+	select {
+	case o.queue <- delivery{msg: msg}:
+		return nil
+	default:
+		return ErrOutboxFull
+	}
+}
+
+// Start sends queued messages until the process exits
+func (o *Outbox) Start() {
+	for i := 1; i < o.workers; i++ {
+		go o.work()
+	}
+	o.work()
+}
+
+func (o *Outbox) work() {
+	for d := range o.queue {
+		o.deliver(d)
+	}
+}
+
+// deliver sends one message and schedules a retry when that fails
+func (o *Outbox) deliver(d delivery) {
+	err := o.mailer.Send(d.msg)
+	if err == nil {
+		return
+	}
+
+	d.attempts++
+	if d.attempts >= constants.MailMaxAttempts {
+		logger.Error("Giving up sending email",
+			zap.String("to", d.msg.To),
+			zap.String("subject", d.msg.Subject),
+			zap.Int("attempts", d.attempts),
+			zap.Error(err))
+		return
+	}
+
+	delay := constants.MailRetryBaseDelay << (d.attempts - 1)
+	logger.Warn("Failed to send email, retrying",
+		zap.String("to", d.msg.To),
+		zap.String("subject", d.msg.Subject),
+		zap.Int("attempts", d.attempts),
+		zap.Duration("retry_in", delay),
+		zap.Error(err))
+	time.AfterFunc(delay, func() { o.queue <- d })
+}
+
+// Send renders a template in the recipient's locale and queues it on the outbox
+func Send(to, locale, template string, data Data) error {
+	if outbox == nil {
+		return errors.New("mail outbox is not initialized")
+	}
+
+	msg, err := Render(to, locale, template, data)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(msg)
+}
@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// unsafeFileChars are replaced in the recipient part of .eml file names
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+// SinkMailer is the development and test mailer: it logs every message and, when it has
+// a directory, also writes it there as an .eml file that mail clients can open
+type SinkMailer struct {
+	dir string
+}
+
+// NewSinkMailer creates a sink mailer; an empty dir only logs
+func NewSinkMailer(dir string) (*SinkMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &SinkMailer{dir: dir}, nil
+}
+
+// Send logs the message and stores it when a directory is configured
+func (m *SinkMailer) Send(msg Message) error {
+	data, err := buildMIME(config.MailFrom, msg)
+	if err != nil {
+		return err
+	}
+
+	fields := []zap.Field{zap.String("to", msg.To), zap.String("subject", msg.Subject)}
+	if m.dir == "" {
+		fields = append(fields, zap.String("text", msg.Text))
+		logger.Info("Email sent to log", fields...)
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	logger.Info("Email written to file", append(fields, zap.String("path", path))...)
+	return nil
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates every locale provides; each has a .txt body defining a "subject" block and an
+// .html body defining a "content" block rendered inside templates/layout.html
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateVerification  = "verification"
+	TemplateInvitation    = "invitation"
+	TemplateNotification  = "notification"
+	TemplateAccountLocked = "account_locked"
+)
+
+// Notices the notification template renders
+const (
+	NoticePasswordChanged   = "password_changed"
+	NoticeEmailChanged      = "email_changed"
+	NoticeTwoFactorEnabled  = "two_factor_enabled"
+	NoticeTwoFactorDisabled = "two_factor_disabled"
+)
+
+// DefaultLocale is used for users without a locale and for locales without templates
+const DefaultLocale = "en"
+
+// Locales are the languages mail templates exist in
+var Locales = []string{"en", "de"}
+
+// appName is available to every template as .AppName
+const appName = "Volleymate"
+
+//go:embed templates
+var templateFS embed.FS
+
+// Data are the values a template renders
+type Data map[string]interface{}
+
+// IsSupportedLocale reports whether mail can be sent in a locale
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range Locales {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLocale returns the locale to render in, e.g. "de" for "de-AT"
+func resolveLocale(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if IsSupportedLocale(locale) {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Render renders a template for a recipient in their locale
+func Render(to, locale, name string, data Data) (Message, error) {
+	locale = resolveLocale(locale)
+	dir := "templates/" + locale + "/"
+	if _, err := fs.Stat(templateFS, dir+name+".txt"); err != nil {
+		dir = "templates/" + DefaultLocale + "/"
+	}
+
+	values := Data{"AppName": appName, "Locale": locale}
+	for key, value := range data {
+		values[key] = value
+	}
+
+	textTmpl, err := texttemplate.ParseFS(templateFS, dir+name+".txt")
+	if err != nil {
+		return Message{}, err
+	}
+	var subject, text bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return Message{}, err
+	}
+	if err := textTmpl.Execute(&text, values); err != nil {
+		return Message{}, err
+	}
+	values["Subject"] = strings.TrimSpace(subject.String())
+
+	htmlTmpl, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", dir+name+".html")
+	if err != nil {
+		return Message{}, err
+	}
+	var html bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&html, "layout.html", values); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: values["Subject"].(string),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
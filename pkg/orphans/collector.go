@@ -0,0 +1,377 @@
+package orphans
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	scoutPkg "go-gin-starter/pkg/scout"
+	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"go.uber.org/zap"
+)
+
+// Prefixes are the storage folders whose objects are owned by database rows.
+// Everything else, e.g. defaults/ and quarantine/, is never collected.
+var Prefixes = []string{"avatars/", "logos/", "videos/", "scouts/", "scout-files/"}
+
+// Actions the collector can take on orphans once their grace period is over
+const (
+	ActionQuarantine = "quarantine"
+	ActionDelete     = "delete"
+)
+
+// archivedStorageClasses hold objects that cannot be copied before they are restored
+var archivedStorageClasses = map[string]bool{"GLACIER": true, "DEEP_ARCHIVE": true}
+
+// errArchived is returned when quarantining an object that would first need a restore
+var errArchived = errors.New("archived object cannot be quarantined without a restore; it is left in place")
+
+// Orphan is a stored object that no live row references
+type Orphan struct {
+	Key          string
+	Size         int64
+	StorageClass string
+	LastModified time.Time
+	FirstSeenAt  *time.Time // nil until a collection run has recorded the object
+	EligibleAt   time.Time  // when the grace period ends and the action applies
+}
+
+// PrefixSummary counts the objects below one prefix and how many of them are orphaned
+type PrefixSummary struct {
+	Objects      int
+	Bytes        int64
+	Orphans      int
+	OrphanBytes  int64
+	EligibleNow  int
+	EligibleSize int64
+}
+
+// Report is the outcome of a scan, computed without changing anything
+type Report struct {
+	Action      string
+	GracePeriod time.Duration
+	Prefixes    map[string]PrefixSummary
+	Orphans     []Orphan
+	GeneratedAt time.Time
+}
+
+// Collector finds stored objects no database row references any more, e.g. replaced
+// avatars and logos or the videos of deleted matches, and after a grace period moves
+// them to quarantine or deletes them
+type Collector struct {
+	store    storagePkg.ObjectStore
+	interval time.Duration
+	action   string
+
+	referenceRepo repositories.AssetReferenceRepository
+	orphanRepo    repositories.OrphanedObjectRepository
+}
+
+// NewCollector creates a new orphan collector; unknown actions fall back to quarantine
+func NewCollector(store storagePkg.ObjectStore, interval time.Duration, action string) *Collector {
+	if action != ActionDelete {
+		action = ActionQuarantine
+	}
+	return &Collector{
+		store:         store,
+		interval:      interval,
+		action:        action,
+		referenceRepo: repositories.NewAssetReferenceRepository(),
+		orphanRepo:    repositories.NewOrphanedObjectRepository(),
+	}
+}
+
+// Start runs a collection on every tick until the process exits
+func (c *Collector) Start() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Run()
+		<-ticker.C
+	}
+}
+
+// Scan lists the orphans and when the action would apply to each, without changing anything
+func (c *Collector) Scan() (*Report, error) {
+	now := time.Now()
+	report, err := c.scan(now)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked, err := c.orphanRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	firstSeen := make(map[string]time.Time, len(tracked))
+	for _, object := range tracked {
+		firstSeen[object.Key] = object.FirstSeenAt
+	}
+
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		if seen, ok := firstSeen[orphan.Key]; ok {
+			orphan.FirstSeenAt = &seen
+		}
+		c.setEligibility(report, orphan, now)
+	}
+	return report, nil
+}
+
+// Run records newly found orphans, forgets objects that are referenced again or gone,
+// applies the action to orphans past their grace period and purges old quarantined copies
+func (c *Collector) Run() {
+	now := time.Now()
+	report, err := c.scan(now)
+	if err != nil {
+		logger.Error("Orphan scan failed", zap.Error(err))
+		return
+	}
+
+	tracked, err := c.orphanRepo.GetAll()
+	if err != nil {
+		logger.Error("Failed to fetch tracked orphans", zap.Error(err))
+		return
+	}
+
+	orphaned := make(map[string]bool, len(report.Orphans))
+	for _, orphan := range report.Orphans {
+		orphaned[orphan.Key] = true
+	}
+	firstSeen := make(map[string]time.Time, len(tracked))
+	var resolved []string
+	for _, object := range tracked {
+		if orphaned[object.Key] {
+			firstSeen[object.Key] = object.FirstSeenAt
+		} else {
+			resolved = append(resolved, object.Key)
+		}
+	}
+	if err := c.orphanRepo.DeleteByKeys(resolved); err != nil {
+		logger.Error("Failed to forget resolved orphans", zap.Error(err))
+	}
+
+	records := make([]models.OrphanedObject, 0, len(report.Orphans))
+	var handled []string
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		seen, ok := firstSeen[orphan.Key]
+		if !ok {
+			seen = now
+		}
+		orphan.FirstSeenAt = &seen
+		c.setEligibility(report, orphan, now)
+		records = append(records, models.OrphanedObject{
+			Key:          orphan.Key,
+			Size:         orphan.Size,
+			LastModified: orphan.LastModified,
+			FirstSeenAt:  seen,
+		})
+
+		if orphan.EligibleAt.After(now) {
+			continue
+		}
+		if err := c.apply(orphan); err != nil {
+			if errors.Is(err, errArchived) {
+				logger.Warn("Archived orphan left in place", zap.String("key", orphan.Key))
+				continue
+			}
+			logger.Error("Failed to collect orphaned object",
+				zap.String("key", orphan.Key),
+				zap.String("action", c.action),
+				zap.Error(err))
+			continue
+		}
+		handled = append(handled, orphan.Key)
+		logger.Info("Orphaned object collected",
+			zap.String("key", orphan.Key),
+			zap.Int64("size", orphan.Size),
+			zap.String("action", c.action))
+	}
+
+	if err := c.orphanRepo.Track(records); err != nil {
+		logger.Error("Failed to track orphaned objects", zap.Error(err))
+	}
+	if err := c.orphanRepo.DeleteByKeys(handled); err != nil {
+		logger.Error("Failed to forget collected orphans", zap.Error(err))
+	}
+
+	purged := c.purgeQuarantine(now)
+
+	if len(report.Orphans) > 0 || purged > 0 {
+		logger.Info("Orphan collection finished",
+			zap.Int("orphans", len(report.Orphans)),
+			zap.Int("collected", len(handled)),
+			zap.Int("purged", purged))
+	}
+}
+
+// scan lists every prefix and compares it with the references in the database.
+// Eligibility is not set yet since it depends on when each orphan was first seen.
+func (c *Collector) scan(now time.Time) (*Report, error) {
+	referenced, err := c.referencedKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Action:      c.action,
+		GracePeriod: constants.OrphanGracePeriod,
+		Prefixes:    make(map[string]PrefixSummary, len(Prefixes)),
+		Orphans:     []Orphan{},
+		GeneratedAt: now,
+	}
+
+	for _, prefix := range Prefixes {
+		objects, err := c.store.List(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+
+		summary := PrefixSummary{}
+		for _, object := range objects {
+			summary.Objects++
+			summary.Bytes += object.Size
+			if referenced.contains(object.Key) {
+				continue
+			}
+			summary.Orphans++
+			summary.OrphanBytes += object.Size
+			report.Orphans = append(report.Orphans, Orphan{
+				Key:          object.Key,
+				Size:         object.Size,
+				StorageClass: object.StorageClass,
+				LastModified: object.LastModified,
+			})
+		}
+		report.Prefixes[prefix] = summary
+	}
+	return report, nil
+}
+
+// setEligibility computes when the grace period of an orphan ends and counts it in its
+// prefix summary if that is now. The grace period runs from the later of the first time
+// a run saw the object orphaned and its last write, so in-flight uploads are never touched.
+func (c *Collector) setEligibility(report *Report, orphan *Orphan, now time.Time) {
+	start := now
+	if orphan.FirstSeenAt != nil {
+		start = *orphan.FirstSeenAt
+	}
+	if orphan.LastModified.After(start) {
+		start = orphan.LastModified
+	}
+	orphan.EligibleAt = start.Add(constants.OrphanGracePeriod)
+
+	if orphan.EligibleAt.After(now) {
+		return
+	}
+	for _, prefix := range Prefixes {
+		if strings.HasPrefix(orphan.Key, prefix) {
+			summary := report.Prefixes[prefix]
+			summary.EligibleNow++
+			summary.EligibleSize += orphan.Size
+			report.Prefixes[prefix] = summary
+			return
+		}
+	}
+}
+
+// references are the object keys live rows point at, plus the live matches whose raw
+// uploads belong to the raw retention job
+type references struct {
+	keys    map[string]bool
+	matches map[string]bool
+}
+
+// contains reports whether an object is referenced. Raw uploads of live matches always
+// are: those uploaded before their keys were stored are referenced by no row, and whether
+// they are kept, archived or deleted is decided by the retention policy of their season.
+func (r references) contains(key string) bool {
+	if r.keys[key] {
+		return true
+	}
+	matchID, ok := rawUploadMatchID(key)
+	return ok && r.matches[matchID]
+}
+
+// rawUploadMatchID returns the match ID of a key in a match's raw folder,
+// videos/<season>/<competition>/<matchID>/raw/<file>
+func rawUploadMatchID(key string) (string, bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) < 4 || parts[0] != "videos" || parts[len(parts)-2] != video.RawVideoFolder {
+		return "", false
+	}
+	return parts[len(parts)-3], true
+}
+
+// referencedKeys resolves the references of all live rows to object keys
+func (c *Collector) referencedKeys() (references, error) {
+	refs, err := c.referenceRepo.GetReferences()
+	if err != nil {
+		return references{}, fmt.Errorf("failed to read references: %w", err)
+	}
+
+	referenced := references{
+		keys:    make(map[string]bool, len(refs.Values)+2*len(refs.MatchIDs)),
+		matches: make(map[string]bool, len(refs.MatchIDs)),
+	}
+	for _, value := range refs.Values {
+		// URLs of other hosts are not ours to collect
+		if key, ok := assets.Key(value); ok {
+			referenced.keys[key] = true
+		}
+	}
+	for _, matchID := range refs.MatchIDs {
+		referenced.keys[scoutPkg.BuildSourceKey(matchID)] = true
+		referenced.keys[scoutPkg.BuildParsedKey(matchID)] = true
+		referenced.matches[matchID.String()] = true
+	}
+	return referenced, nil
+}
+
+// apply quarantines or deletes one orphan. Quarantine copies the object within the store,
+// which archived objects do not allow until they are restored.
+func (c *Collector) apply(orphan *Orphan) error {
+	if c.action == ActionDelete {
+		return c.store.Delete(orphan.Key)
+	}
+
+	if archivedStorageClasses[orphan.StorageClass] {
+		return errArchived
+	}
+	if err := c.store.Copy(orphan.Key, constants.OrphanQuarantinePrefix+orphan.Key); err != nil {
+		return err
+	}
+	return c.store.Delete(orphan.Key)
+}
+
+// purgeQuarantine deletes quarantined copies older than the quarantine retention
+func (c *Collector) purgeQuarantine(now time.Time) int {
+	objects, err := c.store.List(constants.OrphanQuarantinePrefix)
+	if err != nil {
+		logger.Error("Failed to list quarantined objects", zap.Error(err))
+		return 0
+	}
+
+	purged := 0
+	for _, object := range objects {
+		if now.Sub(object.LastModified) < constants.OrphanQuarantineRetention {
+			continue
+		}
+		if err := c.store.Delete(object.Key); err != nil {
+			logger.Error("Failed to purge quarantined object", zap.String("key", object.Key), zap.Error(err))
+			continue
+		}
+		purged++
+	}
+	return purged
+}
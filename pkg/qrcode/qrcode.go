@@ -0,0 +1,208 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the light border, in modules, scanners need around a code
+const QuietZone = 4
+
+// ErrTooLong is returned for content that does not fit the largest supported version
+var ErrTooLong = errors.New("qrcode: content too long")
+
+// blockLayout describes the error correction blocks of one version at level M:
+// the EC codewords per block and the number and data size of the blocks in each group
+type blockLayout struct {
+	ecPerBlock  int
+	group1      int
+	group1Data  int
+	group2      int
+	group2Data  int
+	alignCenter []int
+}
+
+// layouts holds versions 1 to 20 at error correction level M (ISO/IEC 18004, tables 9 and E.1)
+var layouts = []blockLayout{
+	{10, 1, 16, 0, 0, nil},
+	{16, 1, 28, 0, 0, []int{6, 18}},
+	{26, 1, 44, 0, 0, []int{6, 22}},
+	{18, 2, 32, 0, 0, []int{6, 26}},
+	{24, 2, 43, 0, 0, []int{6, 30}},
+	{16, 4, 27, 0, 0, []int{6, 34}},
+	{18, 4, 31, 0, 0, []int{6, 22, 38}},
+	{22, 2, 38, 2, 39, []int{6, 24, 42}},
+	{22, 3, 36, 2, 37, []int{6, 26, 46}},
+	{26, 4, 43, 1, 44, []int{6, 28, 50}},
+	{30, 1, 50, 4, 51, []int{6, 30, 54}},
+	{22, 6, 36, 2, 37, []int{6, 32, 58}},
+	{22, 8, 37, 1, 38, []int{6, 34, 62}},
+	{24, 4, 40, 5, 41, []int{6, 26, 46, 66}},
+	{24, 5, 41, 5, 42, []int{6, 26, 48, 70}},
+	{28, 7, 45, 3, 46, []int{6, 26, 50, 74}},
+	{28, 10, 46, 1, 47, []int{6, 30, 54, 78}},
+	{26, 9, 43, 4, 44, []int{6, 30, 56, 82}},
+	{26, 3, 44, 11, 45, []int{6, 30, 58, 86}},
+	{26, 3, 41, 13, 42, []int{6, 34, 62, 90}},
+}
+
+func (l blockLayout) dataCodewords() int {
+	return l.group1*l.group1Data + l.group2*l.group2Data
+}
+
+// Code is an encoded symbol; Modules[y][x] is true for dark modules
+type Code struct {
+	Version int
+	Modules [][]bool
+}
+
+// Encode encodes content in byte mode at error correction level M, using the smallest
+// version it fits and the mask with the lowest penalty
+func Encode(content []byte) (*Code, error) {
+	version := 0
+	for v := 1; v <= len(layouts); v++ {
+		if 4+countBits(v)+8*len(content) <= 8*layouts[v-1].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addErrorCorrection(encodeData(content, version), layouts[version-1])
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(mask)
+		if penalty := m.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		m.applyMask(mask) // masks are their own inverse
+	}
+	m.applyMask(best)
+	m.drawFormatBits(best)
+
+	return &Code{Version: version, Modules: m.modules}, nil
+}
+
+// PNG renders content as a black on white PNG with scale pixels per module
+func PNG(content string, scale int) ([]byte, error) {
+	code, err := Encode([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(code.Modules)
+	side := (size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for y, row := range code.Modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+QuietZone)*scale+dx, (y+QuietZone)*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// countBits is the length of the byte mode character count of a version
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData builds the data codewords: mode, count, content, terminator and padding
+func encodeData(content []byte, version int) []byte {
+	capacity := layouts[version-1].dataCodewords()
+	var bits bitBuffer
+	bits.append(0b0100, 4) // byte mode
+	bits.append(len(content), countBits(version))
+	for _, b := range content {
+		bits.append(int(b), 8)
+	}
+
+	bits.append(0, min(4, 8*capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < 8*capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// addErrorCorrection splits the data into blocks, computes their Reed-Solomon codewords
+// and interleaves data and error correction codewords
+func addErrorCorrection(data []byte, layout blockLayout) []byte {
+	divisor := rsGenerator(layout.ecPerBlock)
+
+	var blocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < layout.group1+layout.group2; i++ {
+		size := layout.group1Data
+		if i >= layout.group1 {
+			size = layout.group2Data
+		}
+		block := data[offset : offset+size]
+		offset += size
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+	}
+
+	var result []byte
+	longest := max(layout.group1Data, layout.group2Data)
+	for i := 0; i < longest; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// bitBuffer collects bits most significant first
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
@@ -0,0 +1,400 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatStringsM are the published format information strings of level M for masks 0 to 7
+// (ISO/IEC 18004, annex C), most significant bit first
+var formatStringsM = []string{
+	"101010000010010",
+	"101000100100101",
+	"101111001111100",
+	"101101101001011",
+	"100010111111001",
+	"100000011001110",
+	"100111110010111",
+	"100101010100000",
+}
+
+// versionInformation holds the published version information of versions 7 to 20
+// (ISO/IEC 18004, annex D)
+var versionInformation = map[int]int{
+	7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3, 11: 0x0BBF6, 12: 0x0C762, 13: 0x0D847,
+	14: 0x0E60D, 15: 0x0F928, 16: 0x10B78, 17: 0x1145D, 18: 0x12A17, 19: 0x13532, 20: 0x149A6,
+}
+
+// maskPatterns are the data mask conditions of ISO/IEC 18004, table 10, with i the row
+// and j the column
+var maskPatterns = []func(i, j int) bool{
+	func(i, j int) bool { return (i+j)%2 == 0 },
+	func(i, j int) bool { return i%2 == 0 },
+	func(i, j int) bool { return j%3 == 0 },
+	func(i, j int) bool { return (i+j)%3 == 0 },
+	func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+	func(i, j int) bool { return (i*j)%2+(i*j)%3 == 0 },
+	func(i, j int) bool { return ((i*j)%2+(i*j)%3)%2 == 0 },
+	func(i, j int) bool { return ((i+j)%2+(i*j)%3)%2 == 0 },
+}
+
+// goldenHelloWorld is "HELLO WORLD" encoded in byte mode, version 1-M with mask 4
+var goldenHelloWorld = []string{
+	"#######.##..#.#######",
+	"#.....#....#..#.....#",
+	"#.###.#..#.#..#.###.#",
+	"#.###.#.#..#..#.###.#",
+	"#.###.#.###.#.#.###.#",
+	"#.....#.#..#..#.....#",
+	"#######.#.#.#.#######",
+	"........#..##........",
+	"#...#.######.#####..#",
+	"...#....#.###....####",
+	"..######..##.##.#..#.",
+	"#####...##...#.......",
+	"#####.#.#.#.#.##..##.",
+	"........#.#.####.#.##",
+	"#######.###.#.#.##.#.",
+	"#.....#..#.###.##..##",
+	"#.###.#.##.#.##...##.",
+	"#.###.#..#..#...##.##",
+	"#.###.#..###...###...",
+	"#.....#....#.#.......",
+	"#######.#########.#.#",
+}
+
+func TestEncodeGoldenMatrix(t *testing.T) {
+	code, err := Encode([]byte("HELLO WORLD"))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got := render(code)
+	if strings.Join(got, "\n") != strings.Join(goldenHelloWorld, "\n") {
+		t.Fatalf("matrix differs from golden:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(goldenHelloWorld, "\n"))
+	}
+	if content := decode(t, code); string(content) != "HELLO WORLD" {
+		t.Fatalf("golden decodes to %q", content)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		version int
+	}{
+		{name: "empty", content: nil, version: 1},
+		{name: "version 1 capacity", content: bytes.Repeat([]byte("a"), 14), version: 1},
+		{name: "version 2", content: bytes.Repeat([]byte("a"), 15), version: 2},
+		{name: "provisioning URI", content: []byte("otpauth://totp/Volley%20Club:coach%40example.com?algorithm=SHA1&digits=6&issuer=Volley+Club&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"), version: 8},
+		{name: "binary", content: []byte{0x00, 0xFF, 0x10, 0x80, 0x7F}, version: 1},
+		{name: "sixteen bit count", content: bytes.Repeat([]byte("0123456789"), 20), version: 10},
+		{name: "version 20 capacity", content: bytes.Repeat([]byte("z"), 666), version: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Encode(tt.content)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if code.Version != tt.version {
+				t.Fatalf("version = %d, want %d", code.Version, tt.version)
+			}
+			if content := decode(t, code); !bytes.Equal(content, tt.content) {
+				t.Fatalf("decoded %q, want %q", content, tt.content)
+			}
+		})
+	}
+}
+
+// TestEveryMask decodes a symbol drawn with each mask, since Encode only ever keeps one
+func TestEveryMask(t *testing.T) {
+	content := []byte("otpauth://totp/a")
+	for mask := 0; mask < 8; mask++ {
+		m := newMatrix(2)
+		m.drawFunctionPatterns()
+		m.drawCodewords(addErrorCorrection(encodeData(content, 2), layouts[1]))
+		m.applyMask(mask)
+		m.drawFormatBits(mask)
+
+		if got := decode(t, &Code{Version: 2, Modules: m.modules}); !bytes.Equal(got, content) {
+			t.Fatalf("mask %d decoded %q", mask, got)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(bytes.Repeat([]byte("z"), 667)); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("err = %v, want ErrTooLong", err)
+	}
+}
+
+// TestRSRemainder checks the error correction of the worked 1-M "HELLO WORLD" example
+// (alphanumeric mode) published with the standard's tutorials
+func TestRSRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := rsRemainder(data, rsGenerator(10)); !bytes.Equal(got, want) {
+		t.Fatalf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	png, err := PNG("otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP", 4)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Fatal("PNG output has no PNG signature")
+	}
+}
+
+func render(code *Code) []string {
+	rows := make([]string, len(code.Modules))
+	for y, row := range code.Modules {
+		var b strings.Builder
+		for _, dark := range row {
+			if dark {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return rows
+}
+
+// decode reads a symbol back the way a scanner would and fails the test on any structural
+// error: finder and timing patterns, format and version information, and the Reed-Solomon
+// syndromes of every block are all checked against the standard
+func decode(t *testing.T, code *Code) []byte {
+	t.Helper()
+
+	size := len(code.Modules)
+	version := (size - 17) / 4
+	if size != 17+4*code.Version || version < 1 || version > len(layouts) {
+		t.Fatalf("symbol is %d modules wide for version %d", size, code.Version)
+	}
+	dark := func(x, y int) bool { return code.Modules[y][x] }
+
+	// Finder patterns with their light separators
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for dy := -1; dy <= 7; dy++ {
+			for dx := -1; dx <= 7; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				ring := max(abs(dx-3), abs(dy-3))
+				if want := ring != 2 && ring != 4; dark(x, y) != want {
+					t.Fatalf("finder module (%d,%d) dark = %v", x, y, dark(x, y))
+				}
+			}
+		}
+	}
+
+	// Timing patterns
+	for i := 8; i < size-8; i++ {
+		if dark(i, 6) != (i%2 == 0) || dark(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern broken at %d", i)
+		}
+	}
+
+	// Format information, both copies, and the dark module
+	var first, second strings.Builder
+	for _, p := range [][2]int{{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8}, {8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0}} {
+		first.WriteString(bit(dark(p[0], p[1])))
+	}
+	for y := size - 1; y >= size-7; y-- {
+		second.WriteString(bit(dark(8, y)))
+	}
+	for x := size - 8; x < size; x++ {
+		second.WriteString(bit(dark(x, 8)))
+	}
+	if first.String() != second.String() {
+		t.Fatalf("format copies differ: %s and %s", first.String(), second.String())
+	}
+	mask := -1
+	for i, format := range formatStringsM {
+		if format == first.String() {
+			mask = i
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %s is not a level M format string", first.String())
+	}
+	if !dark(8, size-8) {
+		t.Fatal("dark module is light")
+	}
+
+	// Version information, both copies
+	if version >= 7 {
+		var below, right int
+		for i := 17; i >= 0; i-- {
+			below = below<<1 | boolBit(dark(i/3, size-11+i%3))
+			right = right<<1 | boolBit(dark(size-11+i%3, i/3))
+		}
+		if below != versionInformation[version] || right != versionInformation[version] {
+			t.Fatalf("version information %#x and %#x, want %#x", below, right, versionInformation[version])
+		}
+	}
+
+	// Read the codewords in placement order, skipping function modules
+	reference := newMatrix(version)
+	reference.drawFunctionPatterns()
+	var bits []bool
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for _, x := range []int{right, right - 1} {
+				if !reference.isFunction[y][x] {
+					bits = append(bits, dark(x, y) != maskPatterns[mask](y, x))
+				}
+			}
+		}
+		upward = !upward
+	}
+
+	layout := layouts[version-1]
+	blockCount := layout.group1 + layout.group2
+	total := layout.dataCodewords() + blockCount*layout.ecPerBlock
+	if len(bits) < 8*total {
+		t.Fatalf("symbol holds %d bits, want at least %d", len(bits), 8*total)
+	}
+	codewords := make([]byte, total)
+	for i := 0; i < 8*total; i++ {
+		if bits[i] {
+			codewords[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	// De-interleave and check that every block is a Reed-Solomon codeword
+	blocks := make([][]byte, blockCount)
+	next := 0
+	for i := 0; i < max(layout.group1Data, layout.group2Data); i++ {
+		for b := range blocks {
+			size := layout.group1Data
+			if b >= layout.group1 {
+				size = layout.group2Data
+			}
+			if i < size {
+				blocks[b] = append(blocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[next])
+			next++
+		}
+	}
+	for b, block := range blocks {
+		for k := 0; k < layout.ecPerBlock; k++ {
+			if s := syndrome(block, k); s != 0 {
+				t.Fatalf("block %d syndrome %d = %d", b, k, s)
+			}
+		}
+	}
+
+	// Byte mode segment, terminator and padding
+	reader := bitReader{data: data}
+	if mode := reader.read(4); mode != 0b0100 {
+		t.Fatalf("mode indicator = %04b, want byte mode", mode)
+	}
+	countLength := 8
+	if version >= 10 {
+		countLength = 16
+	}
+	count := reader.read(countLength)
+	content := make([]byte, count)
+	for i := range content {
+		content[i] = byte(reader.read(8))
+	}
+	if remaining := 8*len(data) - reader.pos; remaining > 0 && reader.read(min(4, remaining)) != 0 {
+		t.Fatal("terminator is not zero")
+	}
+	reader.pos = (reader.pos + 7) / 8 * 8
+	for pad := 0xEC; reader.pos < 8*len(data); pad ^= 0xEC ^ 0x11 {
+		if got := reader.read(8); got != pad {
+			t.Fatalf("pad codeword = %#x, want %#x", got, pad)
+		}
+	}
+	return content
+}
+
+// syndrome evaluates a codeword, highest power first, at alpha^k
+func syndrome(codeword []byte, k int) byte {
+	point := byte(1)
+	for i := 0; i < k; i++ {
+		point = times2(point)
+	}
+	var result byte
+	for _, c := range codeword {
+		result = multiply(result, point) ^ c
+	}
+	return result
+}
+
+// multiply is GF(2^8) multiplication by shift and add, independent of the encoder's
+func multiply(x, y byte) byte {
+	var result byte
+	for y != 0 {
+		if y&1 == 1 {
+			result ^= x
+		}
+		x = times2(x)
+		y >>= 1
+	}
+	return result
+}
+
+func times2(x byte) byte {
+	if x&0x80 != 0 {
+		return x<<1 ^ 0x1D
+	}
+	return x << 1
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value = value<<1 | int(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return value
+}
+
+func bit(dark bool) string {
+	return fmt.Sprint(boolBit(dark))
+}
+
+func boolBit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,17 @@
+package scout
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BuildSourceKey returns the key of the uploaded DataVolley file of a match
+func BuildSourceKey(matchID uuid.UUID) string {
+	return fmt.Sprintf("scouts/%s.dvw", matchID.String())
+}
+
+// BuildParsedKey returns the key of the parsed scout JSON of a match
+func BuildParsedKey(matchID uuid.UUID) string {
+	return fmt.Sprintf("scout-files/%s.json", matchID.String())
+}
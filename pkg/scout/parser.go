@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"go-gin-starter/dto"
-	httpPkg "go-gin-starter/pkg/http"
 	"net/http"
 	"net/url"
 	"os"
@@ -18,14 +17,8 @@ type ScoutParseResponse struct {
 	Message  string                 `json:"message"`
 }
 
-// ExtractScoutMetadata extracts basic metadata from a scout JSON file
-func ExtractScoutMetadata(jsonURL string) (*dto.ScoutMetadataResponse, error) {
-	// Fetch JSON from S3
-	jsonData, err := httpPkg.FetchJSONFromS3(jsonURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JSON: %w", err)
-	}
-
+// ExtractScoutMetadata extracts basic metadata from a parsed scout JSON file
+func ExtractScoutMetadata(jsonData map[string]interface{}) (*dto.ScoutMetadataResponse, error) {
 	// Extract metadata from the JSON structure
 	matchInfo, ok := jsonData["match_info"].(map[string]interface{})
 	if !ok {
@@ -0,0 +1,60 @@
+package scout
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RallyTimecode is the span of one scouted rally in the scoutman's video, in milliseconds
+type RallyTimecode struct {
+	StartMs int64
+	EndMs   int64
+}
+
+// ExtractRallyTimecodes groups the scouted actions of a parsed scout file into rallies.
+// The parser lists actions under "plays" with DataVolley's video_time (seconds into the
+// video) and point_id; a rally runs from its first to its last timecoded action.
+// Actions without a video time (files scouted without video) are skipped.
+func ExtractRallyTimecodes(jsonData map[string]interface{}) ([]RallyTimecode, error) {
+	plays, ok := jsonData["plays"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid plays structure")
+	}
+
+	spans := make(map[string]*RallyTimecode)
+	for _, item := range plays {
+		play, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		seconds, ok := play["video_time"].(float64)
+		if !ok || seconds < 0 {
+			continue
+		}
+		pointID := fmt.Sprint(play["point_id"])
+		if play["point_id"] == nil {
+			continue
+		}
+
+		ms := int64(seconds * 1000)
+		span, ok := spans[pointID]
+		if !ok {
+			spans[pointID] = &RallyTimecode{StartMs: ms, EndMs: ms}
+			continue
+		}
+		if ms < span.StartMs {
+			span.StartMs = ms
+		}
+		if ms > span.EndMs {
+			span.EndMs = ms
+		}
+	}
+
+	rallies := make([]RallyTimecode, 0, len(spans))
+	for _, span := range spans {
+		rallies = append(rallies, *span)
+	}
+	sort.Slice(rallies, func(i, j int) bool { return rallies[i].StartMs < rallies[j].StartMs })
+	return rallies, nil
+}
@@ -0,0 +1,562 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalStore keeps objects on the local filesystem so development runs without AWS.
+// It serves its public and presigned URLs itself (see ServeHTTP), including multipart
+// part uploads, so clients use it exactly like S3. Storage classes are only recorded.
+//
+// Layout below root:
+//
+//	objects/<key>                  object data
+//	meta/<key>.json                content type, tags and storage class
+//	multipart/<upload id>/         upload info and one file per part
+type LocalStore struct {
+	root      string
+	publicURL string
+	secret    []byte
+}
+
+// localMeta is stored next to every object
+type localMeta struct {
+	ContentType  string `json:"content_type"`
+	Tagging      string `json:"tagging,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+}
+
+// localUpload describes a pending multipart upload
+type localUpload struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Tagging     string    `json:"tagging,omitempty"`
+	Initiated   time.Time `json:"initiated"`
+}
+
+// NewLocalStore creates a store below root whose objects are served from publicURL.
+// The secret signs presigned URLs.
+func NewLocalStore(root, publicURL string, secret []byte) (*LocalStore, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("local storage needs a signing secret")
+	}
+
+	for _, dir := range []string{"objects", "meta", "multipart"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &LocalStore{
+		root:      root,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		secret:    secret,
+	}, nil
+}
+
+// Put writes body to key through a temporary file, so readers never see a partial object
+func (s *LocalStore) Put(key string, body io.Reader, opts PutOptions) error {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(objectPath, body); err != nil {
+		return err
+	}
+	return s.writeMeta(key, localMeta{ContentType: opts.ContentType, Tagging: opts.Tagging})
+}
+
+// Get opens an object for reading
+func (s *LocalStore) Get(key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Head(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objectPath, _ := s.objectPath(key)
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, nil, translateFSError(err)
+	}
+	return file, info, nil
+}
+
+// Delete removes an object and its metadata
+func (s *LocalStore) Delete(key string) error {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(s.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Copy duplicates an object and its metadata
+func (s *LocalStore) Copy(srcKey, dstKey string) error {
+	body, _, err := s.Get(srcKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dstPath, err := s.objectPath(dstKey)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(dstPath, body); err != nil {
+		return err
+	}
+	return s.writeMeta(dstKey, s.readMeta(srcKey))
+}
+
+// Head reads the metadata of an object
+func (s *LocalStore) Head(key string) (*ObjectInfo, error) {
+	objectPath, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(objectPath)
+	if err != nil {
+		return nil, translateFSError(err)
+	}
+	if stat.IsDir() {
+		return nil, ErrNotFound
+	}
+
+	meta := s.readMeta(key)
+	return &ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  meta.ContentType,
+		StorageClass: meta.StorageClass,
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+// List returns every object below prefix
+func (s *LocalStore) List(prefix string) ([]ObjectInfo, error) {
+	objectsDir := filepath.Join(s.root, "objects")
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(objectsDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(objectsDir, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		meta := s.readMeta(key)
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  meta.ContentType,
+			StorageClass: meta.StorageClass,
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// PresignGet returns a signed download URL
+func (s *LocalStore) PresignGet(key string, expiry time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expiry, nil)
+}
+
+// PresignPut returns a signed upload URL
+func (s *LocalStore) PresignPut(key, contentType string, expiry time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, expiry, nil)
+}
+
+// PublicURL returns the URL the store serves key from
+func (s *LocalStore) PublicURL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// ObjectKey extracts the key from a URL returned by PublicURL
+func (s *LocalStore) ObjectKey(rawURL string) (string, error) {
+	return keyBelowBase(rawURL, s.publicURL)
+}
+
+// Transition records the storage class and tags; the data stays where it is
+func (s *LocalStore) Transition(key, storageClass, tagging string) error {
+	if _, err := s.Head(key); err != nil {
+		return err
+	}
+
+	meta := s.readMeta(key)
+	meta.StorageClass = storageClass
+	meta.Tagging = tagging
+	return s.writeMeta(key, meta)
+}
+
+// CreateMultipartUpload starts a multipart upload and returns its ID
+func (s *LocalStore) CreateMultipartUpload(key, contentType, tagging string) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.New().String()
+	if err := os.MkdirAll(s.uploadDir(uploadID), 0o755); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(localUpload{Key: key, ContentType: contentType, Tagging: tagging, Initiated: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	return uploadID, os.WriteFile(filepath.Join(s.uploadDir(uploadID), "upload.json"), data, 0o644)
+}
+
+// PresignUploadParts returns a signed PUT URL for every part
+func (s *LocalStore) PresignUploadParts(key, uploadID string, partCount int64, expiry time.Duration) ([]PresignedPart, error) {
+	parts := make([]PresignedPart, 0, partCount)
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		url, err := s.presign(http.MethodPut, key, expiry, map[string]string{
+			"upload_id":   uploadID,
+			"part_number": strconv.FormatInt(partNumber, 10),
+		})
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, PresignedPart{PartNumber: partNumber, URL: url})
+	}
+	return parts, nil
+}
+
+// CompleteMultipartUpload checks every part against its ETag and concatenates them into the object
+func (s *LocalStore) CompleteMultipartUpload(key, uploadID string, parts []CompletedPart) error {
+	upload, err := s.readUpload(key, uploadID)
+	if err != nil {
+		return err
+	}
+
+	sorted := append([]CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	readers := make([]io.Reader, 0, len(sorted))
+	for _, part := range sorted {
+		partPath := s.partPath(uploadID, part.PartNumber)
+		etag, err := fileETag(partPath)
+		if err != nil {
+			return fmt.Errorf("part %d: %w", part.PartNumber, translateFSError(err))
+		}
+		if etag != strings.Trim(part.ETag, `"`) {
+			return fmt.Errorf("part %d: ETag mismatch", part.PartNumber)
+		}
+
+		file, err := os.Open(partPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		readers = append(readers, file)
+	}
+
+	if err := s.Put(key, io.MultiReader(readers...), PutOptions{ContentType: upload.ContentType, Tagging: upload.Tagging}); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.uploadDir(uploadID))
+}
+
+// AbortMultipartUpload discards an upload and its parts
+func (s *LocalStore) AbortMultipartUpload(key, uploadID string) error {
+	if _, err := s.readUpload(key, uploadID); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.uploadDir(uploadID))
+}
+
+// ListStaleMultipartUploads returns uploads below prefix that were initiated before olderThan
+func (s *LocalStore) ListStaleMultipartUploads(prefix string, olderThan time.Time) ([]MultipartUpload, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, "multipart"))
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []MultipartUpload
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(s.uploadDir(entry.Name()), "upload.json"))
+		if err != nil {
+			continue
+		}
+		var upload localUpload
+		if err := json.Unmarshal(data, &upload); err != nil {
+			continue
+		}
+		if strings.HasPrefix(upload.Key, prefix) && upload.Initiated.Before(olderThan) {
+			stale = append(stale, MultipartUpload{Key: upload.Key, UploadID: entry.Name(), Initiated: upload.Initiated})
+		}
+	}
+	return stale, nil
+}
+
+// ServeHTTP serves GET/HEAD for public and presigned URLs and PUT for presigned uploads.
+// Mount it so that PublicURL(key) reaches it with the key as the remaining path.
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if query.Get("signature") != "" && !s.verify(http.MethodGet, key, query) {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+
+		body, info, err := s.Get(key)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer body.Close()
+
+		if info.ContentType != "" {
+			w.Header().Set("Content-Type", info.ContentType)
+		}
+		http.ServeContent(w, r, path.Base(key), info.LastModified, body.(io.ReadSeeker))
+
+	case http.MethodPut:
+		if !s.verify(http.MethodPut, key, query) {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+
+		if uploadID := query.Get("upload_id"); uploadID != "" {
+			s.servePartUpload(w, r, key, uploadID, query.Get("part_number"))
+			return
+		}
+
+		if err := s.Put(key, r.Body, PutOptions{ContentType: r.Header.Get("Content-Type")}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// servePartUpload stores one part of a multipart upload and answers with its ETag like S3
+func (s *LocalStore) servePartUpload(w http.ResponseWriter, r *http.Request, key, uploadID, partNumber string) {
+	number, err := strconv.ParseInt(partNumber, 10, 64)
+	if err != nil || number < 1 {
+		http.Error(w, "invalid part number", http.StatusBadRequest)
+		return
+	}
+	if _, err := s.readUpload(key, uploadID); err != nil {
+		http.Error(w, "unknown upload", http.StatusNotFound)
+		return
+	}
+
+	partPath := s.partPath(uploadID, number)
+	if err := writeFileAtomic(partPath, r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	etag, err := fileETag(partPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", `"`+etag+`"`)
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
+	w.WriteHeader(http.StatusOK)
+}
+
+// presign builds a URL signed for method and key that is valid until expiry
+func (s *LocalStore) presign(method, key string, expiry time.Duration, params map[string]string) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	query.Set("signature", s.signature(method, key, query))
+
+	return s.PublicURL(key) + "?" + query.Encode(), nil
+}
+
+// verify checks the signature and expiry of a presigned request
+func (s *LocalStore) verify(method, key string, query url.Values) bool {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	expected := s.signature(method, key, query)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+// signature is the HMAC of the method, key and every query parameter except the signature itself
+func (s *LocalStore) signature(method, key string, query url.Values) string {
+	signed := url.Values{}
+	for name, values := range query {
+		if name != "signature" {
+			signed[name] = values
+		}
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + signed.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// objectPath maps a key to its file, rejecting keys that would escape the store
+func (s *LocalStore) objectPath(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return filepath.Join(s.root, "objects", filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) metaPath(key string) string {
+	return filepath.Join(s.root, "meta", filepath.FromSlash(key)+".json")
+}
+
+func (s *LocalStore) uploadDir(uploadID string) string {
+	return filepath.Join(s.root, "multipart", filepath.Base(uploadID))
+}
+
+func (s *LocalStore) partPath(uploadID string, partNumber int64) string {
+	return filepath.Join(s.uploadDir(uploadID), fmt.Sprintf("part-%05d", partNumber))
+}
+
+// readMeta returns the stored metadata of an object, or empty metadata if there is none
+func (s *LocalStore) readMeta(key string) localMeta {
+	var meta localMeta
+	if data, err := os.ReadFile(s.metaPath(key)); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+func (s *LocalStore) writeMeta(key string, meta localMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.metaPath(key)), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.metaPath(key), data, 0o644)
+}
+
+// readUpload loads a pending multipart upload and checks that it belongs to key
+func (s *LocalStore) readUpload(key, uploadID string) (*localUpload, error) {
+	data, err := os.ReadFile(filepath.Join(s.uploadDir(uploadID), "upload.json"))
+	if err != nil {
+		return nil, translateFSError(err)
+	}
+
+	var upload localUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, err
+	}
+	if upload.Key != key {
+		return nil, ErrNotFound
+	}
+	return &upload, nil
+}
+
+// writeFileAtomic writes body to a temporary file next to target and renames it into place
+func writeFileAtomic(target string, body io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// fileETag computes an S3-style ETag (hex MD5) of a file
+func fileETag(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// translateFSError maps missing files to ErrNotFound
+func translateFSError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStoreObjectPath(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root, "http://localhost:8080/storage", []byte("secret"))
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "avatars/user.png", want: filepath.Join(root, "objects", "avatars", "user.png")},
+		{key: "videos/2024/match/raw/a..b.mp4", want: filepath.Join(root, "objects", "videos", "2024", "match", "raw", "a..b.mp4")},
+		{key: "...", want: filepath.Join(root, "objects", "...")},
+		{key: ""},
+		{key: "."},
+		{key: ".."},
+		{key: "../secret"},
+		{key: "avatars/.."},
+		{key: "avatars/../../secret"},
+		{key: "avatars/./user.png"},
+		{key: "avatars//user.png"},
+		{key: "avatars/"},
+		{key: "/etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		got, err := store.objectPath(tt.key)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("objectPath(%q) = %q, want an error", tt.key, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("objectPath(%q) returned error: %v", tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("objectPath(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Store keeps objects in an S3 bucket, or any S3-compatible service such as MinIO
+type S3Store struct {
+	client    *s3.S3
+	uploader  *s3manager.Uploader
+	bucket    string
+	publicURL string // serves every key below this URL instead of CloudFront when set
+}
+
+// NewS3Store creates a store on the given bucket. A custom endpoint (e.g. MinIO) usually
+// needs path-style addressing; publicURL overrides the CloudFront domains for such setups.
+func NewS3Store(sess *session.Session, bucket, endpoint string, forcePathStyle bool, publicURL string) *S3Store {
+	cfg := aws.NewConfig()
+	if endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	if forcePathStyle {
+		cfg = cfg.WithS3ForcePathStyle(true)
+	}
+
+	client := s3.New(sess, cfg)
+	return &S3Store{
+		client:    client,
+		uploader:  s3manager.NewUploaderWithClient(client),
+		bucket:    bucket,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// Put streams body into key with a multipart upload where needed
+func (s *S3Store) Put(key string, body io.Reader, opts PutOptions) error {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if opts.Tagging != "" {
+		input.Tagging = aws.String(opts.Tagging)
+	}
+
+	_, err := s.uploader.Upload(input)
+	return err
+}
+
+// Get opens an object for reading
+func (s *S3Store) Get(key string) (io.ReadCloser, *ObjectInfo, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, translateS3Error(err)
+	}
+
+	return out.Body, &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(out.ContentLength),
+		ContentType:  aws.StringValue(out.ContentType),
+		ETag:         aws.StringValue(out.ETag),
+		StorageClass: aws.StringValue(out.StorageClass),
+		LastModified: aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+// Delete removes an object from the bucket
+func (s *S3Store) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// Copy duplicates an object server-side, so the data never passes through this process.
+// Single-request copies are limited to 5GB, which MaxVideoFileSize keeps raw uploads below;
+// objects in GLACIER or DEEP_ARCHIVE have to be restored first.
+func (s *S3Store) Copy(srcKey, dstKey string) error {
+	_, err := s.client.CopyObject(&s3.CopyObjectInput{
+		Bucket:            aws.String(s.bucket),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String(url.PathEscape(s.bucket + "/" + srcKey)),
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+		TaggingDirective:  aws.String(s3.TaggingDirectiveCopy),
+	})
+	return translateS3Error(err)
+}
+
+// Head fetches the metadata of an object without downloading it
+func (s *S3Store) Head(key string) (*ObjectInfo, error) {
+	out, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, translateS3Error(err)
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(out.ContentLength),
+		ContentType:  aws.StringValue(out.ContentType),
+		ETag:         aws.StringValue(out.ETag),
+		StorageClass: aws.StringValue(out.StorageClass),
+		LastModified: aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+// List returns every object below prefix
+func (s *S3Store) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				ETag:         aws.StringValue(object.ETag),
+				StorageClass: aws.StringValue(object.StorageClass),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// PresignGet presigns a GetObject request
+func (s *S3Store) PresignGet(key string, expiry time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(expiry)
+}
+
+// PresignPut presigns a PutObject request; the client must send the same Content-Type
+func (s *S3Store) PresignPut(key, contentType string, expiry time.Duration) (string, error) {
+	req, _ := s.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	return req.Presign(expiry)
+}
+
+// PublicURL returns the CloudFront URL of a key, or the URL below the configured public base
+func (s *S3Store) PublicURL(key string) string {
+	if s.publicURL != "" {
+		return s.publicURL + "/" + key
+	}
+	return cloudFrontURL(key)
+}
+
+// ObjectKey extracts the key from a CloudFront or public base URL
+func (s *S3Store) ObjectKey(rawURL string) (string, error) {
+	return keyBelowBase(rawURL, s.publicURL)
+}
+
+// Transition rewrites an object in place with a new storage class and tag set.
+// Single-request copies are limited to 5GB, which MaxVideoFileSize keeps raw uploads below.
+func (s *S3Store) Transition(key, storageClass, tagging string) error {
+	_, err := s.client.CopyObject(&s3.CopyObjectInput{
+		Bucket:            aws.String(s.bucket),
+		Key:               aws.String(key),
+		CopySource:        aws.String(url.PathEscape(s.bucket + "/" + key)),
+		StorageClass:      aws.String(storageClass),
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+		TaggingDirective:  aws.String(s3.TaggingDirectiveReplace),
+		Tagging:           aws.String(tagging),
+	})
+	return err
+}
+
+// CreateMultipartUpload starts a multipart upload and returns the S3 upload ID
+func (s *S3Store) CreateMultipartUpload(key, contentType, tagging string) (string, error) {
+	out, err := s.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Tagging:     aws.String(tagging),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.UploadId), nil
+}
+
+// PresignUploadParts presigns an UploadPart request for every part of a multipart upload
+func (s *S3Store) PresignUploadParts(key, uploadID string, partCount int64, expiry time.Duration) ([]PresignedPart, error) {
+	parts := make([]PresignedPart, 0, partCount)
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		req, _ := s.client.UploadPartRequest(&s3.UploadPartInput{
+			Bucket:     aws.String(s.bucket),
+			Key:        aws.String(key),
+			UploadId:   aws.String(uploadID),
+			PartNumber: aws.Int64(partNumber),
+		})
+
+		url, err := req.Presign(expiry)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, PresignedPart{PartNumber: partNumber, URL: url})
+	}
+	return parts, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final object
+func (s *S3Store) CompleteMultipartUpload(key, uploadID string, parts []CompletedPart) error {
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, &s3.CompletedPart{
+			PartNumber: aws.Int64(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		})
+	}
+
+	_, err := s.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	return err
+}
+
+// AbortMultipartUpload discards a multipart upload and all parts uploaded so far
+func (s *S3Store) AbortMultipartUpload(key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+// ListStaleMultipartUploads returns multipart uploads below prefix that were initiated before olderThan
+func (s *S3Store) ListStaleMultipartUploads(prefix string, olderThan time.Time) ([]MultipartUpload, error) {
+	var stale []MultipartUpload
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	for {
+		out, err := s.client.ListMultipartUploads(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, upload := range out.Uploads {
+			if upload.Initiated != nil && upload.Initiated.Before(olderThan) {
+				stale = append(stale, MultipartUpload{
+					Key:       aws.StringValue(upload.Key),
+					UploadID:  aws.StringValue(upload.UploadId),
+					Initiated: aws.TimeValue(upload.Initiated),
+				})
+			}
+		}
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		input.KeyMarker = out.NextKeyMarker
+		input.UploadIdMarker = out.NextUploadIdMarker
+	}
+
+	return stale, nil
+}
+
+// translateS3Error maps S3's missing-object errors to ErrNotFound
+func translateS3Error(err error) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		if aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound" {
+			return ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-gin-starter/config"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// ErrNotFound is returned when an object does not exist in the store
+var ErrNotFound = errors.New("object not found")
+
+var errEmptyURL = errors.New("empty object URL")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	StorageClass string
+	LastModified time.Time
+}
+
+// PutOptions holds the optional attributes of a stored object
+type PutOptions struct {
+	ContentType string
+	Tagging     string // URL-encoded tag set, e.g. "storage=raw"
+}
+
+// ObjectStore is the storage every upload, download and URL goes through
+type ObjectStore interface {
+	// Put streams body into key, replacing any existing object
+	Put(key string, body io.Reader, opts PutOptions) error
+	// Get opens an object for reading; the caller closes it
+	Get(key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(key string) error
+	// Copy duplicates an object within the store, keeping its content type and tags
+	Copy(srcKey, dstKey string) error
+	// Head fetches the metadata of an object without reading it
+	Head(key string) (*ObjectInfo, error)
+	// List returns every object below prefix, ordered by key
+	List(prefix string) ([]ObjectInfo, error)
+	// PresignGet returns a URL that downloads key until expiry
+	PresignGet(key string, expiry time.Duration) (string, error)
+	// PresignPut returns a URL that uploads key with the given content type until expiry
+	PresignPut(key, contentType string, expiry time.Duration) (string, error)
+	// PublicURL returns the URL clients load key from
+	PublicURL(key string) string
+	// ObjectKey extracts the key from a URL returned by PublicURL
+	ObjectKey(rawURL string) (string, error)
+}
+
+// PresignedPart holds the presigned URL a client uses to PUT a single multipart chunk
+type PresignedPart struct {
+	PartNumber int64
+	URL        string
+}
+
+// CompletedPart identifies an uploaded chunk by the ETag its PUT returned
+type CompletedPart struct {
+	PartNumber int64
+	ETag       string
+}
+
+// MultipartUpload is an upload that was started but not completed or aborted
+type MultipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// MultipartStore accepts large uploads in parts that clients PUT directly
+type MultipartStore interface {
+	CreateMultipartUpload(key, contentType, tagging string) (string, error)
+	PresignUploadParts(key, uploadID string, partCount int64, expiry time.Duration) ([]PresignedPart, error)
+	CompleteMultipartUpload(key, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(key, uploadID string) error
+	ListStaleMultipartUploads(prefix string, olderThan time.Time) ([]MultipartUpload, error)
+}
+
+// Archiver moves objects to a colder storage class
+type Archiver interface {
+	// Transition rewrites an object in place with a new storage class and tag set
+	Transition(key, storageClass, tagging string) error
+}
+
+// Store is implemented by every backend; consumers depend on the narrowest interface they need
+type Store interface {
+	ObjectStore
+	MultipartStore
+	Archiver
+}
+
+// NewStore creates the backend selected by STORAGE_BACKEND: "s3" (default, also for MinIO
+// through S3_ENDPOINT) or "local" for development without AWS
+func NewStore(sess *session.Session) (Store, error) {
+	switch config.StorageBackend {
+	case "", "s3":
+		return NewS3Store(sess, config.AWSBucketName, config.S3Endpoint, config.S3ForcePathStyle, config.StoragePublicURL), nil
+	case "local":
+		return NewLocalStore(config.LocalStorageDir, config.StoragePublicURL, []byte(config.LocalStorageSecret))
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.StorageBackend)
+	}
+}
+
+// GetJSON reads an object and parses it as a JSON object
+func GetJSON(store ObjectStore, key string) (map[string]interface{}, error) {
+	body, _, err := store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JSON: %w", err)
+	}
+	defer body.Close()
+
+	var jsonData map[string]interface{}
+	if err := json.NewDecoder(body).Decode(&jsonData); err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
+	}
+	return jsonData, nil
+}
+
+// cloudFrontURL returns the CloudFront URL of a key, picking the distribution by asset class
+func cloudFrontURL(key string) string {
+	var domain string
+	switch {
+	case strings.HasPrefix(key, "videos/"):
+		domain = config.VideoCloudFrontDomain
+	case strings.HasPrefix(key, "scout-files/"), strings.HasPrefix(key, "scouts/"):
+		domain = config.ScoutCloudFrontDomain
+	default:
+		domain = config.AssetCloudFrontDomain
+	}
+	return fmt.Sprintf("https://%s/%s", domain, key)
+}
+
+// keyBelowBase extracts the key from a URL, stripping the path of base when the URL lives below it
+func keyBelowBase(rawURL, base string) (string, error) {
+	if rawURL == "" {
+		return "", errEmptyURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	key := parsed.Path
+	if base != "" {
+		if baseURL, err := url.Parse(base); err == nil && parsed.Host == baseURL.Host {
+			key = strings.TrimPrefix(key, strings.TrimSuffix(baseURL.Path, "/"))
+		}
+	}
+
+	key = strings.TrimPrefix(key, "/")
+	if key == "" {
+		return "", errEmptyURL
+	}
+	return key, nil
+}
@@ -1,11 +1,17 @@
 package upload
 
 import (
+	"bytes"
 	"errors"
+	"go-gin-starter/models"
 	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/imaging"
 	"go-gin-starter/pkg/logger"
 	"go-gin-starter/pkg/storage"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -29,63 +35,148 @@ const (
 // FileUploadService defines the interface for file upload operations
 type FileUploadService interface {
 	ValidateAndUploadFile(ctx *gin.Context, fileField string, fileType FileType, maxSize int64) (string, error)
+	ValidateAndUploadImage(ctx *gin.Context, fileField string, fileType FileType, maxSize int64) (models.ImageVariants, error)
+}
+
+// imageSizes lists the renditions stored for each image file type
+var imageSizes = map[FileType][]imaging.Size{
+	TeamLogo: {
+		{Name: models.ImageSizeSmall, MaxSide: 64},
+		{Name: models.ImageSizeMedium, MaxSide: 256},
+		{Name: models.ImageSizeLarge, MaxSide: 512},
+	},
+	SeasonLogo: {
+		{Name: models.ImageSizeSmall, MaxSide: 64},
+		{Name: models.ImageSizeMedium, MaxSide: 256},
+		{Name: models.ImageSizeLarge, MaxSide: 512},
+	},
+	UserAvatar: {
+		{Name: models.ImageSizeSmall, MaxSide: 64},
+		{Name: models.ImageSizeMedium, MaxSide: 128},
+		{Name: models.ImageSizeLarge, MaxSide: 256},
+	},
 }
 
 // FileUploadServiceImpl implements FileUploadService
-type FileUploadServiceImpl struct{}
+type FileUploadServiceImpl struct {
+	store storage.ObjectStore
+}
 
 // NewFileUploadService creates a new instance of FileUploadService
-func NewFileUploadService() FileUploadService {
-	return &FileUploadServiceImpl{}
+func NewFileUploadService(store storage.ObjectStore) FileUploadService {
+	return &FileUploadServiceImpl{store: store}
 }
 
-// ValidateAndUploadFile validates and uploads a file
+// ValidateAndUploadFile validates and uploads a file and returns its object key
 func (s *FileUploadServiceImpl) ValidateAndUploadFile(ctx *gin.Context, fileField string, fileType FileType, maxSize int64) (string, error) {
+	src, fileHeader, contentType, err := s.openFormFile(ctx, fileField, fileType, maxSize)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	ext := filepath.Ext(fileHeader.Filename)
+
+	// Generate path based on file type
+	objectKey := s.generateObjectKey(fileType, ext)
+
+	if err := s.store.Put(objectKey, src, storage.PutOptions{ContentType: contentType}); err != nil {
+		logger.Error("Object upload failed", zap.Error(err), zap.String("key", objectKey))
+		return "", errors.New(constants.ErrUploadFailed)
+	}
+
+	return objectKey, nil
+}
+
+// ValidateAndUploadImage decodes an uploaded logo or avatar, re-encodes it without metadata
+// into the renditions of its file type and uploads them next to each other.
+// It returns the object key of every rendition by name.
+func (s *FileUploadServiceImpl) ValidateAndUploadImage(ctx *gin.Context, fileField string, fileType FileType, maxSize int64) (models.ImageVariants, error) {
+	sizes, ok := imageSizes[fileType]
+	if !ok {
+		return nil, errors.New(constants.ErrInvalidFileType)
+	}
+
+	src, _, _, err := s.openFormFile(ctx, fileField, fileType, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		logger.Error("Failed to read uploaded image", zap.Error(err))
+		return nil, errors.New(constants.ErrUploadFailed)
+	}
+
+	limits := imaging.Limits{
+		MinSide:   constants.MinImageSide,
+		MaxSide:   constants.MaxImageSide,
+		MaxPixels: constants.MaxImagePixels,
+	}
+	renditions, err := imaging.Process(data, limits, sizes, constants.ImageJPEGQuality)
+	switch {
+	case errors.Is(err, imaging.ErrNotAnImage):
+		return nil, errors.New(constants.ErrInvalidImage)
+	case errors.Is(err, imaging.ErrDimensions):
+		return nil, errors.New(constants.ErrImageDimensions)
+	case err != nil:
+		logger.Error("Image processing failed", zap.Error(err))
+		return nil, errors.New(constants.ErrUploadFailed)
+	}
+
+	// All renditions of one upload share a folder, e.g. logos/teams/<uuid>/small.png
+	folder := s.generateObjectKey(fileType, "")
+	variants := make(models.ImageVariants, len(renditions))
+	for _, rendition := range renditions {
+		objectKey := path.Join(folder, rendition.Name+rendition.Ext)
+		err := s.store.Put(objectKey, bytes.NewReader(rendition.Data), storage.PutOptions{ContentType: rendition.ContentType})
+		if err != nil {
+			logger.Error("Object upload failed", zap.Error(err), zap.String("key", objectKey))
+			return nil, errors.New(constants.ErrUploadFailed)
+		}
+		variants[rendition.Name] = objectKey
+	}
+
+	return variants, nil
+}
+
+// openFormFile enforces the size limit and extension of a multipart form file, opens it and
+// validates its content. It returns the content type detected from the file itself.
+func (s *FileUploadServiceImpl) openFormFile(ctx *gin.Context, fileField string, fileType FileType, maxSize int64) (multipart.File, *multipart.FileHeader, string, error) {
 	// Set maximum request size
 	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize)
 
 	fileHeader, err := ctx.FormFile(fileField)
 	if err != nil {
 		if strings.Contains(err.Error(), "http: request body too large") {
-			return "", errors.New(constants.ErrLogoTooLarge)
+			return nil, nil, "", SizeError(fileType)
 		}
-		return "", errors.New(constants.ErrFileUploadRequired)
+		return nil, nil, "", errors.New(constants.ErrFileUploadRequired)
 	}
 
 	if fileHeader.Size > maxSize {
-		return "", errors.New(constants.ErrLogoTooLarge)
+		return nil, nil, "", SizeError(fileType)
 	}
 
 	ext := filepath.Ext(fileHeader.Filename)
 	allowedExts := s.getAllowedExtensions(fileType)
 	if !s.isExtensionAllowed(ext, allowedExts) {
-		return "", errors.New(constants.ErrInvalidFileType)
+		return nil, nil, "", errors.New(constants.ErrInvalidFileType)
 	}
 
 	src, err := fileHeader.Open()
 	if err != nil {
 		logger.Error("Failed to open uploaded file", zap.Error(err))
-		return "", errors.New(constants.ErrUploadFailed)
-	}
-	defer src.Close()
-
-	// Generate path based on file type
-	objectKey := s.generateObjectKey(fileType, ext)
-
-	// Determine content type
-	contentType := fileHeader.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = s.inferContentType(ext)
+		return nil, nil, "", errors.New(constants.ErrUploadFailed)
 	}
 
-	// Upload to S3
-	fileURL, err := storage.UploadFileToS3(src, objectKey, contentType)
+	contentType, err := ValidateContent(fileType, src, fileHeader.Size)
 	if err != nil {
-		logger.Error("S3 upload failed", zap.Error(err), zap.String("key", objectKey))
-		return "", errors.New(constants.ErrUploadFailed)
+		src.Close()
+		return nil, nil, "", err
 	}
-
-	return fileURL, nil
+	return src, fileHeader, contentType, nil
 }
 
 // getAllowedExtensions returns allowed file extensions for a given file type
@@ -119,39 +210,16 @@ func (s *FileUploadServiceImpl) generateObjectKey(fileType FileType, ext string)
 
 	switch fileType {
 	case TeamLogo:
-		return filepath.Join("logos/teams", filename)
+		return path.Join("logos/teams", filename)
 	case SeasonLogo:
-		return filepath.Join("logos/seasons", filename)
+		return path.Join("logos/seasons", filename)
 	case UserAvatar:
-		return filepath.Join("avatars", filename)
+		return path.Join("avatars", filename)
 	case MatchVideo:
-		return filepath.Join("videos", filename)
+		return path.Join("videos", filename)
 	case MatchScout:
-		return filepath.Join("scouts", filename)
-	default:
-		return filepath.Join("misc", filename)
-	}
-}
-
-// inferContentType infers the content type from a file extension
-func (s *FileUploadServiceImpl) inferContentType(ext string) string {
-	ext = strings.ToLower(ext)
-	switch ext {
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	case ".mp4":
-		return "video/mp4"
-	case ".mov":
-		return "video/quicktime"
-	case ".mkv":
-		return "video/x-matroska"
-	case ".json":
-		return "application/json"
-	case ".csv":
-		return "text/csv"
+		return path.Join("scouts", filename)
 	default:
-		return "application/octet-stream"
+		return path.Join("misc", filename)
 	}
 }
@@ -0,0 +1,320 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"go-gin-starter/pkg/constants"
+	"image"
+	_ "image/jpeg" // register decoders for image.DecodeConfig
+	_ "image/png"
+	"io"
+)
+
+// SniffLength is how many leading bytes SniffContentType needs to recognise every file type
+const SniffLength = 64
+
+// maxTopLevelBoxes bounds the container walk of MP4/MOV files
+const maxTopLevelBoxes = 10000
+
+// dvwHeader starts every DataVolley scout file; the sections must all be present
+var (
+	dvwHeader   = []byte("[3DATAVOLLEYSCOUT]")
+	dvwSections = [][]byte{[]byte("[3MATCH]"), []byte("[3TEAMS]"), []byte("[3SCOUT]")}
+)
+
+// sizeErrors maps each file type to the message shown when it exceeds its limit
+var sizeErrors = map[FileType]string{
+	TeamLogo:   constants.ErrLogoTooLarge,
+	SeasonLogo: constants.ErrLogoTooLarge,
+	UserAvatar: constants.ErrAvatarTooLarge,
+	MatchVideo: constants.ErrVideoTooLarge,
+	MatchScout: constants.ErrScoutTooLarge,
+}
+
+// structureErrors maps each file type to the message shown when its content is not valid
+var structureErrors = map[FileType]string{
+	TeamLogo:   constants.ErrInvalidImage,
+	SeasonLogo: constants.ErrInvalidImage,
+	UserAvatar: constants.ErrInvalidImage,
+	MatchVideo: constants.ErrInvalidVideoFile,
+	MatchScout: constants.ErrInvalidScoutFile,
+}
+
+// SizeError returns the "too large" error of a file type
+func SizeError(fileType FileType) error {
+	if message, ok := sizeErrors[fileType]; ok {
+		return errors.New(message)
+	}
+	return errors.New(constants.ErrUploadFailed)
+}
+
+// IsValidationError reports whether an upload error was caused by the file itself
+// rather than by storage, so handlers can answer 400 instead of 500
+func IsValidationError(err error) bool {
+	switch err.Error() {
+	case constants.ErrFileUploadRequired, constants.ErrInvalidFileType, constants.ErrFileContentMismatch,
+		constants.ErrInvalidImage, constants.ErrImageDimensions, constants.ErrInvalidVideoFile,
+		constants.ErrInvalidScoutFile, constants.ErrLogoTooLarge, constants.ErrAvatarTooLarge,
+		constants.ErrVideoTooLarge, constants.ErrScoutTooLarge:
+		return true
+	}
+	return false
+}
+
+// SniffContentType detects the content type of a file from its first bytes and checks that it
+// is one the file type accepts. The client's extension and Content-Type header are not trusted.
+func SniffContentType(fileType FileType, head []byte) (string, error) {
+	var contentType string
+	switch fileType {
+	case TeamLogo, SeasonLogo, UserAvatar:
+		contentType = sniffImage(head)
+	case MatchVideo:
+		contentType = sniffVideo(head)
+	case MatchScout:
+		if bytes.HasPrefix(bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF")), dvwHeader) {
+			contentType = "text/plain"
+		}
+	}
+
+	if contentType == "" {
+		return "", errors.New(constants.ErrFileContentMismatch)
+	}
+	return contentType, nil
+}
+
+// ValidateContent sniffs a file and checks its structure: images must decode, videos must be
+// a well-formed MP4/MOV or Matroska container and scouts need the DataVolley sections.
+// It returns the detected content type and leaves the reader at the start of the file.
+func ValidateContent(fileType FileType, file io.ReadSeeker, size int64) (string, error) {
+	maxSize, ok := maxFileSizes[fileType]
+	if !ok {
+		return "", errors.New(constants.ErrInvalidFileType)
+	}
+	if size > maxSize {
+		return "", SizeError(fileType)
+	}
+
+	head := make([]byte, SniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	contentType, err := SniffContentType(fileType, head[:n])
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	var valid bool
+	switch contentType {
+	case "image/jpeg", "image/png":
+		_, _, err := image.DecodeConfig(file)
+		valid = err == nil
+	case "video/mp4", "video/quicktime":
+		valid = probeISOBMFF(file, size)
+	case "video/x-matroska":
+		valid = probeMatroska(file)
+	case "text/plain":
+		valid = checkScoutSections(file, size)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", errors.New(structureErrors[fileType])
+	}
+	return contentType, nil
+}
+
+// maxFileSizes is the upper size limit of each file type
+var maxFileSizes = map[FileType]int64{
+	TeamLogo:   constants.MaxLogoFileSize,
+	SeasonLogo: constants.MaxLogoFileSize,
+	UserAvatar: constants.MaxAvatarFileSize,
+	MatchVideo: constants.MaxVideoFileSize,
+	MatchScout: constants.MaxScoutFileSize,
+}
+
+func sniffImage(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xFF\xD8\xFF")):
+		return "image/jpeg"
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	}
+	return ""
+}
+
+func sniffVideo(head []byte) string {
+	if bytes.HasPrefix(head, []byte("\x1A\x45\xDF\xA3")) {
+		return "video/x-matroska"
+	}
+	if len(head) < 12 {
+		return ""
+	}
+
+	switch string(head[4:8]) {
+	case "ftyp":
+		if string(head[8:12]) == "qt  " {
+			return "video/quicktime"
+		}
+		return "video/mp4"
+	case "moov", "mdat", "wide", "free", "skip", "pnot":
+		// QuickTime files from older cameras have no ftyp box
+		return "video/quicktime"
+	}
+	return ""
+}
+
+// probeISOBMFF walks the top-level boxes of an MP4/MOV file. The boxes must tile the file
+// exactly and include the movie header (moov) that players need.
+func probeISOBMFF(r io.ReadSeeker, size int64) bool {
+	var offset int64
+	hasMovie := false
+
+	for boxes := 0; offset < size; boxes++ {
+		if boxes >= maxTopLevelBoxes {
+			return false
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return false
+		}
+
+		var header [16]byte
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return false
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := header[4:8]
+		headerSize := int64(8)
+
+		switch boxSize {
+		case 0:
+			// The last box may extend to the end of the file
+			boxSize = size - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return false
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if boxSize < headerSize || boxSize > size-offset || !isBoxType(boxType) {
+			return false
+		}
+		if string(boxType) == "moov" {
+			hasMovie = true
+		}
+		offset += boxSize
+	}
+	return hasMovie
+}
+
+// isBoxType reports whether a box type consists of four printable ASCII characters
+func isBoxType(boxType []byte) bool {
+	for _, c := range boxType {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// probeMatroska reads the EBML header of a Matroska file, checks its document type
+// and that a Segment element follows it
+func probeMatroska(r io.Reader) bool {
+	id, size, err := readEBMLElement(r)
+	if err != nil || id != 0x1A45DFA3 || size > 1024 {
+		return false
+	}
+
+	header := make([]byte, size)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return false
+	}
+
+	docType := ""
+	children := bytes.NewReader(header)
+	for children.Len() > 0 {
+		childID, childSize, err := readEBMLElement(children)
+		if err != nil || childSize > uint64(children.Len()) {
+			return false
+		}
+		value := make([]byte, childSize)
+		if _, err := io.ReadFull(children, value); err != nil {
+			return false
+		}
+		if childID == 0x4282 {
+			docType = string(bytes.TrimRight(value, "\x00"))
+		}
+	}
+	if docType != "matroska" && docType != "webm" {
+		return false
+	}
+
+	segmentID, _, err := readEBMLElement(r)
+	return err == nil && segmentID == 0x18538067
+}
+
+// readEBMLElement reads an element ID (marker bits kept) and its data size (marker bit removed)
+func readEBMLElement(r io.Reader) (uint64, uint64, error) {
+	id, _, err := readVint(r, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, _, err := readVint(r, false)
+	return id, size, err
+}
+
+// readVint reads an EBML variable-length integer of up to 8 bytes
+func readVint(r io.Reader, keepMarker bool) (uint64, int, error) {
+	var first [1]byte
+	if _, err := io.ReadFull(r, first[:]); err != nil {
+		return 0, 0, err
+	}
+
+	length := 1
+	for mask := byte(0x80); length <= 8 && first[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 {
+		return 0, 0, errors.New("invalid EBML integer")
+	}
+
+	value := uint64(first[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+
+	rest := make([]byte, length-1)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return 0, 0, err
+	}
+	for _, b := range rest {
+		value = value<<8 | uint64(b)
+	}
+	return value, length, nil
+}
+
+// checkScoutSections checks that a DataVolley file is text and contains the sections
+// the parser reads
+func checkScoutSections(r io.Reader, size int64) bool {
+	data, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+
+	for _, section := range dvwSections {
+		if !bytes.Contains(data, section) {
+			return false
+		}
+	}
+	return true
+}
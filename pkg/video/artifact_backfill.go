@@ -0,0 +1,84 @@
+package video
+
+import (
+	"path/filepath"
+
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/repositories"
+
+	"go.uber.org/zap"
+)
+
+// BackfillArtifacts records artifacts for videos processed before the processor tracked them.
+// Every candidate key is checked in storage so that only objects that really exist are
+// recorded. Safe to run on every start.
+func BackfillArtifacts(store storagePkg.ObjectStore) {
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+	artifactRepo := repositories.NewVideoArtifactRepository()
+
+	pending, err := matchVideoRepo.GetCompletedWithoutArtifacts()
+	if err != nil {
+		logger.Error("Failed to list videos without artifacts", zap.Error(err))
+		return
+	}
+
+	for _, matchVideo := range pending {
+		artifacts := discoverArtifacts(store, &matchVideo)
+		if len(artifacts) == 0 {
+			continue
+		}
+
+		if err := artifactRepo.ReplaceForMatchVideo(matchVideo.ID, artifacts); err != nil {
+			logger.Error("Failed to record backfilled artifacts",
+				zap.String("match_video_id", matchVideo.ID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		logger.Info("Backfilled video artifacts",
+			zap.String("match_video_id", matchVideo.ID.String()),
+			zap.Int("count", len(artifacts)))
+	}
+}
+
+// discoverArtifacts looks up the keys the processor would have written for a video
+func discoverArtifacts(store storagePkg.ObjectStore, matchVideo *models.MatchVideo) []models.VideoArtifact {
+	var artifacts []models.VideoArtifact
+
+	record := func(kind models.VideoArtifactKindEnum, label, key string) {
+		head, err := store.Head(key)
+		if err != nil {
+			return
+		}
+		artifacts = append(artifacts, models.VideoArtifact{
+			MatchID:      matchVideo.MatchID,
+			MatchVideoID: matchVideo.ID,
+			Kind:         kind,
+			Label:        label,
+			S3Key:        key,
+			ContentType:  head.ContentType,
+			Size:         head.Size,
+			Status:       models.ArtifactReady,
+		})
+	}
+
+	for _, format := range VideoFormatLadder {
+		record(models.ArtifactRendition, format, BuildRenditionKey(matchVideo.OutputKey, format))
+	}
+
+	if key, err := store.ObjectKey(matchVideo.ThumbnailURL); err == nil {
+		record(models.ArtifactThumbnail, "poster", key)
+	}
+	if key, err := store.ObjectKey(matchVideo.StoryboardURL); err == nil {
+		record(models.ArtifactStoryboard, "storyboard", key)
+	}
+	for _, spriteURL := range matchVideo.SpriteURLs {
+		if key, err := store.ObjectKey(spriteURL); err == nil {
+			record(models.ArtifactSprite, filepath.Base(key), key)
+		}
+	}
+
+	return artifacts
+}
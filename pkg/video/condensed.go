@@ -0,0 +1,303 @@
+package video
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// A condensed video keeps only the rallies of a match, each with a short lead-in so the
+// serve is visible and a short tail so the point's end is not cut off.
+const (
+	CondensedLeadInMs = 3000
+	CondensedTailMs   = 1500
+	CondensedMinGapMs = 1000 // clips closer than this are merged instead of jump-cutting
+	CondensedLabel    = "condensed"
+)
+
+// RallySpan is a rally in source video time, in milliseconds
+type RallySpan struct {
+	StartMs int64
+	EndMs   int64
+}
+
+// CondensedClip is one cut of the source that ends up in the condensed video
+type CondensedClip struct {
+	SourceStartMs int64
+	SourceEndMs   int64
+	Rallies       []RallySpan
+}
+
+// CondensedChapter maps one rally of the condensed video back to the source
+type CondensedChapter struct {
+	Number        int   `json:"number"`
+	StartMs       int64 `json:"start_ms"` // condensed time, including the lead-in
+	EndMs         int64 `json:"end_ms"`
+	SourceStartMs int64 `json:"source_start_ms"` // source time of StartMs
+	SourceEndMs   int64 `json:"source_end_ms"`
+	RallyStartMs  int64 `json:"rally_start_ms"` // source time the rally itself starts
+	RallyEndMs    int64 `json:"rally_end_ms"`
+}
+
+// condensedChapterFile is the JSON chapter file written next to the condensed video
+type condensedChapterFile struct {
+	MatchID      string             `json:"match_id"`
+	MatchVideoID string             `json:"match_video_id"`
+	DurationMs   int64              `json:"duration_ms"`
+	SourceMs     int64              `json:"source_duration_ms"`
+	Chapters     []CondensedChapter `json:"chapters"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+}
+
+// BuildCondensedClips pads every rally with lead-in and tail, clamps it to the source and
+// merges clips that overlap or nearly touch, so back-to-back rallies play without a cut
+func BuildCondensedClips(rallies []RallySpan, durationMs int64) []CondensedClip {
+	sorted := make([]RallySpan, 0, len(rallies))
+	for _, rally := range rallies {
+		if rally.EndMs > rally.StartMs && rally.StartMs >= 0 && (durationMs <= 0 || rally.StartMs < durationMs) {
+			sorted = append(sorted, rally)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartMs < sorted[j].StartMs })
+
+	var clips []CondensedClip
+	for _, rally := range sorted {
+		start := rally.StartMs - CondensedLeadInMs
+		if start < 0 {
+			start = 0
+		}
+		end := rally.EndMs + CondensedTailMs
+		if durationMs > 0 && end > durationMs {
+			end = durationMs
+		}
+
+		if n := len(clips); n > 0 && start-clips[n-1].SourceEndMs < CondensedMinGapMs {
+			last := &clips[n-1]
+			if end > last.SourceEndMs {
+				last.SourceEndMs = end
+			}
+			last.Rallies = append(last.Rallies, rally)
+			continue
+		}
+
+		clips = append(clips, CondensedClip{SourceStartMs: start, SourceEndMs: end, Rallies: []RallySpan{rally}})
+	}
+	return clips
+}
+
+// BuildCondensedChapters lays the clips end to end and returns one chapter per rally.
+// Inside a merged clip a chapter starts at its rally's lead-in, or where the clip starts.
+func BuildCondensedChapters(clips []CondensedClip) []CondensedChapter {
+	var chapters []CondensedChapter
+	var offset int64
+
+	for _, clip := range clips {
+		toCondensed := func(sourceMs int64) int64 {
+			return offset + sourceMs - clip.SourceStartMs
+		}
+
+		for i, rally := range clip.Rallies {
+			sourceStart := rally.StartMs - CondensedLeadInMs
+			if sourceStart < clip.SourceStartMs {
+				sourceStart = clip.SourceStartMs
+			}
+			sourceEnd := clip.SourceEndMs
+			if i+1 < len(clip.Rallies) {
+				next := clip.Rallies[i+1].StartMs - CondensedLeadInMs
+				if next > sourceStart && next < sourceEnd {
+					sourceEnd = next
+				}
+			}
+
+			chapters = append(chapters, CondensedChapter{
+				Number:        len(chapters) + 1,
+				StartMs:       toCondensed(sourceStart),
+				EndMs:         toCondensed(sourceEnd),
+				SourceStartMs: sourceStart,
+				SourceEndMs:   sourceEnd,
+				RallyStartMs:  rally.StartMs,
+				RallyEndMs:    rally.EndMs,
+			})
+		}
+
+		offset += clip.SourceEndMs - clip.SourceStartMs
+	}
+	return chapters
+}
+
+// BuildChaptersVTT renders chapters as a WebVTT chapter track; each cue names the rally
+// and its original position so players can show where in the match it happened
+func BuildChaptersVTT(chapters []CondensedChapter) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+
+	for _, chapter := range chapters {
+		fmt.Fprintf(&b, "%d\n%s --> %s\nRally %d (%s)\n\n",
+			chapter.Number,
+			formatVTTTime(time.Duration(chapter.StartMs)*time.Millisecond),
+			formatVTTTime(time.Duration(chapter.EndMs)*time.Millisecond),
+			chapter.Number,
+			formatVTTTime(time.Duration(chapter.RallyStartMs)*time.Millisecond))
+	}
+
+	return b.String()
+}
+
+// CondenseVideo cuts the rallies out of the job input into one condensed rendition and
+// uploads it with its chapter files next to the regular renditions
+func (p *VideoProcessor) CondenseVideo(job *VideoProcessingJob, rallies []RallySpan) (*ProcessingResult, error) {
+	tempDir, err := os.MkdirTemp("", "video-condense-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	inputPath := filepath.Join(tempDir, "input"+filepath.Ext(job.InputKey))
+	if err := p.downloadVideo(job.InputKey, inputPath); err != nil {
+		return nil, fmt.Errorf("failed to download video: %w", err)
+	}
+
+	probe, err := ProbeVideo(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProcessingResult{Probe: probe}
+
+	clips := BuildCondensedClips(rallies, probe.DurationMs)
+	if len(clips) == 0 {
+		return result, fmt.Errorf("%w: no rallies inside the video", ErrRejectedInput)
+	}
+	chapters := BuildCondensedChapters(clips)
+
+	outputPath := filepath.Join(tempDir, "condensed.mp4")
+	if err := p.cutClips(inputPath, outputPath, tempDir, clips, probe.AudioCodec != ""); err != nil {
+		return result, fmt.Errorf("failed to cut rallies: %w", err)
+	}
+
+	condensed := models.VideoArtifact{
+		Kind:        models.ArtifactCondensed,
+		Label:       CondensedLabel,
+		S3Key:       BuildRenditionKey(job.OutputKey, CondensedLabel),
+		ContentType: "video/mp4",
+	}
+	if output, err := ProbeVideo(outputPath); err == nil {
+		condensed.Width = output.Width
+		condensed.Height = output.Height
+	}
+	if err := p.publishArtifact(result, condensed, outputPath); err != nil {
+		return result, fmt.Errorf("failed to upload condensed video: %w", err)
+	}
+	result.Renditions = append(result.Renditions, CondensedLabel)
+
+	var durationMs int64
+	for _, clip := range clips {
+		durationMs += clip.SourceEndMs - clip.SourceStartMs
+	}
+
+	chaptersKey := strings.TrimSuffix(condensed.S3Key, filepath.Ext(condensed.S3Key))
+
+	vttPath := filepath.Join(tempDir, "chapters.vtt")
+	if err := os.WriteFile(vttPath, []byte(BuildChaptersVTT(chapters)), 0o644); err != nil {
+		return result, err
+	}
+	vtt := models.VideoArtifact{Kind: models.ArtifactChapters, Label: "vtt", S3Key: chaptersKey + ".vtt"}
+	if err := p.publishArtifact(result, vtt, vttPath); err != nil {
+		logger.Error("Failed to upload chapter track", zap.Error(err))
+	}
+
+	jsonPath := filepath.Join(tempDir, "chapters.json")
+	data, err := json.MarshalIndent(condensedChapterFile{
+		MatchID:      job.MatchID,
+		MatchVideoID: job.MatchVideoID,
+		DurationMs:   durationMs,
+		SourceMs:     probe.DurationMs,
+		Chapters:     chapters,
+		GeneratedAt:  time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return result, err
+	}
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		return result, err
+	}
+	mapping := models.VideoArtifact{Kind: models.ArtifactChapters, Label: "json", S3Key: chaptersKey + ".json"}
+	if err := p.publishArtifact(result, mapping, jsonPath); err != nil {
+		logger.Error("Failed to upload chapter file", zap.Error(err))
+	}
+
+	logger.Info("condensed video produced",
+		zap.String("match_video_id", job.MatchVideoID),
+		zap.Int("clips", len(clips)),
+		zap.Int("rallies", len(chapters)),
+		zap.Int64("duration_ms", durationMs),
+		zap.Int64("source_duration_ms", probe.DurationMs))
+
+	return result, nil
+}
+
+// cutClips trims every clip from the input and concatenates them in one ffmpeg run.
+// The filter graph goes through a script file; a full match easily has 200 clips.
+func (p *VideoProcessor) cutClips(inputPath, outputPath, tempDir string, clips []CondensedClip, hasAudio bool) error {
+	var graph strings.Builder
+	var inputs strings.Builder
+
+	for i, clip := range clips {
+		start := formatFFmpegTime(time.Duration(clip.SourceStartMs) * time.Millisecond)
+		end := formatFFmpegTime(time.Duration(clip.SourceEndMs) * time.Millisecond)
+
+		fmt.Fprintf(&graph, "[0:v:0]trim=start=%s:end=%s,setpts=PTS-STARTPTS[v%d];\n", start, end, i)
+		fmt.Fprintf(&inputs, "[v%d]", i)
+		if hasAudio {
+			fmt.Fprintf(&graph, "[0:a:0]atrim=start=%s:end=%s,asetpts=PTS-STARTPTS[a%d];\n", start, end, i)
+			fmt.Fprintf(&inputs, "[a%d]", i)
+		}
+	}
+
+	audioStreams := 0
+	if hasAudio {
+		audioStreams = 1
+	}
+	fmt.Fprintf(&graph, "%sconcat=n=%d:v=1:a=%d[outv]", inputs.String(), len(clips), audioStreams)
+	if hasAudio {
+		graph.WriteString("[outa]")
+	}
+
+	scriptPath := filepath.Join(tempDir, "condense.filter")
+	if err := os.WriteFile(scriptPath, []byte(graph.String()), 0o644); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-v", "error",
+		"-i", inputPath,
+		"-filter_complex_script", scriptPath,
+		"-map", "[outv]",
+	}
+	if hasAudio {
+		args = append(args, "-map", "[outa]", "-c:a", "aac", "-b:a", "128k")
+	}
+	args = append(args,
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-crf", "23",
+		"-movflags", "+faststart",
+		"-y",
+		outputPath,
+	)
+
+	output, err := exec.Command("ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package video
+
+import (
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/repositories"
+
+	"go.uber.org/zap"
+)
+
+// ImportLegacyVideos gives every match that only carries a VideoURL a reference camera
+// angle, taking over the processing state and metadata stored on the match. It runs before
+// the server starts, so legacy videos never go missing from the angle listing. Safe to run
+// on every start.
+func ImportLegacyVideos(store storagePkg.ObjectStore) {
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+
+	legacyVideos, err := repositories.NewMatchRepository().GetLegacyVideos()
+	if err != nil {
+		logger.Error("Failed to list matches with legacy videos", zap.Error(err))
+		return
+	}
+
+	imported := 0
+	for _, legacy := range legacyVideos {
+		outputKey, err := store.ObjectKey(legacy.VideoURL)
+		if err != nil {
+			logger.Warn("Skipping match with unparseable video URL",
+				zap.String("match_id", legacy.MatchID.String()),
+				zap.String("video_url", legacy.VideoURL))
+			continue
+		}
+
+		status := legacy.Status
+		if status == "" {
+			status = StatusCompleted
+		}
+
+		matchVideo := &models.MatchVideo{
+			MatchID:       legacy.MatchID,
+			Angle:         constants.DefaultVideoAngle,
+			IsReference:   true,
+			RawKey:        legacy.RawKey,
+			OutputKey:     outputKey,
+			VideoURL:      legacy.VideoURL,
+			ThumbnailURL:  legacy.ThumbnailURL,
+			StoryboardURL: legacy.StoryboardURL,
+			SpriteURLs:    legacy.SpriteURLs,
+			Status:        status,
+			Error:         legacy.Error,
+			Metadata:      legacy.Metadata,
+		}
+		if err := matchVideoRepo.Create(matchVideo); err != nil {
+			logger.Error("Failed to create camera angle for legacy video",
+				zap.String("match_id", legacy.MatchID.String()),
+				zap.Error(err))
+			continue
+		}
+		imported++
+	}
+
+	if imported > 0 {
+		logger.Info("Legacy match videos imported as camera angles", zap.Int("matches", imported))
+	}
+}
@@ -0,0 +1,78 @@
+package video
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// manifestEntry describes one uploaded artifact in the manifest
+type manifestEntry struct {
+	Kind        string `json:"kind"`
+	Label       string `json:"label"`
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+}
+
+// manifestSource summarizes the probed source video
+type manifestSource struct {
+	DurationMs int64   `json:"duration_ms"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FrameRate  float64 `json:"frame_rate"`
+	VideoCodec string  `json:"video_codec"`
+	AudioCodec string  `json:"audio_codec,omitempty"`
+}
+
+// manifest is written next to the renditions of every processed video
+type manifest struct {
+	MatchID      string          `json:"match_id"`
+	MatchVideoID string          `json:"match_video_id"`
+	Source       manifestSource  `json:"source"`
+	Artifacts    []manifestEntry `json:"artifacts"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+}
+
+// writeManifest writes a JSON manifest of every successfully uploaded artifact to path
+func writeManifest(path string, job *VideoProcessingJob, result *ProcessingResult) error {
+	m := manifest{
+		MatchID:      job.MatchID,
+		MatchVideoID: job.MatchVideoID,
+		GeneratedAt:  time.Now().UTC(),
+	}
+
+	if result.Probe != nil {
+		m.Source = manifestSource{
+			DurationMs: result.Probe.DurationMs,
+			Width:      result.Probe.Width,
+			Height:     result.Probe.Height,
+			FrameRate:  result.Probe.FrameRate,
+			VideoCodec: result.Probe.VideoCodec,
+			AudioCodec: result.Probe.AudioCodec,
+		}
+	}
+
+	for _, artifact := range result.Artifacts {
+		if artifact.Error != "" {
+			continue
+		}
+		m.Artifacts = append(m.Artifacts, manifestEntry{
+			Kind:        string(artifact.Kind),
+			Label:       artifact.Label,
+			Key:         artifact.S3Key,
+			ContentType: artifact.ContentType,
+			Size:        artifact.Size,
+			Width:       artifact.Width,
+			Height:      artifact.Height,
+		})
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
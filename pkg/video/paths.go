@@ -0,0 +1,82 @@
+package video
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+)
+
+// BuildMatchBasePath returns the S3 folder that holds all video assets of a match:
+// videos/<season>_<country>/<competition>_<gender>/<matchID>
+func BuildMatchBasePath(season *models.Season, matchID uuid.UUID) string {
+	safeSeasonName := strings.ReplaceAll(strings.ToLower(string(season.Name)), " ", "_")
+	safeSeasonYear := strings.ReplaceAll(season.SeasonYear, "/", "_")
+	safeGender := strings.ToLower(string(season.Gender))
+	safeCountry := strings.ToLower(string(season.Country))
+
+	return fmt.Sprintf("videos/%s_%s/%s_%s/%s",
+		safeSeasonYear,
+		safeCountry,
+		safeSeasonName,
+		safeGender,
+		matchID.String())
+}
+
+// BuildRawVideoKey returns a fresh key for a raw upload below the match base path
+func BuildRawVideoKey(basePath, ext string) string {
+	return fmt.Sprintf("%s/%s/%s%s", basePath, RawVideoFolder, uuid.New().String(), ext)
+}
+
+// BuildCompressedVideoKey returns a fresh key for the processed output below the match base path
+func BuildCompressedVideoKey(basePath string) string {
+	return fmt.Sprintf("%s/%s/%s.mp4", basePath, CompressedFolder, uuid.New().String())
+}
+
+// BuildRenditionKey returns the key of one rendition of a processed output:
+// .../compressed/<uuid>.mp4 becomes .../compressed/<format>/<uuid>.mp4
+func BuildRenditionKey(outputKey, format string) string {
+	return strings.Replace(outputKey, CompressedFolder+"/", fmt.Sprintf("%s/%s/", CompressedFolder, format), 1)
+}
+
+// BuildManifestKey returns the key of the artifact manifest of a processed output:
+// .../compressed/<uuid>.mp4 becomes .../manifests/<uuid>.json
+func BuildManifestKey(outputKey string) string {
+	key := strings.Replace(outputKey, CompressedFolder+"/", ManifestsFolder+"/", 1)
+	return strings.TrimSuffix(key, path.Ext(key)) + ".json"
+}
+
+// BuildBestRenditionKey returns the key of the largest available rendition, used as the source
+// for derived renditions once the raw upload may already be archived
+func BuildBestRenditionKey(outputKey string, renditions []string) string {
+	available := make(map[string]bool, len(renditions))
+	for _, format := range renditions {
+		available[format] = true
+	}
+
+	for _, format := range VideoFormatLadder {
+		if available[format] {
+			return BuildRenditionKey(outputKey, format)
+		}
+	}
+	return ""
+}
+
+// BuildAnalysisInputKey returns the key of the smallest available rendition, which is enough
+// for audio analysis and much cheaper to download than the raw upload
+func BuildAnalysisInputKey(outputKey string, renditions []string) string {
+	available := make(map[string]bool, len(renditions))
+	for _, format := range renditions {
+		available[format] = true
+	}
+
+	for i := len(VideoFormatLadder) - 1; i >= 0; i-- {
+		if available[VideoFormatLadder[i]] {
+			return BuildRenditionKey(outputKey, VideoFormatLadder[i])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package video
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go-gin-starter/pkg/constants"
+)
+
+// ErrRejectedInput marks source videos that can never be processed, so they are not retried
+var ErrRejectedInput = errors.New("video input rejected")
+
+// SupportedVideoCodecs lists the source codecs the worker is willing to transcode
+var SupportedVideoCodecs = map[string]bool{
+	"h264":       true,
+	"hevc":       true,
+	"mpeg4":      true,
+	"mpeg2video": true,
+	"vp8":        true,
+	"vp9":        true,
+	"av1":        true,
+	"prores":     true,
+}
+
+// ProbeResult holds the stream information ffprobe reports for a source video
+type ProbeResult struct {
+	DurationMs int64
+	Width      int
+	Height     int
+	FrameRate  float64
+	VideoCodec string
+	AudioCodec string
+	FileSize   int64
+}
+
+// ffprobeOutput mirrors the subset of `ffprobe -print_format json` we read
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		Size     string `json:"size"`
+	} `json:"format"`
+}
+
+// ProbeVideo runs ffprobe on a local file and extracts duration, resolution, frame rate and codecs
+func ProbeVideo(path string) (*ProbeResult, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: ffprobe failed: %v", ErrRejectedInput, err)
+	}
+
+	var parsed ffprobeOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	result := &ProbeResult{}
+	for _, stream := range parsed.Streams {
+		switch stream.CodecType {
+		case "video":
+			// Only the first video stream counts; cover art shows up as a second one
+			if result.VideoCodec == "" {
+				result.VideoCodec = stream.CodecName
+				result.Width = stream.Width
+				result.Height = stream.Height
+				result.FrameRate = parseFrameRate(stream.AvgFrameRate)
+			}
+		case "audio":
+			if result.AudioCodec == "" {
+				result.AudioCodec = stream.CodecName
+			}
+		}
+	}
+
+	if seconds, err := strconv.ParseFloat(parsed.Format.Duration, 64); err == nil {
+		result.DurationMs = int64(seconds * 1000)
+	}
+	if size, err := strconv.ParseInt(parsed.Format.Size, 10, 64); err == nil {
+		result.FileSize = size
+	}
+
+	return result, nil
+}
+
+// Validate rejects inputs that would waste CPU: no picture, unknown codec or no duration
+func (r *ProbeResult) Validate() error {
+	if r.VideoCodec == "" || r.Width == 0 || r.Height == 0 {
+		return fmt.Errorf("%w: %s", ErrRejectedInput, constants.ErrNoVideoStream)
+	}
+	if !SupportedVideoCodecs[r.VideoCodec] {
+		return fmt.Errorf("%w: %s: %s", ErrRejectedInput, constants.ErrUnsupportedVideoCodec, r.VideoCodec)
+	}
+	if r.DurationMs <= 0 {
+		return fmt.Errorf("%w: %s", ErrRejectedInput, constants.ErrInvalidVideoDuration)
+	}
+	return nil
+}
+
+// parseFrameRate converts ffprobe's rational "30000/1001" notation to frames per second
+func parseFrameRate(rate string) float64 {
+	num, den, found := strings.Cut(rate, "/")
+	if !found {
+		value, _ := strconv.ParseFloat(rate, 64)
+		return value
+	}
+
+	n, errNum := strconv.ParseFloat(num, 64)
+	d, errDen := strconv.ParseFloat(den, 64)
+	if errNum != nil || errDen != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
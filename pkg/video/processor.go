@@ -2,118 +2,226 @@ package video
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
 	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"go.uber.org/zap"
 )
 
 // VideoProcessor handles video compression and processing
 type VideoProcessor struct {
-	s3Client   *s3.S3
-	downloader *s3manager.Downloader
-	uploader   *s3manager.Uploader
-	bucket     string
+	store storagePkg.ObjectStore
 }
 
 // NewVideoProcessor creates a new video processor instance
-func NewVideoProcessor(sess *session.Session, s3Client *s3.S3, bucket string) *VideoProcessor {
-	return &VideoProcessor{
-		s3Client:   s3Client,
-		downloader: s3manager.NewDownloader(sess),
-		uploader:   s3manager.NewUploader(sess),
-		bucket:     bucket,
-	}
+func NewVideoProcessor(store storagePkg.ObjectStore) *VideoProcessor {
+	return &VideoProcessor{store: store}
 }
 
 // ProcessVideo handles the complete video processing pipeline
-func (p *VideoProcessor) ProcessVideo(job *VideoProcessingJob) (string, error) {
+func (p *VideoProcessor) ProcessVideo(job *VideoProcessingJob) (*ProcessingResult, error) {
 	// Create temp directory
 	tempDir, err := os.MkdirTemp("", "video-processing-*")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp dir: %w", err)
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
 
 	// Download raw video
 	inputPath := filepath.Join(tempDir, "input"+filepath.Ext(job.InputKey))
 	if err := p.downloadVideo(job.InputKey, inputPath); err != nil {
-		return "", fmt.Errorf("failed to download video: %w", err)
+		return nil, fmt.Errorf("failed to download video: %w", err)
 	}
 
-	// Process for each format
-	for format, specs := range DefaultVideoFormats {
+	// Probe the source before spending CPU on it
+	probe, err := ProbeVideo(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProcessingResult{Probe: probe}
+	if err := probe.Validate(); err != nil {
+		return result, err
+	}
+
+	logger.Info("probed source video",
+		zap.String("match_id", job.MatchID),
+		zap.Int("width", probe.Width),
+		zap.Int("height", probe.Height),
+		zap.String("codec", probe.VideoCodec),
+		zap.Int64("duration_ms", probe.DurationMs))
+
+	// Process each format up to the source resolution
+	for _, format := range BuildRenditionLadder(probe.Height) {
+		specs := DefaultVideoFormats[format]
 		outputPath := filepath.Join(tempDir, fmt.Sprintf("output_%s.mp4", format))
+		artifact := models.VideoArtifact{
+			Kind:        models.ArtifactRendition,
+			Label:       format,
+			S3Key:       BuildRenditionKey(job.OutputKey, format),
+			ContentType: "video/mp4",
+		}
 
-		if err := p.compressVideo(inputPath, outputPath, specs); err != nil {
+		if err := p.compressVideo(inputPath, outputPath, specs, probe.Height); err != nil {
 			logger.Error("Failed to process video format",
 				zap.String("format", format),
 				zap.Error(err))
+			artifact.Status = models.ArtifactFailed
+			artifact.Error = fmt.Sprintf("compression failed: %v", err)
+			result.Artifacts = append(result.Artifacts, artifact)
 			continue
 		}
 
-		// Generate output key for this format
-		formatKey := strings.Replace(job.OutputKey, "compressed/", fmt.Sprintf("compressed/%s/", format), 1)
+		// Record the real output dimensions; odd source sizes get rounded by the scaler
+		if output, err := ProbeVideo(outputPath); err == nil {
+			artifact.Width = output.Width
+			artifact.Height = output.Height
+		}
 
-		// Upload processed video
-		if err := p.uploadVideo(outputPath, formatKey); err != nil {
+		if err := p.publishArtifact(result, artifact, outputPath); err != nil {
 			logger.Error("Failed to upload processed video",
 				zap.String("format", format),
 				zap.Error(err))
 			continue
 		}
+
+		result.Renditions = append(result.Renditions, format)
+	}
+
+	if len(result.Renditions) == 0 {
+		return result, fmt.Errorf("%s: no rendition could be produced", constants.ErrVideoProcessing)
 	}
 
-	// Generate and upload thumbnail
-	thumbnailPath := filepath.Join(tempDir, "thumbnail.jpg")
-	var thumbnailURL string
+	// Pick a representative poster frame, falling back to the first second of video
+	thumbnailKey := strings.Replace(job.OutputKey, "compressed/", "thumbnails/", 1)
+	thumbnailKey = strings.TrimSuffix(thumbnailKey, filepath.Ext(thumbnailKey)) + ".jpg"
 
-	logger.Info("generating thumbnail", zap.String("input_path", inputPath))
+	logger.Info("selecting poster frame", zap.String("input_path", inputPath))
 
-	if err := p.generateThumbnail(inputPath, thumbnailPath); err != nil {
+	thumbnailPath, err := p.selectPosterFrame(inputPath, tempDir, probe.DurationMs)
+	if err != nil {
+		logger.Warn("Poster frame selection failed, using first second", zap.Error(err))
+		thumbnailPath = filepath.Join(tempDir, "thumbnail.jpg")
+		err = p.generateThumbnail(inputPath, thumbnailPath)
+	}
+
+	poster := models.VideoArtifact{Kind: models.ArtifactThumbnail, Label: "poster", S3Key: thumbnailKey}
+	if err != nil {
 		logger.Error("Failed to generate thumbnail", zap.Error(err))
+	} else if err := p.publishArtifact(result, poster, thumbnailPath); err != nil {
+		logger.Error("Failed to upload thumbnail", zap.Error(err))
 	} else {
-		logger.Info("thumbnail generated", zap.String("thumbnail_path", thumbnailPath))
+		result.ThumbnailKey = thumbnailKey
+		logger.Info("thumbnail uploaded successfully", zap.String("key", thumbnailKey))
+	}
+
+	// Sprite sheets and storyboard for scrubbing previews
+	storyboardFolder := strings.TrimSuffix(thumbnailKey, filepath.Ext(thumbnailKey))
+	if err := p.processStoryboard(inputPath, tempDir, storyboardFolder, probe, result); err != nil {
+		logger.Error("Failed to generate storyboard", zap.Error(err))
+	}
+
+	// The manifest lists everything above so consumers don't need to know the key layout
+	manifestPath := filepath.Join(tempDir, "manifest.json")
+	manifest := models.VideoArtifact{Kind: models.ArtifactManifest, Label: "manifest", S3Key: BuildManifestKey(job.OutputKey)}
+	if err := writeManifest(manifestPath, job, result); err != nil {
+		logger.Error("Failed to write manifest", zap.Error(err))
+	} else if err := p.publishArtifact(result, manifest, manifestPath); err != nil {
+		logger.Error("Failed to upload manifest", zap.Error(err))
+	}
+
+	return result, nil
+}
 
-		thumbnailKey := strings.Replace(job.OutputKey, "compressed/", "thumbnails/", 1)
-		thumbnailKey = strings.TrimSuffix(thumbnailKey, filepath.Ext(thumbnailKey)) + ".jpg"
+// processStoryboard generates sprite sheets plus a WebVTT storyboard and uploads them below folder
+func (p *VideoProcessor) processStoryboard(inputPath, tempDir, folder string, probe *ProbeResult, result *ProcessingResult) error {
+	sheet, err := p.generateSpriteSheets(inputPath, tempDir, probe)
+	if err != nil {
+		return err
+	}
 
-		if err := p.uploadVideo(thumbnailPath, thumbnailKey); err != nil {
-			logger.Error("Failed to upload thumbnail", zap.Error(err))
-		} else {
-			thumbnailURL = fmt.Sprintf("https://%s/%s", os.Getenv("VIDEO_CLOUDFRONT_DOMAIN"), thumbnailKey)
-			logger.Info("thumbnail uploaded successfully", zap.String("url", thumbnailURL))
+	spriteNames := make([]string, 0, len(sheet.Paths))
+	for _, spritePath := range sheet.Paths {
+		name := filepath.Base(spritePath)
+		key := fmt.Sprintf("%s/%s", folder, name)
+
+		sprite := models.VideoArtifact{Kind: models.ArtifactSprite, Label: name, S3Key: key}
+		if err := p.publishArtifact(result, sprite, spritePath); err != nil {
+			return fmt.Errorf("failed to upload sprite %s: %w", name, err)
+		}
+
+		spriteNames = append(spriteNames, name)
+		result.SpriteKeys = append(result.SpriteKeys, key)
+	}
+
+	vttPath := filepath.Join(tempDir, "storyboard.vtt")
+	if err := os.WriteFile(vttPath, []byte(BuildStoryboardVTT(sheet, spriteNames, probe.DurationMs)), 0o644); err != nil {
+		return err
+	}
+
+	vttKey := fmt.Sprintf("%s/storyboard.vtt", folder)
+	storyboard := models.VideoArtifact{Kind: models.ArtifactStoryboard, Label: "storyboard", S3Key: vttKey}
+	if err := p.publishArtifact(result, storyboard, vttPath); err != nil {
+		return fmt.Errorf("failed to upload storyboard: %w", err)
+	}
+
+	result.StoryboardKey = vttKey
+	return nil
+}
+
+// publishArtifact uploads a produced file and records it on the result, including failed uploads
+func (p *VideoProcessor) publishArtifact(result *ProcessingResult, artifact models.VideoArtifact, filePath string) error {
+	if artifact.ContentType == "" {
+		artifact.ContentType = p.getContentType(filePath)
+	}
+
+	err := p.uploadVideo(filePath, artifact.S3Key)
+	if err != nil {
+		artifact.Status = models.ArtifactFailed
+		artifact.Error = err.Error()
+	} else {
+		artifact.Status = models.ArtifactReady
+		if info, statErr := os.Stat(filePath); statErr == nil {
+			artifact.Size = info.Size()
 		}
 	}
 
-	return thumbnailURL, nil
+	result.Artifacts = append(result.Artifacts, artifact)
+	return err
 }
 
-// downloadVideo downloads a video from S3
+// downloadVideo copies an object from storage to a local file
 func (p *VideoProcessor) downloadVideo(key, outputPath string) error {
+	body, _, err := p.store.Get(key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	_, err = p.downloader.Download(file, &s3.GetObjectInput{
-		Bucket: aws.String(p.bucket),
-		Key:    aws.String(key),
-	})
+	_, err = io.Copy(file, body)
 	return err
 }
 
-// compressVideo compresses a video using ffmpeg
-func (p *VideoProcessor) compressVideo(inputPath, outputPath string, format VideoFormat) error {
+// compressVideo compresses a video using ffmpeg, never scaling above the source height
+func (p *VideoProcessor) compressVideo(inputPath, outputPath string, format VideoFormat, sourceHeight int) error {
+	height := format.Height
+	if sourceHeight > 0 && sourceHeight < height {
+		height = sourceHeight
+	}
+
 	cmd := exec.Command("ffmpeg",
 		"-i", inputPath,
 		"-c:v", "libx264",
@@ -122,7 +230,7 @@ func (p *VideoProcessor) compressVideo(inputPath, outputPath string, format Vide
 		"-c:a", "aac",
 		"-b:a", "128k",
 		"-movflags", "+faststart",
-		"-vf", fmt.Sprintf("scale=%s", format.Resolution),
+		"-vf", fmt.Sprintf("scale=-2:%d", height),
 		"-b:v", format.Bitrate,
 		"-y",
 		outputPath,
@@ -131,7 +239,7 @@ func (p *VideoProcessor) compressVideo(inputPath, outputPath string, format Vide
 	return cmd.Run()
 }
 
-// generateThumbnail generates a thumbnail from the video
+// generateThumbnail grabs the frame at one second, used when poster selection fails
 func (p *VideoProcessor) generateThumbnail(videoPath, thumbnailPath string) error {
 	cmd := exec.Command("ffmpeg",
 		"-i", videoPath,
@@ -145,7 +253,7 @@ func (p *VideoProcessor) generateThumbnail(videoPath, thumbnailPath string) erro
 	return cmd.Run()
 }
 
-// uploadVideo uploads a processed video to S3
+// uploadVideo uploads a processed file to storage
 func (p *VideoProcessor) uploadVideo(filePath, key string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -153,14 +261,7 @@ func (p *VideoProcessor) uploadVideo(filePath, key string) error {
 	}
 	defer file.Close()
 
-	contentType := p.getContentType(filePath)
-	_, err = p.uploader.Upload(&s3manager.UploadInput{
-		Bucket:      aws.String(p.bucket),
-		Key:         aws.String(key),
-		Body:        file,
-		ContentType: aws.String(contentType),
-	})
-	return err
+	return p.store.Put(key, file, storagePkg.PutOptions{ContentType: p.getContentType(filePath)})
 }
 
 // getContentType determines the content type based on file extension
@@ -173,6 +274,10 @@ func (p *VideoProcessor) getContentType(filePath string) string {
 		return "video/quicktime"
 	case ".jpg", ".jpeg":
 		return "image/jpeg"
+	case ".vtt":
+		return "text/vtt"
+	case ".json":
+		return "application/json"
 	default:
 		return "application/octet-stream"
 	}
@@ -2,6 +2,8 @@ package video
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -9,7 +11,10 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"go-gin-starter/models"
 	"go-gin-starter/pkg/logger"
+	scoutPkg "go-gin-starter/pkg/scout"
+	storagePkg "go-gin-starter/pkg/storage"
 	"go-gin-starter/repositories"
 )
 
@@ -69,39 +74,18 @@ func (q *QueueManager) StartProcessing() {
 				continue
 			}
 
-			job.Status = StatusProcessing
-
-			thumbnailURL, err := q.processor.ProcessVideo(&job)
-			if err != nil {
-				logger.Error("Failed to process video",
-					zap.String("match_id", job.MatchID),
-					zap.Error(err))
-				job.Status = StatusFailed
-				job.Error = err.Error()
-			} else {
-				job.Status = StatusCompleted
+			var done bool
+			switch job.Type {
+			case JobTypeDetectRallies:
+				done = q.handleRallyDetection(&job)
+			case JobTypeCondense:
+				done = q.handleCondense(&job)
+			default:
+				done = q.handleProcessing(&job)
 			}
 
-			matchID, parseErr := uuid.Parse(job.MatchID)
-			if parseErr == nil {
-				matchRepo := repositories.NewMatchRepository()
-				match, getErr := matchRepo.GetByID(matchID)
-				if getErr == nil {
-					match.ThumbnailURL = thumbnailURL
-					if uploadErr := matchRepo.Update(match); uploadErr != nil {
-						logger.Error("failed to update match thumbnail",
-							zap.Error(uploadErr))
-					}
-				} else {
-					logger.Error("failed to fetch match for thumbnail update",
-						zap.Error(getErr))
-				}
-			} else {
-				logger.Error("invalid match UUID", zap.Error(parseErr))
-			}
-
-			// Delete message from queue if processed successfully
-			if job.Status == StatusCompleted {
+			// Delete the message once handled for good; failures are retried by SQS
+			if done {
 				_, err = q.sqs.DeleteMessage(&sqs.DeleteMessageInput{
 					QueueUrl:      aws.String(q.queueURL),
 					ReceiptHandle: message.ReceiptHandle,
@@ -113,3 +97,390 @@ func (q *QueueManager) StartProcessing() {
 		}
 	}
 }
+
+// handleProcessing runs the processing pipeline for a job and reports whether it is done for good
+func (q *QueueManager) handleProcessing(job *VideoProcessingJob) bool {
+	job.Status = StatusProcessing
+	q.updateMatchVideo(job, nil)
+
+	processed, err := q.processor.ProcessVideo(job)
+	switch {
+	case errors.Is(err, ErrRejectedInput):
+		logger.Warn("Rejected video input",
+			zap.String("match_id", job.MatchID),
+			zap.Error(err))
+		job.Status = StatusRejected
+		job.Error = err.Error()
+	case err != nil:
+		logger.Error("Failed to process video",
+			zap.String("match_id", job.MatchID),
+			zap.Error(err))
+		job.Status = StatusFailed
+		job.Error = err.Error()
+	default:
+		job.Status = StatusCompleted
+	}
+
+	q.updateMatchVideo(job, processed)
+
+	if job.Status == StatusCompleted {
+		q.enqueueFollowUpJobs(job, processed)
+	}
+
+	// Rejected inputs will never succeed, so they are done as well
+	return job.Status == StatusCompleted || job.Status == StatusRejected
+}
+
+// updateMatchVideo records the job status and whatever the processor produced on the
+// camera angle, mirroring the playable URLs onto the match when it is the reference angle
+func (q *QueueManager) updateMatchVideo(job *VideoProcessingJob, result *ProcessingResult) {
+	matchVideoID, err := uuid.Parse(job.MatchVideoID)
+	if err != nil {
+		logger.Error("invalid match video UUID",
+			zap.String("match_id", job.MatchID),
+			zap.Error(err))
+		return
+	}
+
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+	matchVideo, err := matchVideoRepo.GetByID(matchVideoID)
+	if err != nil {
+		logger.Error("failed to fetch match video for update", zap.Error(err))
+		return
+	}
+
+	// A newer upload for the same angle replaced this job's output; leave the record alone
+	if matchVideo.OutputKey != job.OutputKey {
+		logger.Warn("skipping update for superseded video job",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.String("output_key", job.OutputKey))
+		return
+	}
+
+	matchVideo.Status = job.Status
+	matchVideo.Error = job.Error
+
+	if result != nil {
+		if result.Probe != nil {
+			matchVideo.Metadata = models.VideoMetadata{
+				DurationMs: result.Probe.DurationMs,
+				Width:      result.Probe.Width,
+				Height:     result.Probe.Height,
+				FrameRate:  result.Probe.FrameRate,
+				VideoCodec: result.Probe.VideoCodec,
+				AudioCodec: result.Probe.AudioCodec,
+				FileSize:   result.Probe.FileSize,
+			}
+		}
+		if result.ThumbnailKey != "" {
+			matchVideo.ThumbnailURL = result.ThumbnailKey
+		}
+		if result.StoryboardKey != "" {
+			matchVideo.StoryboardURL = result.StoryboardKey
+			matchVideo.SpriteURLs = models.StringArray(result.SpriteKeys)
+		}
+	}
+
+	if err := matchVideoRepo.Update(matchVideo); err != nil {
+		logger.Error("failed to update match video status", zap.Error(err))
+		return
+	}
+
+	if result != nil {
+		artifacts := make([]models.VideoArtifact, 0, len(result.Artifacts))
+		for _, artifact := range result.Artifacts {
+			artifact.MatchID = matchVideo.MatchID
+			artifact.MatchVideoID = matchVideo.ID
+			artifacts = append(artifacts, artifact)
+		}
+
+		if err := repositories.NewVideoArtifactRepository().ReplaceForMatchVideo(matchVideo.ID, artifacts); err != nil {
+			logger.Error("failed to record video artifacts",
+				zap.String("match_video_id", job.MatchVideoID),
+				zap.Error(err))
+		}
+	}
+
+	if matchVideo.IsReference && result != nil && result.ThumbnailKey != "" {
+		matchRepo := repositories.NewMatchRepository()
+		match, err := matchRepo.GetByID(matchVideo.MatchID)
+		if err != nil {
+			logger.Error("failed to fetch match for thumbnail update", zap.Error(err))
+			return
+		}
+
+		match.ThumbnailURL = matchVideo.ThumbnailURL
+		if err := matchRepo.Update(match); err != nil {
+			logger.Error("failed to update match thumbnail", zap.Error(err))
+		}
+	}
+}
+
+// enqueueFollowUpJobs queues the rally work for a freshly processed reference angle: a condensed
+// video straight away when the match has a scout file, otherwise rally detection from the audio,
+// which in turn queues the condensed video; coaches get rally-by-rally navigation out of the box
+func (q *QueueManager) enqueueFollowUpJobs(job *VideoProcessingJob, result *ProcessingResult) {
+	if result == nil {
+		return
+	}
+
+	matchVideoID, err := uuid.Parse(job.MatchVideoID)
+	if err != nil {
+		logger.Error("invalid match video UUID",
+			zap.String("match_id", job.MatchID),
+			zap.Error(err))
+		return
+	}
+
+	matchVideo, err := repositories.NewMatchVideoRepository().GetByID(matchVideoID)
+	if err != nil || !matchVideo.IsReference || matchVideo.OutputKey != job.OutputKey {
+		return
+	}
+
+	match, err := repositories.NewMatchRepository().GetByID(matchVideo.MatchID)
+	if err != nil {
+		return
+	}
+
+	if match.ScoutJSON != "" {
+		err = q.EnqueueCondensedVideo(matchVideo, BuildBestRenditionKey(job.OutputKey, result.Renditions))
+	} else if result.Probe != nil && result.Probe.AudioCodec != "" {
+		err = q.EnqueueRallyDetection(matchVideo, BuildAnalysisInputKey(job.OutputKey, result.Renditions))
+	}
+	if err != nil {
+		logger.Error("Failed to enqueue follow-up video job",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.Error(err))
+	}
+}
+
+// EnqueueRallyDetection queues audio rally detection for a processed match video
+func (q *QueueManager) EnqueueRallyDetection(matchVideo *models.MatchVideo, inputKey string) error {
+	job := &VideoProcessingJob{
+		Type:         JobTypeDetectRallies,
+		MatchID:      matchVideo.MatchID.String(),
+		MatchVideoID: matchVideo.ID.String(),
+		InputKey:     inputKey,
+		OutputKey:    matchVideo.OutputKey,
+	}
+	if err := q.EnqueueVideo(job); err != nil {
+		return err
+	}
+
+	return repositories.NewMatchVideoRepository().UpdateRallyStatus(matchVideo.ID, matchVideo.OutputKey, StatusPending, "")
+}
+
+// handleRallyDetection runs audio rally detection for a job and stores the proposed segments
+func (q *QueueManager) handleRallyDetection(job *VideoProcessingJob) bool {
+	matchVideoID, err := uuid.Parse(job.MatchVideoID)
+	if err != nil {
+		logger.Error("invalid match video UUID", zap.String("match_id", job.MatchID), zap.Error(err))
+		return true
+	}
+
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+	matchVideo, err := matchVideoRepo.GetByID(matchVideoID)
+	if err != nil || matchVideo.OutputKey != job.OutputKey {
+		logger.Warn("skipping rally detection for removed or superseded video",
+			zap.String("match_video_id", job.MatchVideoID))
+		return true
+	}
+
+	_ = matchVideoRepo.UpdateRallyStatus(matchVideoID, job.OutputKey, StatusProcessing, "")
+
+	detected, err := q.processor.DetectRallies(job)
+	if err != nil {
+		logger.Error("Failed to detect rallies",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.Error(err))
+		_ = matchVideoRepo.UpdateRallyStatus(matchVideoID, job.OutputKey, StatusFailed, err.Error())
+		// Inputs without audio will never succeed
+		return errors.Is(err, ErrRejectedInput)
+	}
+
+	segments := make([]models.RallySegment, 0, len(detected))
+	for _, rally := range detected {
+		segments = append(segments, models.RallySegment{
+			MatchID:      matchVideo.MatchID,
+			MatchVideoID: matchVideo.ID,
+			StartMs:      rally.StartMs,
+			EndMs:        rally.EndMs,
+			Source:       models.RallyDetected,
+			Confidence:   rally.Confidence,
+		})
+	}
+
+	if err := repositories.NewRallySegmentRepository().ReplaceDetected(matchVideoID, segments); err != nil {
+		logger.Error("Failed to store detected rallies",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.Error(err))
+		_ = matchVideoRepo.UpdateRallyStatus(matchVideoID, job.OutputKey, StatusFailed, err.Error())
+		return false
+	}
+
+	logger.Info("Rally detection finished",
+		zap.String("match_video_id", job.MatchVideoID),
+		zap.Int("rallies", len(segments)))
+
+	_ = matchVideoRepo.UpdateRallyStatus(matchVideoID, job.OutputKey, StatusCompleted, "")
+
+	if len(segments) > 0 {
+		inputKey := BuildBestRenditionKey(job.OutputKey, readyRenditions(matchVideoID))
+		if err := q.EnqueueCondensedVideo(matchVideo, inputKey); err != nil {
+			logger.Error("Failed to enqueue condensed video",
+				zap.String("match_video_id", job.MatchVideoID),
+				zap.Error(err))
+		}
+	}
+	return true
+}
+
+// EnqueueCondensedVideo queues a rallies-only rendition of a processed match video
+func (q *QueueManager) EnqueueCondensedVideo(matchVideo *models.MatchVideo, inputKey string) error {
+	if inputKey == "" {
+		return errors.New("no rendition to condense")
+	}
+
+	job := &VideoProcessingJob{
+		Type:         JobTypeCondense,
+		MatchID:      matchVideo.MatchID.String(),
+		MatchVideoID: matchVideo.ID.String(),
+		InputKey:     inputKey,
+		OutputKey:    matchVideo.OutputKey,
+	}
+	if err := q.EnqueueVideo(job); err != nil {
+		return err
+	}
+
+	return repositories.NewMatchVideoRepository().UpdateCondensedStatus(matchVideo.ID, matchVideo.OutputKey, StatusPending, "")
+}
+
+// handleCondense cuts the rallies of a match video into a condensed rendition and records it
+func (q *QueueManager) handleCondense(job *VideoProcessingJob) bool {
+	matchVideoID, err := uuid.Parse(job.MatchVideoID)
+	if err != nil {
+		logger.Error("invalid match video UUID", zap.String("match_id", job.MatchID), zap.Error(err))
+		return true
+	}
+
+	matchVideoRepo := repositories.NewMatchVideoRepository()
+	matchVideo, err := matchVideoRepo.GetByID(matchVideoID)
+	if err != nil || matchVideo.OutputKey != job.OutputKey {
+		logger.Warn("skipping condensed video for removed or superseded video",
+			zap.String("match_video_id", job.MatchVideoID))
+		return true
+	}
+
+	_ = matchVideoRepo.UpdateCondensedStatus(matchVideoID, job.OutputKey, StatusProcessing, "")
+
+	rallies, err := condensedRallies(q.processor.store, matchVideo)
+	if err == nil && len(rallies) == 0 {
+		err = fmt.Errorf("%w: no rally boundaries for this video", ErrRejectedInput)
+	}
+
+	var result *ProcessingResult
+	if err == nil {
+		result, err = q.processor.CondenseVideo(job, rallies)
+	}
+	if err != nil {
+		logger.Error("Failed to produce condensed video",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.Error(err))
+		_ = matchVideoRepo.UpdateCondensedStatus(matchVideoID, job.OutputKey, StatusFailed, err.Error())
+		return errors.Is(err, ErrRejectedInput)
+	}
+
+	artifacts := make([]models.VideoArtifact, 0, len(result.Artifacts))
+	for _, artifact := range result.Artifacts {
+		artifact.MatchID = matchVideo.MatchID
+		artifact.MatchVideoID = matchVideo.ID
+		artifacts = append(artifacts, artifact)
+	}
+
+	kinds := []models.VideoArtifactKindEnum{models.ArtifactCondensed, models.ArtifactChapters}
+	if err := repositories.NewVideoArtifactRepository().ReplaceKindsForMatchVideo(matchVideoID, kinds, artifacts); err != nil {
+		logger.Error("Failed to record condensed video",
+			zap.String("match_video_id", job.MatchVideoID),
+			zap.Error(err))
+		_ = matchVideoRepo.UpdateCondensedStatus(matchVideoID, job.OutputKey, StatusFailed, err.Error())
+		return false
+	}
+
+	_ = matchVideoRepo.UpdateCondensedStatus(matchVideoID, job.OutputKey, StatusCompleted, "")
+	return true
+}
+
+// condensedRallies returns the rally boundaries of an angle in its own time: scout timecodes
+// when the match has a scout file with video times, otherwise its rally segments, falling back
+// to the reference angle's segments. Scout files and the reference share the reference time base.
+func condensedRallies(store storagePkg.ObjectStore, matchVideo *models.MatchVideo) ([]RallySpan, error) {
+	match, err := repositories.NewMatchRepository().GetByID(matchVideo.MatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match.ScoutJSON != "" {
+		jsonData, err := storagePkg.GetJSON(store, match.ScoutJSON)
+		if err == nil {
+			var timecodes []scoutPkg.RallyTimecode
+			if timecodes, err = scoutPkg.ExtractRallyTimecodes(jsonData); err == nil && len(timecodes) > 0 {
+				rallies := make([]RallySpan, 0, len(timecodes))
+				for _, timecode := range timecodes {
+					rallies = append(rallies, RallySpan{
+						StartMs: timecode.StartMs + matchVideo.OffsetMs,
+						EndMs:   timecode.EndMs + matchVideo.OffsetMs,
+					})
+				}
+				return rallies, nil
+			}
+		}
+		logger.Warn("Scout file has no usable video timecodes, using rally segments",
+			zap.String("match_id", match.ID.String()),
+			zap.Error(err))
+	}
+
+	rallyRepo := repositories.NewRallySegmentRepository()
+	segments, err := rallyRepo.GetByMatchVideoID(matchVideo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := int64(0)
+	if len(segments) == 0 && !matchVideo.IsReference {
+		angles, err := repositories.NewMatchVideoRepository().GetByMatchID(matchVideo.MatchID)
+		if err != nil {
+			return nil, err
+		}
+		for _, angle := range angles {
+			if angle.IsReference {
+				if segments, err = rallyRepo.GetByMatchVideoID(angle.ID); err != nil {
+					return nil, err
+				}
+				offset = matchVideo.OffsetMs
+				break
+			}
+		}
+	}
+
+	rallies := make([]RallySpan, 0, len(segments))
+	for _, segment := range segments {
+		rallies = append(rallies, RallySpan{StartMs: segment.StartMs + offset, EndMs: segment.EndMs + offset})
+	}
+	return rallies, nil
+}
+
+// readyRenditions lists the formats of a match video whose rendition was uploaded
+func readyRenditions(matchVideoID uuid.UUID) []string {
+	artifacts, err := repositories.NewVideoArtifactRepository().GetByMatchVideoIDs([]uuid.UUID{matchVideoID})
+	if err != nil {
+		return nil
+	}
+
+	var renditions []string
+	for _, artifact := range artifacts {
+		if artifact.Kind == models.ArtifactRendition && artifact.Status == models.ArtifactReady {
+			renditions = append(renditions, artifact.Label)
+		}
+	}
+	return renditions
+}
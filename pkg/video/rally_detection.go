@@ -0,0 +1,285 @@
+package video
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// Rally detection works on two audio channels rendered by ffmpeg at a low sample rate:
+// the full mono mix and the same mix band-passed around referee whistle frequencies.
+// A whistle is a window where the band carries most of the energy; a rally runs from one
+// whistle (service authorised) to the next (point over), with play noise in between.
+const (
+	RallySampleRate     = 16000
+	RallyWindowMs       = 50
+	RallyWhistleCenter  = 3500 // Hz, referee whistles sit between roughly 2.5 and 4.5 kHz
+	RallyWhistleWidth   = 2000 // Hz
+	RallyWhistleRatio   = 0.55 // share of window energy inside the whistle band
+	RallyWhistleLoudDb  = 10   // whistles must be this far above the noise floor
+	RallyQuietDb        = 6    // windows within this of the noise floor count as silence
+	RallyMinWhistleMs   = 150
+	RallyMinMs          = 3000
+	RallyMaxMs          = 60000
+	RallyMinActiveShare = 0.5 // share of non-silent windows a rally must contain
+	RallyBridgeMs       = 1000
+)
+
+// DetectedRally is a proposed rally, in milliseconds from the start of the analysed video
+type DetectedRally struct {
+	StartMs    int64
+	EndMs      int64
+	Confidence float64 // 0..1, lower when derived from activity alone
+}
+
+// audioWindow holds the features of one analysis window
+type audioWindow struct {
+	db        float64 // loudness of the full mix in dBFS
+	bandRatio float64 // whistle band energy / full energy
+}
+
+// whistleEvent is a run of consecutive whistle windows
+type whistleEvent struct {
+	start, end int // window indexes, end exclusive
+	strength   float64
+}
+
+// DetectRallies downloads the job input and proposes rally segments from its audio track
+func (p *VideoProcessor) DetectRallies(job *VideoProcessingJob) ([]DetectedRally, error) {
+	tempDir, err := os.MkdirTemp("", "rally-detection-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	inputPath := filepath.Join(tempDir, "input"+filepath.Ext(job.InputKey))
+	if err := p.downloadVideo(job.InputKey, inputPath); err != nil {
+		return nil, fmt.Errorf("failed to download video: %w", err)
+	}
+
+	windows, err := extractAudioWindows(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("%w: no audio to analyse", ErrRejectedInput)
+	}
+
+	return detectRallies(windows), nil
+}
+
+// extractAudioWindows streams mix and whistle band from ffmpeg as 16-bit stereo PCM
+// and reduces them to per-window features without holding the samples in memory
+func extractAudioWindows(inputPath string) ([]audioWindow, error) {
+	filter := fmt.Sprintf(
+		"[0:a:0]aresample=%d,aformat=channel_layouts=mono,asplit[mix][band];"+
+			"[band]bandpass=f=%d:width_type=h:w=%d[whistle];"+
+			"[mix][whistle]amerge=inputs=2[out]",
+		RallySampleRate, RallyWhistleCenter, RallyWhistleWidth)
+
+	cmd := exec.Command("ffmpeg",
+		"-v", "error",
+		"-i", inputPath,
+		"-filter_complex", filter,
+		"-map", "[out]",
+		"-f", "s16le",
+		"-acodec", "pcm_s16le",
+		"-",
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	windows, readErr := readAudioWindows(bufio.NewReaderSize(stdout, 64*1024))
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("audio extraction failed: %w", err)
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	return windows, nil
+}
+
+// readAudioWindows consumes interleaved mix/whistle samples and computes window features
+func readAudioWindows(r io.Reader) ([]audioWindow, error) {
+	frames := RallySampleRate * RallyWindowMs / 1000
+	buf := make([]int16, frames*2)
+
+	var windows []audioWindow
+	for {
+		err := binary.Read(r, binary.LittleEndian, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// A trailing partial window is too short to matter
+			return windows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var mixEnergy, bandEnergy float64
+		for i := 0; i < frames; i++ {
+			mix := float64(buf[2*i]) / math.MaxInt16
+			band := float64(buf[2*i+1]) / math.MaxInt16
+			mixEnergy += mix * mix
+			bandEnergy += band * band
+		}
+
+		window := audioWindow{db: -120}
+		if mixEnergy > 0 {
+			window.db = 10 * math.Log10(mixEnergy/float64(frames))
+			window.bandRatio = math.Min(bandEnergy/mixEnergy, 1)
+		}
+		windows = append(windows, window)
+	}
+}
+
+// detectRallies pairs whistles into rallies, falling back to bursts of activity
+// when the recording has too few whistles (e.g. a camera far from the referee)
+func detectRallies(windows []audioWindow) []DetectedRally {
+	floor := noiseFloor(windows)
+	events := findWhistles(windows, floor)
+
+	rallies := pairWhistles(windows, events, floor)
+	if len(rallies) == 0 {
+		rallies = activityRallies(windows, floor)
+	}
+	return rallies
+}
+
+// noiseFloor estimates the background level as the 20th percentile of window loudness
+func noiseFloor(windows []audioWindow) float64 {
+	levels := make([]float64, len(windows))
+	for i, window := range windows {
+		levels[i] = window.db
+	}
+	sort.Float64s(levels)
+	return levels[len(levels)/5]
+}
+
+// findWhistles merges loud, band-dominated windows into whistle events
+func findWhistles(windows []audioWindow, floor float64) []whistleEvent {
+	minWindows := RallyMinWhistleMs / RallyWindowMs
+
+	var events []whistleEvent
+	var current *whistleEvent
+	gap := 0
+
+	for i, window := range windows {
+		isWhistle := window.bandRatio >= RallyWhistleRatio && window.db >= floor+RallyWhistleLoudDb
+		switch {
+		case isWhistle && current == nil:
+			current = &whistleEvent{start: i, end: i + 1, strength: window.bandRatio}
+			gap = 0
+		case isWhistle:
+			current.end = i + 1
+			current.strength = math.Max(current.strength, window.bandRatio)
+			gap = 0
+		case current != nil:
+			// Tolerate a single dropped window inside a whistle
+			gap++
+			if gap > 1 {
+				if current.end-current.start >= minWindows {
+					events = append(events, *current)
+				}
+				current = nil
+			}
+		}
+	}
+	if current != nil && current.end-current.start >= minWindows {
+		events = append(events, *current)
+	}
+	return events
+}
+
+// pairWhistles turns start/end whistle pairs with play in between into rallies
+func pairWhistles(windows []audioWindow, events []whistleEvent, floor float64) []DetectedRally {
+	var rallies []DetectedRally
+
+	for i := 0; i+1 < len(events); {
+		start, end := events[i], events[i+1]
+		durationMs := int64(end.start-start.end) * RallyWindowMs
+		active := activeShare(windows[start.end:end.start], floor)
+
+		if durationMs < RallyMinMs || durationMs > RallyMaxMs || active < RallyMinActiveShare {
+			// Not a rally between these two; the second whistle may start the next one
+			i++
+			continue
+		}
+
+		rallies = append(rallies, DetectedRally{
+			StartMs:    int64(start.start) * RallyWindowMs,
+			EndMs:      int64(end.end) * RallyWindowMs,
+			Confidence: clamp01((start.strength + end.strength) / 2 * (0.5 + active/2)),
+		})
+		i += 2
+	}
+	return rallies
+}
+
+// activityRallies proposes rallies from runs of non-silent audio, bridging short pauses
+func activityRallies(windows []audioWindow, floor float64) []DetectedRally {
+	bridge := RallyBridgeMs / RallyWindowMs
+
+	var rallies []DetectedRally
+	start, lastActive := -1, -1
+
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		durationMs := int64(lastActive+1-start) * RallyWindowMs
+		if durationMs >= RallyMinMs && durationMs <= RallyMaxMs {
+			rallies = append(rallies, DetectedRally{
+				StartMs:    int64(start) * RallyWindowMs,
+				EndMs:      int64(lastActive+1) * RallyWindowMs,
+				Confidence: clamp01(0.3 * activeShare(windows[start:lastActive+1], floor)),
+			})
+		}
+		start = -1
+	}
+
+	for i, window := range windows {
+		if window.db < floor+RallyQuietDb {
+			if start >= 0 && i-lastActive > bridge {
+				flush()
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		lastActive = i
+	}
+	flush()
+	return rallies
+}
+
+// activeShare returns the share of windows louder than the silence threshold
+func activeShare(windows []audioWindow, floor float64) float64 {
+	if len(windows) == 0 {
+		return 0
+	}
+
+	active := 0
+	for _, window := range windows {
+		if window.db >= floor+RallyQuietDb {
+			active++
+		}
+	}
+	return float64(active) / float64(len(windows))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
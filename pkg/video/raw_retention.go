@@ -0,0 +1,208 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RawRetention deletes or archives raw uploads once their renditions are verified,
+// and flags raw uploads whose processing failed, following each season's policy
+type RawRetention struct {
+	store    storagePkg.Store
+	interval time.Duration
+
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	matchRepo      repositories.MatchRepository
+	policyRepo     repositories.RetentionPolicyRepository
+}
+
+// NewRawRetention creates a new raw retention job
+func NewRawRetention(store storagePkg.Store, interval time.Duration) *RawRetention {
+	return &RawRetention{
+		store:          store,
+		interval:       interval,
+		matchVideoRepo: repositories.NewMatchVideoRepository(),
+		artifactRepo:   repositories.NewVideoArtifactRepository(),
+		matchRepo:      repositories.NewMatchRepository(),
+		policyRepo:     repositories.NewRetentionPolicyRepository(),
+	}
+}
+
+// Start runs the retention pass on every tick until the process exits
+func (r *RawRetention) Start() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.Run()
+		<-ticker.C
+	}
+}
+
+// Run applies the retention policies to every raw upload that is still stored
+func (r *RawRetention) Run() {
+	candidates, err := r.matchVideoRepo.GetRawRetentionCandidates()
+	if err != nil {
+		logger.Error("Failed to fetch raw retention candidates", zap.Error(err))
+		return
+	}
+
+	policies := make(map[uuid.UUID]*models.RetentionPolicy)
+	changed := 0
+
+	for i := range candidates {
+		matchVideo := &candidates[i]
+
+		policy, err := r.policyForMatch(matchVideo.MatchID, policies)
+		if err != nil {
+			logger.Warn("Skipping raw video of unknown match",
+				zap.String("match_video_id", matchVideo.ID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		var status models.RawFileStatusEnum
+		var reason string
+		switch matchVideo.Status {
+		case StatusCompleted:
+			status, reason = r.evaluateProcessed(matchVideo, policy)
+		case StatusFailed, StatusRejected:
+			if time.Since(matchVideo.UpdatedAt) >= days(policy.FailedRawFlagDays) {
+				status = models.RawFileFlagged
+				reason = fmt.Sprintf("processing %s: %s", matchVideo.Status, matchVideo.Error)
+			}
+		}
+
+		if status == "" || status == models.RawFileStored {
+			continue
+		}
+
+		if err := r.matchVideoRepo.UpdateRawStatus(matchVideo.ID, matchVideo.RawKey, status, reason); err != nil {
+			logger.Error("Failed to record raw video status",
+				zap.String("match_video_id", matchVideo.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		changed++
+
+		logger.Info("Raw video retention applied",
+			zap.String("match_video_id", matchVideo.ID.String()),
+			zap.String("raw_key", matchVideo.RawKey),
+			zap.String("status", string(status)),
+			zap.String("reason", reason))
+	}
+
+	if changed > 0 {
+		logger.Info("Raw video retention finished", zap.Int("changed", changed))
+	}
+}
+
+// evaluateProcessed verifies the renditions of a processed video and applies the policy action.
+// It returns an empty status while the video is inside its grace period or should be kept.
+func (r *RawRetention) evaluateProcessed(matchVideo *models.MatchVideo, policy *models.RetentionPolicy) (models.RawFileStatusEnum, string) {
+	artifacts, err := r.artifactRepo.GetByMatchVideoIDs([]uuid.UUID{matchVideo.ID})
+	if err != nil {
+		logger.Error("Failed to fetch artifacts for retention",
+			zap.String("match_video_id", matchVideo.ID.String()),
+			zap.Error(err))
+		return "", ""
+	}
+
+	// The grace period starts when processing recorded its artifacts
+	processedAt := matchVideo.UpdatedAt
+	for _, artifact := range artifacts {
+		if artifact.CreatedAt.After(processedAt) {
+			processedAt = artifact.CreatedAt
+		}
+	}
+	if time.Since(processedAt) < days(policy.RawGraceDays) || policy.RawAction == models.RawRetentionKeep {
+		return "", ""
+	}
+
+	if reason := r.verifyRenditions(artifacts); reason != "" {
+		return models.RawFileFlagged, reason
+	}
+
+	if _, err := r.store.Head(matchVideo.RawKey); errors.Is(err, storagePkg.ErrNotFound) {
+		return models.RawFileDeleted, "raw file no longer in storage"
+	}
+
+	switch policy.RawAction {
+	case models.RawRetentionDelete:
+		if err := r.store.Delete(matchVideo.RawKey); err != nil {
+			logger.Error("Failed to delete raw video", zap.String("key", matchVideo.RawKey), zap.Error(err))
+			return "", ""
+		}
+		return models.RawFileDeleted, "renditions verified"
+	case models.RawRetentionArchive:
+		if err := r.store.Transition(matchVideo.RawKey, policy.ArchiveStorageClass, "storage=archived"); err != nil {
+			logger.Error("Failed to archive raw video", zap.String("key", matchVideo.RawKey), zap.Error(err))
+			return "", ""
+		}
+		return models.RawFileArchived, "renditions verified, moved to " + policy.ArchiveStorageClass
+	}
+	return "", ""
+}
+
+// verifyRenditions checks that every rendition was produced and is in storage with its recorded size.
+// It returns why the renditions cannot be trusted, or an empty string if they can.
+func (r *RawRetention) verifyRenditions(artifacts []models.VideoArtifact) string {
+	renditions := 0
+	for _, artifact := range artifacts {
+		if artifact.Kind != models.ArtifactRendition {
+			continue
+		}
+		renditions++
+
+		if artifact.Status != models.ArtifactReady {
+			return fmt.Sprintf("rendition %s failed: %s", artifact.Label, artifact.Error)
+		}
+
+		head, err := r.store.Head(artifact.S3Key)
+		if err != nil {
+			return fmt.Sprintf("rendition %s missing from storage", artifact.Label)
+		}
+		if artifact.Size > 0 && head.Size != artifact.Size {
+			return fmt.Sprintf("rendition %s has %d bytes, expected %d", artifact.Label, head.Size, artifact.Size)
+		}
+	}
+
+	if renditions == 0 {
+		return "no renditions recorded"
+	}
+	return ""
+}
+
+// policyForMatch returns the retention policy of a match's season, caching per season
+func (r *RawRetention) policyForMatch(matchID uuid.UUID, cache map[uuid.UUID]*models.RetentionPolicy) (*models.RetentionPolicy, error) {
+	match, err := r.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy, ok := cache[match.SeasonID]; ok {
+		return policy, nil
+	}
+
+	policy, err := r.policyRepo.GetBySeasonID(match.SeasonID)
+	if err != nil {
+		policy = models.DefaultRetentionPolicy(match.SeasonID)
+	}
+	cache[match.SeasonID] = policy
+	return policy, nil
+}
+
+// days converts a day count from a policy into a duration
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
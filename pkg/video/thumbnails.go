@@ -0,0 +1,221 @@
+package video
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Poster frame selection
+	posterCandidates    = 12  // timestamps sampled across the match
+	posterMinBrightness = 40  // mean luma below this is treated as a black frame
+	posterMaxBrightness = 215 // mean luma above this is treated as blown out
+
+	// Scrubbing previews
+	StoryboardInterval = 10 * time.Second
+	SpriteColumns      = 10
+	SpriteRows         = 10
+	SpriteThumbWidth   = 160
+)
+
+// posterCandidate is one extracted frame and its image statistics
+type posterCandidate struct {
+	path       string
+	brightness float64
+	contrast   float64
+}
+
+// SpriteSheet describes the generated scrubbing preview images
+type SpriteSheet struct {
+	Paths       []string
+	ThumbWidth  int
+	ThumbHeight int
+	Count       int
+}
+
+// selectPosterFrame samples frames across the video and keeps the most detailed, well-exposed one.
+// Each sample lets ffmpeg's thumbnail filter pick the most representative frame of a short batch,
+// which skips fades and motion blur; brightness and contrast then rank the candidates.
+func (p *VideoProcessor) selectPosterFrame(videoPath, workDir string, durationMs int64) (string, error) {
+	var candidates []posterCandidate
+
+	for i := 0; i < posterCandidates; i++ {
+		// Spread samples between 5% and 95% so warm-ups and credits are skipped
+		offset := time.Duration(float64(durationMs)*(0.05+0.9*float64(i)/float64(posterCandidates-1))) * time.Millisecond
+		framePath := filepath.Join(workDir, fmt.Sprintf("poster_candidate_%02d.jpg", i))
+
+		cmd := exec.Command("ffmpeg",
+			"-ss", formatFFmpegTime(offset),
+			"-i", videoPath,
+			"-vf", "thumbnail=50,scale=640:-2",
+			"-frames:v", "1",
+			"-y",
+			framePath,
+		)
+		if err := cmd.Run(); err != nil {
+			continue
+		}
+
+		brightness, contrast, err := measureFrame(framePath)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, posterCandidate{path: framePath, brightness: brightness, contrast: contrast})
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no poster candidate could be extracted")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return posterScore(candidates[i]) > posterScore(candidates[j])
+	})
+	return candidates[0].path, nil
+}
+
+// posterScore favours high contrast and penalises frames that are too dark or too bright
+func posterScore(c posterCandidate) float64 {
+	score := c.contrast
+	if c.brightness < posterMinBrightness || c.brightness > posterMaxBrightness {
+		score -= 1000
+	}
+	// Prefer mid-range exposure among otherwise similar frames
+	score -= math.Abs(c.brightness-128) / 8
+	return score
+}
+
+// measureFrame returns the mean luma and its standard deviation for a JPEG frame
+func measureFrame(path string) (float64, float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	img, err := jpeg.Decode(file)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bounds := img.Bounds()
+	var sum, sumSquares, count float64
+
+	// Every other pixel in each direction is plenty for exposure statistics
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x += 2 {
+			luma := lumaAt(img, x, y)
+			sum += luma
+			sumSquares += luma * luma
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, 0, fmt.Errorf("empty frame")
+	}
+
+	mean := sum / count
+	variance := sumSquares/count - mean*mean
+	return mean, math.Sqrt(math.Max(variance, 0)), nil
+}
+
+// lumaAt returns the Rec. 601 luma of a pixel on a 0-255 scale
+func lumaAt(img image.Image, x, y int) float64 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+}
+
+// generateSpriteSheets tiles one preview every StoryboardInterval into JPEG sprite sheets
+func (p *VideoProcessor) generateSpriteSheets(videoPath, workDir string, probe *ProbeResult) (*SpriteSheet, error) {
+	thumbHeight := SpriteThumbWidth * 9 / 16
+	if probe.Width > 0 && probe.Height > 0 {
+		thumbHeight = int(math.Round(float64(SpriteThumbWidth)*float64(probe.Height)/float64(probe.Width)/2)) * 2
+	}
+
+	spriteDir := filepath.Join(workDir, "sprites")
+	if err := os.MkdirAll(spriteDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-i", videoPath,
+		"-vf", fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d",
+			int(StoryboardInterval.Seconds()), SpriteThumbWidth, thumbHeight, SpriteColumns, SpriteRows),
+		"-q:v", "5",
+		"-y",
+		filepath.Join(spriteDir, "sprite_%03d.jpg"),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(spriteDir, "sprite_*.jpg"))
+	if err != nil || len(paths) == 0 {
+		return nil, fmt.Errorf("no sprite sheets were produced")
+	}
+	sort.Strings(paths)
+
+	count := int(math.Ceil(float64(probe.DurationMs) / float64(StoryboardInterval.Milliseconds())))
+	if maxCount := len(paths) * SpriteColumns * SpriteRows; count > maxCount {
+		count = maxCount
+	}
+
+	return &SpriteSheet{
+		Paths:       paths,
+		ThumbWidth:  SpriteThumbWidth,
+		ThumbHeight: thumbHeight,
+		Count:       count,
+	}, nil
+}
+
+// BuildStoryboardVTT writes a WebVTT track whose cues point into the sprite sheets via #xywh
+// media fragments. Sprite names are relative so the track works wherever it is served from.
+func BuildStoryboardVTT(sheet *SpriteSheet, spriteNames []string, durationMs int64) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+
+	perSheet := SpriteColumns * SpriteRows
+	interval := StoryboardInterval
+
+	for i := 0; i < sheet.Count; i++ {
+		sheetIndex := i / perSheet
+		if sheetIndex >= len(spriteNames) {
+			break
+		}
+
+		tile := i % perSheet
+		x := (tile % SpriteColumns) * sheet.ThumbWidth
+		y := (tile / SpriteColumns) * sheet.ThumbHeight
+
+		start := time.Duration(i) * interval
+		end := start + interval
+		if total := time.Duration(durationMs) * time.Millisecond; end > total {
+			end = total
+		}
+
+		fmt.Fprintf(&b, "%s --> %s\n%s#xywh=%d,%d,%d,%d\n\n",
+			formatVTTTime(start), formatVTTTime(end),
+			spriteNames[sheetIndex], x, y, sheet.ThumbWidth, sheet.ThumbHeight)
+	}
+
+	return b.String()
+}
+
+// formatVTTTime formats a duration as HH:MM:SS.mmm
+func formatVTTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, ms%1000)
+}
+
+// formatFFmpegTime formats a duration as seconds with millisecond precision for -ss
+func formatFFmpegTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -1,35 +1,59 @@
 package video
 
-import "time"
+import (
+	"time"
+
+	"go-gin-starter/models"
+)
 
 // VideoProcessingJob represents a video processing task
 type VideoProcessingJob struct {
-	MatchID   string    `json:"match_id"`
-	InputKey  string    `json:"input_key"`  // S3 key for raw video
-	OutputKey string    `json:"output_key"` // S3 key for processed video
-	CreatedAt time.Time `json:"created_at"`
-	Status    string    `json:"status"`
-	Error     string    `json:"error,omitempty"`
+	Type         string    `json:"type,omitempty"` // JobTypeProcess when empty
+	MatchID      string    `json:"match_id"`
+	MatchVideoID string    `json:"match_video_id"` // camera angle the job belongs to
+	InputKey     string    `json:"input_key"`      // S3 key for raw video
+	OutputKey    string    `json:"output_key"`     // S3 key for processed video
+	CreatedAt    time.Time `json:"created_at"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
 }
 
 // VideoFormat represents different video formats
 type VideoFormat struct {
 	Resolution string
+	Height     int
 	Bitrate    string
 	MaxSize    int64 // in bytes
 }
 
+// ProcessingResult describes what a processing run produced
+type ProcessingResult struct {
+	Probe         *ProbeResult
+	Renditions    []string
+	ThumbnailKey  string
+	StoryboardKey string
+	SpriteKeys    []string
+	Artifacts     []models.VideoArtifact // every object written (or attempted) in S3
+}
+
 const (
 	// Status constants
 	StatusPending    = "pending"
 	StatusProcessing = "processing"
 	StatusCompleted  = "completed"
 	StatusFailed     = "failed"
+	StatusRejected   = "rejected"
+
+	// Job types
+	JobTypeProcess       = "process"
+	JobTypeDetectRallies = "detect_rallies"
+	JobTypeCondense      = "condense"
 
 	// Folder structure
 	RawVideoFolder   = "raw"
 	CompressedFolder = "compressed"
 	ThumbnailsFolder = "thumbnails"
+	ManifestsFolder  = "manifests"
 
 	// Default formats
 	Format1080p = "1080p"
@@ -41,17 +65,39 @@ const (
 var DefaultVideoFormats = map[string]VideoFormat{
 	Format1080p: {
 		Resolution: "1920x1080",
+		Height:     1080,
 		Bitrate:    "4M",
 		MaxSize:    1.5 * 1024 * 1024 * 1024, // 1.5GB
 	},
 	Format720p: {
 		Resolution: "1280x720",
+		Height:     720,
 		Bitrate:    "2.5M",
 		MaxSize:    800 * 1024 * 1024, // 800MB
 	},
 	Format480p: {
 		Resolution: "854x480",
+		Height:     480,
 		Bitrate:    "1M",
 		MaxSize:    400 * 1024 * 1024, // 400MB
 	},
 }
+
+// VideoFormatLadder lists the formats from highest to lowest resolution
+var VideoFormatLadder = []string{Format1080p, Format720p, Format480p}
+
+// BuildRenditionLadder returns the formats worth producing for a source of the given height.
+// Sources are never upscaled; anything below the smallest format still gets that one rendition.
+func BuildRenditionLadder(sourceHeight int) []string {
+	var ladder []string
+	for _, name := range VideoFormatLadder {
+		if DefaultVideoFormats[name].Height <= sourceHeight {
+			ladder = append(ladder, name)
+		}
+	}
+
+	if len(ladder) == 0 {
+		ladder = append(ladder, VideoFormatLadder[len(VideoFormatLadder)-1])
+	}
+	return ladder
+}
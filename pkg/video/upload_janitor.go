@@ -0,0 +1,84 @@
+package video
+
+import (
+	"time"
+
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/repositories"
+
+	"go.uber.org/zap"
+)
+
+// UploadJanitor aborts presigned multipart uploads that were never completed
+type UploadJanitor struct {
+	store    storagePkg.MultipartStore
+	interval time.Duration
+}
+
+// NewUploadJanitor creates a new upload janitor instance
+func NewUploadJanitor(store storagePkg.MultipartStore, interval time.Duration) *UploadJanitor {
+	return &UploadJanitor{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the cleanup on every tick until the process exits
+func (j *UploadJanitor) Start() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.Cleanup()
+		<-ticker.C
+	}
+}
+
+// Cleanup expires tracked uploads past their deadline and aborts untracked stale ones
+func (j *UploadJanitor) Cleanup() {
+	now := time.Now()
+	uploadRepo := repositories.NewVideoUploadRepository()
+
+	expired, err := uploadRepo.GetExpiredPending(now)
+	if err != nil {
+		logger.Error("Failed to fetch expired video uploads", zap.Error(err))
+	}
+
+	for i := range expired {
+		upload := &expired[i]
+		if err := j.store.AbortMultipartUpload(upload.ObjectKey, upload.S3UploadID); err != nil {
+			logger.Warn("Failed to abort expired multipart upload",
+				zap.String("upload_id", upload.ID.String()),
+				zap.Error(err))
+		}
+
+		upload.Status = models.VideoUploadExpired
+		if err := uploadRepo.Update(upload); err != nil {
+			logger.Error("Failed to mark video upload expired",
+				zap.String("upload_id", upload.ID.String()),
+				zap.Error(err))
+		}
+	}
+
+	// Sweep multipart uploads storage still holds but the database lost track of
+	stale, err := j.store.ListStaleMultipartUploads("videos/", now.Add(-2*constants.VideoUploadExpiry))
+	if err != nil {
+		logger.Error("Failed to list stale multipart uploads", zap.Error(err))
+		return
+	}
+
+	for _, upload := range stale {
+		if err := j.store.AbortMultipartUpload(upload.Key, upload.UploadID); err != nil {
+			logger.Warn("Failed to abort stale multipart upload", zap.String("key", upload.Key), zap.Error(err))
+		}
+	}
+
+	if len(expired) > 0 || len(stale) > 0 {
+		logger.Info("Video upload cleanup finished",
+			zap.Int("expired", len(expired)),
+			zap.Int("stale", len(stale)))
+	}
+}
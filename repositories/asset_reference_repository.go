@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"encoding/json"
+
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AssetReferenceRepository defines the interface for collecting every stored object the database points at
+type AssetReferenceRepository interface {
+	GetReferences() (*AssetReferences, error)
+	RewriteReferences(rewrite func(table, ref string) string) (int, error)
+}
+
+// referenceColumn is a column holding one object reference, or a JSON list or map of them
+type referenceColumn struct {
+	table  string
+	column string
+	json   bool
+}
+
+// referenceColumns lists every column that points at a stored object
+var referenceColumns = []referenceColumn{
+	{table: "users", column: "avatar"},
+	{table: "users", column: "avatar_variants", json: true},
+	{table: "teams", column: "logo"},
+	{table: "teams", column: "logo_variants", json: true},
+	{table: "seasons", column: "logo"},
+	{table: "seasons", column: "logo_variants", json: true},
+	{table: "matches", column: "video_url"},
+	{table: "matches", column: "thumbnail_url"},
+	{table: "matches", column: "scout_json"},
+	{table: "match_videos", column: "video_url"},
+	{table: "match_videos", column: "thumbnail_url"},
+	{table: "match_videos", column: "storyboard_url"},
+	{table: "match_videos", column: "sprite_urls", json: true},
+}
+
+// AssetReferences holds the object references of all live rows. Values are object keys,
+// or URLs for rows written before keys were stored; empty values are left out.
+type AssetReferences struct {
+	Values   []string
+	MatchIDs []uuid.UUID // live matches, whose scout keys and raw folders are derived from the ID
+}
+
+// GormAssetReferenceRepository implements AssetReferenceRepository using GORM
+type GormAssetReferenceRepository struct{}
+
+// NewAssetReferenceRepository creates a new instance of AssetReferenceRepository
+func NewAssetReferenceRepository() AssetReferenceRepository {
+	return &GormAssetReferenceRepository{}
+}
+
+// GetReferences reads the references of users, teams, seasons, matches, match videos,
+// their artifacts and pending uploads. Soft-deleted rows and the videos of soft-deleted
+// matches do not count, so their objects become orphans.
+func (r *GormAssetReferenceRepository) GetReferences() (*AssetReferences, error) {
+	refs := &AssetReferences{}
+	add := func(values ...string) {
+		for _, value := range values {
+			if value != "" {
+				refs.Values = append(refs.Values, value)
+			}
+		}
+	}
+	addVariants := func(variants models.ImageVariants) {
+		for _, value := range variants {
+			add(value)
+		}
+	}
+
+	var users []models.User
+	if err := database.DB.Select("avatar", "avatar_variants").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		add(user.Avatar)
+		addVariants(user.AvatarVariants)
+	}
+
+	var teams []models.Team
+	if err := database.DB.Select("logo", "logo_variants").Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		add(team.Logo)
+		addVariants(team.LogoVariants)
+	}
+
+	var seasons []models.Season
+	if err := database.DB.Select("logo", "logo_variants").Find(&seasons).Error; err != nil {
+		return nil, err
+	}
+	for _, season := range seasons {
+		add(season.Logo)
+		addVariants(season.LogoVariants)
+	}
+
+	var matches []models.Match
+	if err := database.DB.Select("id", "video_url", "thumbnail_url", "scout_json").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		add(match.VideoURL, match.ThumbnailURL, match.ScoutJSON)
+		refs.MatchIDs = append(refs.MatchIDs, match.ID)
+	}
+
+	var matchVideos []models.MatchVideo
+	err := database.DB.
+		Select("match_videos.raw_key", "match_videos.output_key", "match_videos.video_url",
+			"match_videos.thumbnail_url", "match_videos.storyboard_url", "match_videos.sprite_urls").
+		Joins("JOIN matches ON matches.id = match_videos.match_id AND matches.deleted_at IS NULL").
+		Find(&matchVideos).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, matchVideo := range matchVideos {
+		add(matchVideo.RawKey, matchVideo.OutputKey, matchVideo.VideoURL, matchVideo.ThumbnailURL, matchVideo.StoryboardURL)
+		add(matchVideo.SpriteURLs...)
+	}
+
+	var artifactKeys []string
+	err = database.DB.Model(&models.VideoArtifact{}).
+		Joins("JOIN match_videos ON match_videos.id = video_artifacts.match_video_id AND match_videos.deleted_at IS NULL").
+		Joins("JOIN matches ON matches.id = match_videos.match_id AND matches.deleted_at IS NULL").
+		Pluck("video_artifacts.s3_key", &artifactKeys).Error
+	if err != nil {
+		return nil, err
+	}
+	add(artifactKeys...)
+
+	// Uploads still in flight own their keys before any match video points at them
+	var uploadKeys []string
+	err = database.DB.Model(&models.VideoUpload{}).
+		Where("status = ?", models.VideoUploadPending).
+		Pluck("object_key", &uploadKeys).Error
+	if err != nil {
+		return nil, err
+	}
+	add(uploadKeys...)
+
+	return refs, nil
+}
+
+// RewriteReferences passes every stored reference, including those of soft-deleted rows,
+// through rewrite and saves the values it changes. JSON columns are rewritten element by
+// element. Rows are updated without touching updated_at. It returns the number of rows changed.
+func (r *GormAssetReferenceRepository) RewriteReferences(rewrite func(table, ref string) string) (int, error) {
+	changed := 0
+	for _, ref := range referenceColumns {
+		var rows []struct {
+			ID    uuid.UUID
+			Value string
+		}
+		err := database.DB.Table(ref.table).
+			Select("id, " + ref.column + "::text AS value").
+			Where(ref.column + " IS NOT NULL").
+			Find(&rows).Error
+		if err != nil {
+			return changed, err
+		}
+
+		for _, row := range rows {
+			modified := false
+			value, err := rewriteValue(row.Value, ref.json, func(value string) string {
+				rewritten := rewrite(ref.table, value)
+				modified = modified || rewritten != value
+				return rewritten
+			})
+			if err != nil || !modified {
+				continue
+			}
+
+			var update interface{} = value
+			if ref.json {
+				update = gorm.Expr("?::jsonb", value)
+			}
+			if err := database.DB.Table(ref.table).Where("id = ?", row.ID).UpdateColumn(ref.column, update).Error; err != nil {
+				return changed, err
+			}
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// rewriteValue applies rewrite to a plain reference, or to each reference of a JSON array or object
+func rewriteValue(value string, isJSON bool, rewrite func(string) string) (string, error) {
+	if !isJSON {
+		if value == "" {
+			return value, nil
+		}
+		return rewrite(value), nil
+	}
+
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err == nil {
+		for i := range list {
+			list[i] = rewrite(list[i])
+		}
+		encoded, err := json.Marshal(list)
+		return string(encoded), err
+	}
+
+	var named map[string]string
+	if err := json.Unmarshal([]byte(value), &named); err != nil {
+		return value, err
+	}
+	for name := range named {
+		named[name] = rewrite(named[name])
+	}
+	encoded, err := json.Marshal(named)
+	return string(encoded), err
+}
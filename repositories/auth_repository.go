@@ -6,17 +6,23 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // AuthRepository defines the interface for authentication data operations
 type AuthRepository interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByUsername(username string) (*models.User, error)
-	UpdateRefreshToken(userID uuid.UUID, token string, expiry time.Time) error
-	UpdateResetToken(email, token string, expiry time.Time) error
-	GetUserByRefreshToken(token string) (*models.User, error)
-	GetUserByResetToken(token string) (*models.User, error)
+	UpdateResetToken(email, tokenHash string, expiry time.Time) error
+	GetUserByResetToken(tokenHash string) (*models.User, error)
 	UpdatePassword(userID uuid.UUID, hashedPassword string) error
+	UpdateEmailVerifiedAt(userID uuid.UUID, verifiedAt *time.Time) error
+	UpdateVerificationSentAt(userID uuid.UUID, sentAt time.Time) error
+	BackfillEmailVerification() (int64, error)
+	HashStoredResetTokens() (int64, error)
+	RecordFailedLogin(userID uuid.UUID, at time.Time, window time.Duration) (int, error)
+	LockAccount(userID uuid.UUID, until time.Time) error
+	ClearFailedLogins(userID uuid.UUID) error
 }
 
 // GormAuthRepository implements AuthRepository using GORM
@@ -45,35 +51,18 @@ func (r *GormAuthRepository) GetUserByUsername(username string) (*models.User, e
 	return &user, nil
 }
 
-// UpdateRefreshToken updates a user's refresh token and expiry
-func (r *GormAuthRepository) UpdateRefreshToken(userID uuid.UUID, token string, expiry time.Time) error {
-	return database.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
-		"refresh_token":        token,
-		"refresh_token_expiry": expiry,
-	}).Error
-}
-
-// UpdateResetToken updates a user's password reset token and expiry
-func (r *GormAuthRepository) UpdateResetToken(email, token string, expiry time.Time) error {
+// UpdateResetToken updates the hash of a user's password reset token and its expiry
+func (r *GormAuthRepository) UpdateResetToken(email, tokenHash string, expiry time.Time) error {
 	return database.DB.Model(&models.User{}).Where("email = ?", email).Updates(map[string]interface{}{
-		"reset_password_token":   token,
+		"reset_password_token":   tokenHash,
 		"reset_password_expires": expiry,
 	}).Error
 }
 
-// GetUserByRefreshToken finds a user by their refresh token
-func (r *GormAuthRepository) GetUserByRefreshToken(token string) (*models.User, error) {
-	var user models.User
-	if err := database.DB.Where("refresh_token = ?", token).First(&user).Error; err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-// GetUserByResetToken finds a user by their password reset token
-func (r *GormAuthRepository) GetUserByResetToken(token string) (*models.User, error) {
+// GetUserByResetToken finds a user by the hash of their password reset token
+func (r *GormAuthRepository) GetUserByResetToken(tokenHash string) (*models.User, error) {
 	var user models.User
-	if err := database.DB.Where("reset_password_token = ?", token).First(&user).Error; err != nil {
+	if err := database.DB.Where("reset_password_token = ?", tokenHash).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -88,6 +77,59 @@ func (r *GormAuthRepository) UpdatePassword(userID uuid.UUID, hashedPassword str
 	}).Error
 }
 
+// UpdateEmailVerifiedAt marks a user's email address verified, or unverified when verifiedAt is nil
+func (r *GormAuthRepository) UpdateEmailVerifiedAt(userID uuid.UUID, verifiedAt *time.Time) error {
+	return database.DB.Model(&models.User{}).Where("id = ?", userID).Update("email_verified_at", verifiedAt).Error
+}
+
+// UpdateVerificationSentAt records when a verification link was last sent to a user
+func (r *GormAuthRepository) UpdateVerificationSentAt(userID uuid.UUID, sentAt time.Time) error {
+	return database.DB.Model(&models.User{}).Where("id = ?", userID).Update("verification_sent_at", sentAt).Error
+}
+
+// BackfillEmailVerification marks every unverified user verified as of their registration.
+// It runs once, when verification is introduced, so existing accounts keep their access.
+func (r *GormAuthRepository) BackfillEmailVerification() (int64, error) {
+	result := database.DB.Model(&models.User{}).Unscoped().
+		Where("email_verified_at IS NULL").
+		Update("email_verified_at", gorm.Expr("created_at"))
+	return result.RowsAffected, result.Error
+}
+
+// HashStoredResetTokens replaces reset tokens stored in plaintext by their hash. Plain
+// tokens are 32 hex characters and hashes 64, so it is safe to run on every start.
+func (r *GormAuthRepository) HashStoredResetTokens() (int64, error) {
+	result := database.DB.Model(&models.User{}).Unscoped().
+		Where("LENGTH(reset_password_token) = 32").
+		Update("reset_password_token", gorm.Expr("ENCODE(SHA256(CONVERT_TO(reset_password_token, 'UTF8')), 'hex')"))
+	return result.RowsAffected, result.Error
+}
+
+// RecordFailedLogin counts a failed login of a user and returns the failures so far.
+// The count starts over if the previous failure is older than window.
+func (r *GormAuthRepository) RecordFailedLogin(userID uuid.UUID, at time.Time, window time.Duration) (int, error) {
+	var failures int
+	err := database.DB.Raw(`
+		UPDATE users SET
+			failed_login_count = CASE WHEN last_failed_login_at > ? THEN failed_login_count + 1 ELSE 1 END,
+			last_failed_login_at = ?
+		WHERE id = ?
+		RETURNING failed_login_count`, at.Add(-window), at, userID).Scan(&failures).Error
+	return failures, err
+}
+
+// LockAccount refuses password logins of a user until the given time
+func (r *GormAuthRepository) LockAccount(userID uuid.UUID, until time.Time) error {
+	return database.DB.Exec("UPDATE users SET locked_until = ? WHERE id = ?", until, userID).Error
+}
+
+// ClearFailedLogins forgets the failed logins of a user and lifts a lockout
+func (r *GormAuthRepository) ClearFailedLogins(userID uuid.UUID) error {
+	return database.DB.Exec(
+		"UPDATE users SET failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL WHERE id = ?",
+		userID).Error
+}
+
 func SaveResetToken(user *models.User) error {
 	return database.DB.Save(user).Error
 }
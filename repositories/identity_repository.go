@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdentityRepository defines the interface for OIDC identities and pending OIDC logins
+type IdentityRepository interface {
+	CreateLoginState(state *models.OIDCLoginState) error
+	ConsumeLoginState(state string) (*models.OIDCLoginState, error)
+	FindIdentity(provider, subject string) (*models.UserIdentity, error)
+	FindUserByEmail(email string) (*models.User, error)
+	CreateIdentity(identity *models.UserIdentity) error
+	CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error
+	RecordLogin(identityID uuid.UUID, email string, at time.Time) error
+}
+
+// GormIdentityRepository implements IdentityRepository using GORM
+type GormIdentityRepository struct{}
+
+// NewIdentityRepository creates a new instance of IdentityRepository
+func NewIdentityRepository() IdentityRepository {
+	return &GormIdentityRepository{}
+}
+
+// CreateLoginState stores a pending login and drops those that expired
+func (r *GormIdentityRepository) CreateLoginState(state *models.OIDCLoginState) error {
+	if err := database.DB.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		return err
+	}
+	return database.DB.Create(state).Error
+}
+
+// ConsumeLoginState deletes and returns a pending login that has not expired, so each
+// state can complete one login
+func (r *GormIdentityRepository) ConsumeLoginState(state string) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	err := database.DB.Clauses(clause.Returning{}).
+		Where("state = ? AND expires_at > ?", state, time.Now()).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+// FindIdentity finds the identity a provider knows by a subject
+func (r *GormIdentityRepository) FindIdentity(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := database.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindUserByEmail finds a user by email address, ignoring case
+func (r *GormIdentityRepository) FindUserByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateIdentity links an identity to an existing user
+func (r *GormIdentityRepository) CreateIdentity(identity *models.UserIdentity) error {
+	return database.DB.Create(identity).Error
+}
+
+// CreateUserWithIdentity creates a user and their first identity together
+func (r *GormIdentityRepository) CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+// RecordLogin stores when an identity was last used and the email it came with
+func (r *GormIdentityRepository) RecordLogin(identityID uuid.UUID, email string, at time.Time) error {
+	return database.DB.Model(&models.UserIdentity{}).Where("id = ?", identityID).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": at,
+	}).Error
+}
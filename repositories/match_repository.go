@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"strings"
+
 	"go-gin-starter/database"
 	"go-gin-starter/models"
 
@@ -14,6 +16,37 @@ type MatchRepository interface {
 	GetByID(id uuid.UUID) (*models.Match, error)
 	Update(match *models.Match) error
 	Delete(id uuid.UUID) error
+	GetLegacyVideos() ([]LegacyVideo, error)
+}
+
+// LegacyVideo is a video stored on its match before camera angles existed, with the
+// processing columns matches carried until then where the database still has them
+type LegacyVideo struct {
+	MatchID       uuid.UUID
+	VideoURL      string
+	ThumbnailURL  string
+	StoryboardURL string
+	SpriteURLs    models.StringArray
+	Status        string
+	Error         string
+	Metadata      models.VideoMetadata `gorm:"embedded;embeddedPrefix:video_"`
+	RawKey        string               // raw upload the video was processed from, if it went through a tracked upload
+}
+
+// legacyVideoColumns are the processing columns of matches that camera angles replaced,
+// each with the name it is selected under
+var legacyVideoColumns = [][2]string{
+	{"storyboard_url", "storyboard_url"},
+	{"sprite_urls", "sprite_urls"},
+	{"video_status", "status"},
+	{"video_error", "error"},
+	{"video_duration_ms", "video_duration_ms"},
+	{"video_width", "video_width"},
+	{"video_height", "video_height"},
+	{"video_frame_rate", "video_frame_rate"},
+	{"video_video_codec", "video_video_codec"},
+	{"video_audio_codec", "video_audio_codec"},
+	{"video_file_size", "video_file_size"},
 }
 
 // GormMatchRepository implements MatchRepository using GORM
@@ -57,6 +90,30 @@ func (r *GormMatchRepository) Delete(id uuid.UUID) error {
 	return database.DB.Delete(&models.Match{}, "id = ?", id).Error
 }
 
+// GetLegacyVideos fetches the videos of matches that have a video URL but no camera angle
+// records, i.e. videos uploaded before angles were introduced. Processing columns are only
+// read where they still exist; the schema no longer declares them, so they are never dropped.
+func (r *GormMatchRepository) GetLegacyVideos() ([]LegacyVideo, error) {
+	columns := []string{"matches.id AS match_id", "matches.video_url", "matches.thumbnail_url"}
+	migrator := database.DB.Migrator()
+	for _, column := range legacyVideoColumns {
+		if migrator.HasColumn(&models.Match{}, column[0]) {
+			columns = append(columns, "matches."+column[0]+" AS "+column[1])
+		}
+	}
+	columns = append(columns, `(SELECT vu.object_key FROM video_uploads vu
+		WHERE vu.match_id = matches.id AND vu.output_key = matches.video_url AND vu.status = ?
+		ORDER BY vu.completed_at DESC LIMIT 1) AS raw_key`)
+
+	var videos []LegacyVideo
+	err := database.DB.Model(&models.Match{}).
+		Select(strings.Join(columns, ", "), models.VideoUploadCompleted).
+		Where("matches.video_url <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM match_videos mv WHERE mv.match_id = matches.id AND mv.deleted_at IS NULL)").
+		Scan(&videos).Error
+	return videos, err
+}
+
 // Legacy functions for backward compatibility
 // These will be removed once migration is complete
 
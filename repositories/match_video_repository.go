@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"time"
+
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+)
+
+// MatchVideoRepository defines the interface for match video (camera angle) data operations
+type MatchVideoRepository interface {
+	Create(matchVideo *models.MatchVideo) error
+	GetByID(id uuid.UUID) (*models.MatchVideo, error)
+	GetByMatchID(matchID uuid.UUID) ([]models.MatchVideo, error)
+	GetByMatchAndAngle(matchID uuid.UUID, angle string) (*models.MatchVideo, error)
+	Update(matchVideo *models.MatchVideo) error
+	Delete(id uuid.UUID) error
+	GetCompletedWithoutArtifacts() ([]models.MatchVideo, error)
+	GetRawRetentionCandidates() ([]models.MatchVideo, error)
+	GetByRawStatus(status models.RawFileStatusEnum, seasonID *uuid.UUID) ([]models.MatchVideo, error)
+	UpdateRawStatus(id uuid.UUID, rawKey string, status models.RawFileStatusEnum, reason string) error
+	SumRawSizeByStatus(seasonID *uuid.UUID) ([]StorageUsage, error)
+	UpdateRallyStatus(id uuid.UUID, outputKey, status, errMsg string) error
+	UpdateCondensedStatus(id uuid.UUID, outputKey, status, errMsg string) error
+}
+
+// GormMatchVideoRepository implements MatchVideoRepository using GORM
+type GormMatchVideoRepository struct{}
+
+// NewMatchVideoRepository creates a new instance of MatchVideoRepository
+func NewMatchVideoRepository() MatchVideoRepository {
+	return &GormMatchVideoRepository{}
+}
+
+// Create inserts a new match video record
+func (r *GormMatchVideoRepository) Create(matchVideo *models.MatchVideo) error {
+	return database.DB.Create(matchVideo).Error
+}
+
+// GetByID fetches a match video by ID
+func (r *GormMatchVideoRepository) GetByID(id uuid.UUID) (*models.MatchVideo, error) {
+	var matchVideo models.MatchVideo
+	if err := database.DB.First(&matchVideo, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &matchVideo, nil
+}
+
+// GetByMatchID fetches all angles of a match, reference angle first
+func (r *GormMatchVideoRepository) GetByMatchID(matchID uuid.UUID) ([]models.MatchVideo, error) {
+	var matchVideos []models.MatchVideo
+	err := database.DB.
+		Where("match_id = ?", matchID).
+		Order("is_reference DESC, created_at ASC").
+		Find(&matchVideos).Error
+	return matchVideos, err
+}
+
+// GetByMatchAndAngle fetches the video of a match filmed from the given angle
+func (r *GormMatchVideoRepository) GetByMatchAndAngle(matchID uuid.UUID, angle string) (*models.MatchVideo, error) {
+	var matchVideo models.MatchVideo
+	if err := database.DB.First(&matchVideo, "match_id = ? AND angle = ?", matchID, angle).Error; err != nil {
+		return nil, err
+	}
+	return &matchVideo, nil
+}
+
+// Update saves changes to an existing match video
+func (r *GormMatchVideoRepository) Update(matchVideo *models.MatchVideo) error {
+	return database.DB.Save(matchVideo).Error
+}
+
+// Delete soft deletes a match video by ID
+func (r *GormMatchVideoRepository) Delete(id uuid.UUID) error {
+	return database.DB.Delete(&models.MatchVideo{}, "id = ?", id).Error
+}
+
+// GetCompletedWithoutArtifacts fetches processed videos that have no artifact records yet
+func (r *GormMatchVideoRepository) GetCompletedWithoutArtifacts() ([]models.MatchVideo, error) {
+	var matchVideos []models.MatchVideo
+	err := database.DB.
+		Where("status = ?", "completed").
+		Where("NOT EXISTS (SELECT 1 FROM video_artifacts va WHERE va.match_video_id = match_videos.id)").
+		Find(&matchVideos).Error
+	return matchVideos, err
+}
+
+// GetRawRetentionCandidates fetches videos whose raw upload is still stored and whose processing has finished
+func (r *GormMatchVideoRepository) GetRawRetentionCandidates() ([]models.MatchVideo, error) {
+	var matchVideos []models.MatchVideo
+	err := database.DB.
+		Where("raw_status = ? AND raw_key <> ''", models.RawFileStored).
+		Where("status IN ?", []string{"completed", "failed", "rejected"}).
+		Find(&matchVideos).Error
+	return matchVideos, err
+}
+
+// GetByRawStatus fetches videos by the state of their raw upload, optionally limited to one season
+func (r *GormMatchVideoRepository) GetByRawStatus(status models.RawFileStatusEnum, seasonID *uuid.UUID) ([]models.MatchVideo, error) {
+	var matchVideos []models.MatchVideo
+	query := scopeToSeason(database.DB.Model(&models.MatchVideo{}), "match_videos", seasonID)
+	err := query.
+		Where("match_videos.raw_status = ?", status).
+		Order("match_videos.raw_status_at ASC").
+		Find(&matchVideos).Error
+	return matchVideos, err
+}
+
+// UpdateRawStatus records a new raw upload state, unless the raw key changed since it was read
+func (r *GormMatchVideoRepository) UpdateRawStatus(id uuid.UUID, rawKey string, status models.RawFileStatusEnum, reason string) error {
+	now := time.Now()
+	return database.DB.Model(&models.MatchVideo{}).
+		Where("id = ? AND raw_key = ?", id, rawKey).
+		Updates(map[string]interface{}{
+			"raw_status":        status,
+			"raw_status_reason": reason,
+			"raw_status_at":     now,
+		}).Error
+}
+
+// SumRawSizeByStatus adds up the probed size of raw uploads per raw status
+func (r *GormMatchVideoRepository) SumRawSizeByStatus(seasonID *uuid.UUID) ([]StorageUsage, error) {
+	var usage []StorageUsage
+	query := scopeToSeason(database.DB.Model(&models.MatchVideo{}), "match_videos", seasonID)
+	err := query.
+		Select("'raw_' || match_videos.raw_status AS category, COUNT(*) AS objects, COALESCE(SUM(match_videos.video_file_size), 0) AS bytes").
+		Where("match_videos.raw_key <> ''").
+		Group("match_videos.raw_status").
+		Scan(&usage).Error
+	return usage, err
+}
+
+// UpdateRallyStatus records the rally detection state, unless the video was replaced since
+func (r *GormMatchVideoRepository) UpdateRallyStatus(id uuid.UUID, outputKey, status, errMsg string) error {
+	return database.DB.Model(&models.MatchVideo{}).
+		Where("id = ? AND output_key = ?", id, outputKey).
+		Updates(map[string]interface{}{
+			"rally_status": status,
+			"rally_error":  errMsg,
+		}).Error
+}
+
+// UpdateCondensedStatus records the condensed video state, unless the video was replaced since
+func (r *GormMatchVideoRepository) UpdateCondensedStatus(id uuid.UUID, outputKey, status, errMsg string) error {
+	return database.DB.Model(&models.MatchVideo{}).
+		Where("id = ? AND output_key = ?", id, outputKey).
+		Updates(map[string]interface{}{
+			"condensed_status": status,
+			"condensed_error":  errMsg,
+		}).Error
+}
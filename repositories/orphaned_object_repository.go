@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// OrphanedObjectRepository defines the interface for tracking unreferenced stored objects
+type OrphanedObjectRepository interface {
+	GetAll() ([]models.OrphanedObject, error)
+	Track(objects []models.OrphanedObject) error
+	DeleteByKeys(keys []string) error
+}
+
+// GormOrphanedObjectRepository implements OrphanedObjectRepository using GORM
+type GormOrphanedObjectRepository struct{}
+
+// NewOrphanedObjectRepository creates a new instance of OrphanedObjectRepository
+func NewOrphanedObjectRepository() OrphanedObjectRepository {
+	return &GormOrphanedObjectRepository{}
+}
+
+// GetAll fetches every tracked orphan
+func (r *GormOrphanedObjectRepository) GetAll() ([]models.OrphanedObject, error) {
+	var objects []models.OrphanedObject
+	err := database.DB.Order("key ASC").Find(&objects).Error
+	return objects, err
+}
+
+// Track records orphans, keeping the first-seen time of those already tracked
+func (r *GormOrphanedObjectRepository) Track(objects []models.OrphanedObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "last_modified", "updated_at"}),
+	}).CreateInBatches(&objects, 500).Error
+}
+
+// DeleteByKeys stops tracking the given objects, in chunks to stay below the parameter limit
+func (r *GormOrphanedObjectRepository) DeleteByKeys(keys []string) error {
+	for start := 0; start < len(keys); start += 1000 {
+		end := min(start+1000, len(keys))
+		if err := database.DB.Where("key IN ?", keys[start:end]).Delete(&models.OrphanedObject{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"time"
+
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlaylistRepository defines the interface for playlist data operations, including items, shares and views
+type PlaylistRepository interface {
+	Create(playlist *models.Playlist) error
+	GetByID(id uuid.UUID) (*models.Playlist, error)
+	GetAccessible(userID uuid.UUID, isPlayer bool) ([]models.Playlist, error)
+	Update(playlist *models.Playlist) error
+	Delete(id uuid.UUID) error
+
+	CreateItem(item *models.PlaylistItem) error
+	GetItemByID(id uuid.UUID) (*models.PlaylistItem, error)
+	UpdateItem(item *models.PlaylistItem) error
+	UpdateItemPositions(playlistID uuid.UUID, orderedIDs []uuid.UUID) error
+	DeleteItem(id uuid.UUID) error
+
+	ReplaceShares(playlistID uuid.UUID, userIDs []uuid.UUID) error
+	IsSharedWith(playlistID, userID uuid.UUID) (bool, error)
+
+	RecordView(view *models.PlaylistView) error
+	GetViews(playlistID uuid.UUID) ([]models.PlaylistView, error)
+}
+
+// GormPlaylistRepository implements PlaylistRepository using GORM
+type GormPlaylistRepository struct{}
+
+// NewPlaylistRepository creates a new instance of PlaylistRepository
+func NewPlaylistRepository() PlaylistRepository {
+	return &GormPlaylistRepository{}
+}
+
+// Create inserts a new playlist together with its items
+func (r *GormPlaylistRepository) Create(playlist *models.Playlist) error {
+	return database.DB.Create(playlist).Error
+}
+
+// GetByID fetches a playlist with its items in order and its shares
+func (r *GormPlaylistRepository) GetByID(id uuid.UUID) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := database.DB.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Shares").
+		First(&playlist, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+// GetAccessible fetches playlists the user owns or that were shared with them
+func (r *GormPlaylistRepository) GetAccessible(userID uuid.UUID, isPlayer bool) ([]models.Playlist, error) {
+	var playlists []models.Playlist
+
+	shared := database.DB.Model(&models.PlaylistShare{}).Select("playlist_id").Where("user_id = ?", userID)
+	query := database.DB.Where("owner_id = ?", userID).Or("id IN (?)", shared)
+	if isPlayer {
+		query = query.Or("shared_with_players = ?", true)
+	}
+
+	err := database.DB.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where(query).
+		Order("updated_at DESC").
+		Find(&playlists).Error
+	return playlists, err
+}
+
+// Update saves changes to the playlist itself
+func (r *GormPlaylistRepository) Update(playlist *models.Playlist) error {
+	return database.DB.Omit(clause.Associations).Save(playlist).Error
+}
+
+// Delete soft deletes a playlist by ID
+func (r *GormPlaylistRepository) Delete(id uuid.UUID) error {
+	return database.DB.Delete(&models.Playlist{}, "id = ?", id).Error
+}
+
+// CreateItem inserts a new playlist item
+func (r *GormPlaylistRepository) CreateItem(item *models.PlaylistItem) error {
+	return database.DB.Create(item).Error
+}
+
+// GetItemByID fetches a playlist item by ID
+func (r *GormPlaylistRepository) GetItemByID(id uuid.UUID) (*models.PlaylistItem, error) {
+	var item models.PlaylistItem
+	if err := database.DB.First(&item, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateItem saves changes to a playlist item
+func (r *GormPlaylistRepository) UpdateItem(item *models.PlaylistItem) error {
+	return database.DB.Save(item).Error
+}
+
+// UpdateItemPositions rewrites item positions to follow the given order
+func (r *GormPlaylistRepository) UpdateItemPositions(playlistID uuid.UUID, orderedIDs []uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for position, id := range orderedIDs {
+			err := tx.Model(&models.PlaylistItem{}).
+				Where("id = ? AND playlist_id = ?", id, playlistID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteItem removes a playlist item and its view records
+func (r *GormPlaylistRepository) DeleteItem(id uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.PlaylistView{}, "playlist_item_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.PlaylistItem{}, "id = ?", id).Error
+	})
+}
+
+// ReplaceShares sets the exact list of users a playlist is shared with
+func (r *GormPlaylistRepository) ReplaceShares(playlistID uuid.UUID, userIDs []uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.PlaylistShare{}, "playlist_id = ?", playlistID).Error; err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			share := models.PlaylistShare{PlaylistID: playlistID, UserID: userID}
+			if err := tx.Create(&share).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IsSharedWith reports whether a playlist was shared with the user explicitly
+func (r *GormPlaylistRepository) IsSharedWith(playlistID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.PlaylistShare{}).
+		Where("playlist_id = ? AND user_id = ?", playlistID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RecordView upserts the viewing progress of a user on a playlist item
+func (r *GormPlaylistRepository) RecordView(view *models.PlaylistView) error {
+	view.LastViewedAt = time.Now()
+	view.ViewCount = 1
+
+	return database.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "playlist_item_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"watched_ms":     gorm.Expr("GREATEST(playlist_views.watched_ms, EXCLUDED.watched_ms)"),
+			"completed":      gorm.Expr("playlist_views.completed OR EXCLUDED.completed"),
+			"view_count":     gorm.Expr("playlist_views.view_count + 1"),
+			"last_viewed_at": view.LastViewedAt,
+			"updated_at":     view.LastViewedAt,
+		}),
+	}).Create(view).Error
+}
+
+// GetViews fetches all view records of a playlist
+func (r *GormPlaylistRepository) GetViews(playlistID uuid.UUID) ([]models.PlaylistView, error) {
+	var views []models.PlaylistView
+	err := database.DB.
+		Where("playlist_id = ?", playlistID).
+		Order("last_viewed_at DESC").
+		Find(&views).Error
+	return views, err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RallySegmentRepository defines the interface for rally segment data operations
+type RallySegmentRepository interface {
+	Create(segment *models.RallySegment) error
+	GetByID(id uuid.UUID) (*models.RallySegment, error)
+	GetByMatchVideoID(matchVideoID uuid.UUID) ([]models.RallySegment, error)
+	Update(segment *models.RallySegment) error
+	Delete(id uuid.UUID) error
+	ReplaceDetected(matchVideoID uuid.UUID, detected []models.RallySegment) error
+}
+
+// GormRallySegmentRepository implements RallySegmentRepository using GORM
+type GormRallySegmentRepository struct{}
+
+// NewRallySegmentRepository creates a new instance of RallySegmentRepository
+func NewRallySegmentRepository() RallySegmentRepository {
+	return &GormRallySegmentRepository{}
+}
+
+// Create inserts a new rally segment
+func (r *GormRallySegmentRepository) Create(segment *models.RallySegment) error {
+	return database.DB.Create(segment).Error
+}
+
+// GetByID fetches a rally segment by ID
+func (r *GormRallySegmentRepository) GetByID(id uuid.UUID) (*models.RallySegment, error) {
+	var segment models.RallySegment
+	if err := database.DB.First(&segment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &segment, nil
+}
+
+// GetByMatchVideoID fetches the rallies of a match video in playing order
+func (r *GormRallySegmentRepository) GetByMatchVideoID(matchVideoID uuid.UUID) ([]models.RallySegment, error) {
+	var segments []models.RallySegment
+	err := database.DB.
+		Where("match_video_id = ?", matchVideoID).
+		Order("start_ms ASC").
+		Find(&segments).Error
+	return segments, err
+}
+
+// Update saves changes to a rally segment
+func (r *GormRallySegmentRepository) Update(segment *models.RallySegment) error {
+	return database.DB.Save(segment).Error
+}
+
+// Delete removes a rally segment
+func (r *GormRallySegmentRepository) Delete(id uuid.UUID) error {
+	return database.DB.Delete(&models.RallySegment{}, "id = ?", id).Error
+}
+
+// ReplaceDetected swaps the unreviewed segments of a match video for a new detection result.
+// Reviewed segments stay, and detected segments overlapping them are dropped.
+func (r *GormRallySegmentRepository) ReplaceDetected(matchVideoID uuid.UUID, detected []models.RallySegment) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.RallySegment{}, "match_video_id = ? AND reviewed = ?", matchVideoID, false).Error; err != nil {
+			return err
+		}
+
+		var reviewed []models.RallySegment
+		if err := tx.Where("match_video_id = ?", matchVideoID).Find(&reviewed).Error; err != nil {
+			return err
+		}
+
+		keep := make([]models.RallySegment, 0, len(detected))
+		for _, segment := range detected {
+			overlaps := false
+			for _, existing := range reviewed {
+				if segment.StartMs < existing.EndMs && existing.StartMs < segment.EndMs {
+					overlaps = true
+					break
+				}
+			}
+			if !overlaps {
+				keep = append(keep, segment)
+			}
+		}
+
+		if len(keep) == 0 {
+			return nil
+		}
+		return tx.Create(&keep).Error
+	})
+}
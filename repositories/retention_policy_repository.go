@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// RetentionPolicyRepository defines the interface for per-season retention policy data operations
+type RetentionPolicyRepository interface {
+	GetBySeasonID(seasonID uuid.UUID) (*models.RetentionPolicy, error)
+	Upsert(policy *models.RetentionPolicy) error
+}
+
+// GormRetentionPolicyRepository implements RetentionPolicyRepository using GORM
+type GormRetentionPolicyRepository struct{}
+
+// NewRetentionPolicyRepository creates a new instance of RetentionPolicyRepository
+func NewRetentionPolicyRepository() RetentionPolicyRepository {
+	return &GormRetentionPolicyRepository{}
+}
+
+// GetBySeasonID fetches the policy stored for a season
+func (r *GormRetentionPolicyRepository) GetBySeasonID(seasonID uuid.UUID) (*models.RetentionPolicy, error) {
+	var policy models.RetentionPolicy
+	if err := database.DB.Where("season_id = ?", seasonID).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Upsert creates the policy of a season or replaces the existing one
+func (r *GormRetentionPolicyRepository) Upsert(policy *models.RetentionPolicy) error {
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "season_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"raw_action", "raw_grace_days", "archive_storage_class", "failed_raw_flag_days", "updated_at"}),
+	}).Create(policy).Error
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionRepository defines the interface for session data operations
+type SessionRepository interface {
+	Create(session *models.Session, maxPerUser int) error
+	FindByID(id uuid.UUID) (*models.Session, error)
+	FindByRefreshToken(tokenHash string) (*models.Session, error)
+	FindRotatedToken(tokenHash string) (*models.RotatedRefreshToken, error)
+	Rotate(session *models.Session, previousHash string) (bool, error)
+	ListActive(userID uuid.UUID) ([]models.Session, error)
+	Delete(userID, sessionID uuid.UUID) (bool, error)
+	DeleteOthers(userID, keepID uuid.UUID) (int64, error)
+	DeleteAll(userID uuid.UUID) (int64, error)
+	ImportLegacyRefreshTokens() (int64, error)
+	HashStoredRefreshTokens() (int64, error)
+}
+
+// GormSessionRepository implements SessionRepository using GORM
+type GormSessionRepository struct{}
+
+// NewSessionRepository creates a new instance of SessionRepository
+func NewSessionRepository() SessionRepository {
+	return &GormSessionRepository{}
+}
+
+// Create stores a new session. Expired sessions and rotated tokens of the user are
+// dropped, and so are the least recently used sessions beyond maxPerUser with their
+// rotated tokens.
+func (r *GormSessionRepository) Create(session *models.Session, maxPerUser int) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Where("user_id = ? AND expires_at < ?", session.UserID, now).
+			Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND expires_at < ?", session.UserID, now).
+			Delete(&models.RotatedRefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		keep := tx.Model(&models.Session{}).Select("id").
+			Where("user_id = ?", session.UserID).
+			Order("last_used_at DESC").
+			Limit(maxPerUser - 1)
+		if err := tx.Where("user_id = ? AND session_id NOT IN (?)", session.UserID, keep).
+			Delete(&models.RotatedRefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND id NOT IN (?)", session.UserID, keep).
+			Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(session).Error
+	})
+}
+
+// FindByID finds a session by ID
+func (r *GormSessionRepository) FindByID(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := database.DB.First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByRefreshToken finds the session whose current refresh token has the given hash
+func (r *GormSessionRepository) FindByRefreshToken(tokenHash string) (*models.Session, error) {
+	var session models.Session
+	if err := database.DB.Where("refresh_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindRotatedToken finds a refresh token that was already exchanged by its hash
+func (r *GormSessionRepository) FindRotatedToken(tokenHash string) (*models.RotatedRefreshToken, error) {
+	var rotated models.RotatedRefreshToken
+	if err := database.DB.Where("token_hash = ?", tokenHash).First(&rotated).Error; err != nil {
+		return nil, err
+	}
+	return &rotated, nil
+}
+
+// Rotate saves the new refresh token and usage of a session and remembers the previous
+// token as rotated until the session would expire. It reports false if the previous token
+// was already replaced, so a token can be used for one refresh only.
+func (r *GormSessionRepository) Rotate(session *models.Session, previousHash string) (bool, error) {
+	rotated := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("id = ? AND refresh_token_hash = ?", session.ID, previousHash).
+			Updates(map[string]interface{}{
+				"refresh_token_hash": session.RefreshTokenHash,
+				"user_agent":         session.UserAgent,
+				"ip_address":         session.IPAddress,
+				"expires_at":         session.ExpiresAt,
+				"last_used_at":       session.LastUsedAt,
+			})
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		rotated = true
+
+		if err := tx.Where("session_id = ? AND expires_at < ?", session.ID, session.LastUsedAt).
+			Delete(&models.RotatedRefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.RotatedRefreshToken{
+			TokenHash: previousHash,
+			SessionID: session.ID,
+			UserID:    session.UserID,
+			RotatedAt: session.LastUsedAt,
+			ExpiresAt: session.ExpiresAt,
+		}).Error
+	})
+	return rotated && err == nil, err
+}
+
+// ListActive lists the unexpired sessions of a user, most recently used first
+func (r *GormSessionRepository) ListActive(userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	err := database.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Delete removes a session of a user with its rotated tokens and reports whether the
+// session existed
+func (r *GormSessionRepository) Delete(userID, sessionID uuid.UUID) (bool, error) {
+	deleted, err := deleteSessions(database.DB.Where("user_id = ?", userID).Where("id = ?", sessionID),
+		database.DB.Where("user_id = ?", userID).Where("session_id = ?", sessionID))
+	return deleted == 1, err
+}
+
+// DeleteOthers removes all sessions of a user except one, with their rotated tokens
+func (r *GormSessionRepository) DeleteOthers(userID, keepID uuid.UUID) (int64, error) {
+	return deleteSessions(database.DB.Where("user_id = ?", userID).Where("id <> ?", keepID),
+		database.DB.Where("user_id = ?", userID).Where("session_id <> ?", keepID))
+}
+
+// DeleteAll removes all sessions of a user with their rotated tokens
+func (r *GormSessionRepository) DeleteAll(userID uuid.UUID) (int64, error) {
+	return deleteSessions(database.DB.Where("user_id = ?", userID), database.DB.Where("user_id = ?", userID))
+}
+
+// deleteSessions removes the sessions and rotated tokens matched by the given conditions in
+// one transaction, so a token of a revoked session is never mistaken for a reused one. It
+// returns the number of sessions removed.
+func deleteSessions(sessions, rotatedTokens *gorm.DB) (int64, error) {
+	var deleted int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(rotatedTokens).Delete(&models.RotatedRefreshToken{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where(sessions).Delete(&models.Session{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// ImportLegacyRefreshTokens turns the single refresh token users had before sessions
+// existed into a session each and drops the old columns. It does nothing once they are gone.
+func (r *GormSessionRepository) ImportLegacyRefreshTokens() (int64, error) {
+	if !database.DB.Migrator().HasColumn("users", "refresh_token") {
+		return 0, nil
+	}
+
+	var imported int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			INSERT INTO sessions (user_id, refresh_token_hash, user_agent, ip_address, expires_at, last_used_at, created_at)
+			SELECT id, ENCODE(SHA256(CONVERT_TO(refresh_token, 'UTF8')), 'hex'), '', '', refresh_token_expiry, updated_at, updated_at
+			FROM users
+			WHERE refresh_token IS NOT NULL AND refresh_token <> ''
+				AND refresh_token_expiry > NOW() AND deleted_at IS NULL
+			ON CONFLICT DO NOTHING`)
+		if result.Error != nil {
+			return result.Error
+		}
+		imported = result.RowsAffected
+
+		if err := tx.Migrator().DropColumn("users", "refresh_token"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn("users", "refresh_token_expiry")
+	})
+	return imported, err
+}
+
+// HashStoredRefreshTokens replaces the plaintext refresh_token column of sessions by
+// refresh_token_hash. It runs before auto-migration and does nothing once the column is gone.
+func (r *GormSessionRepository) HashStoredRefreshTokens() (int64, error) {
+	if !database.DB.Migrator().HasColumn("sessions", "refresh_token") {
+		return 0, nil
+	}
+
+	var hashed int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("UPDATE sessions SET refresh_token = ENCODE(SHA256(CONVERT_TO(refresh_token, 'UTF8')), 'hex')")
+		if result.Error != nil {
+			return result.Error
+		}
+		hashed = result.RowsAffected
+
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_sessions_refresh_token").Error; err != nil {
+			return err
+		}
+		return tx.Migrator().RenameColumn("sessions", "refresh_token", "refresh_token_hash")
+	})
+	return hashed, err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository defines the interface for TOTP secrets and recovery codes
+type TwoFactorRepository interface {
+	SetPendingSecret(userID uuid.UUID, encryptedSecret string) error
+	Enable(userID uuid.UUID, step int64, codeHashes []string) error
+	Disable(userID uuid.UUID) error
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	AcceptStep(userID uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uuid.UUID) (int64, error)
+}
+
+// GormTwoFactorRepository implements TwoFactorRepository using GORM
+type GormTwoFactorRepository struct{}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepository
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &GormTwoFactorRepository{}
+}
+
+// SetPendingSecret stores a new secret that becomes active once it is confirmed
+func (r *GormTwoFactorRepository) SetPendingSecret(userID uuid.UUID, encryptedSecret string) error {
+	return database.DB.Model(&models.User{}).
+		Where("id = ? AND two_factor_enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"totp_secret":    encryptedSecret,
+			"totp_last_step": 0,
+		}).Error
+}
+
+// Enable activates the pending secret and stores the first recovery codes
+func (r *GormTwoFactorRepository) Enable(userID uuid.UUID, step int64, codeHashes []string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled_at": time.Now(),
+			"totp_last_step":        step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Disable removes the secret and all recovery codes
+func (r *GormTwoFactorRepository) Disable(userID uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":           nil,
+			"two_factor_enabled_at": nil,
+			"totp_last_step":        0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes invalidates all recovery codes of a user and stores new ones
+func (r *GormTwoFactorRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]models.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
+
+// AcceptStep records a used TOTP step. It reports false when that step or a later one was
+// already used, so a code cannot be replayed even by concurrent requests.
+func (r *GormTwoFactorRepository) AcceptStep(userID uuid.UUID, step int64) (bool, error) {
+	result := database.DB.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseRecoveryCode marks an unused recovery code as used and reports whether there was one
+func (r *GormTwoFactorRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := database.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountRecoveryCodes counts the unused recovery codes of a user
+func (r *GormTwoFactorRepository) CountRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -14,6 +14,9 @@ type UserRepository interface {
 	Update(user *models.User) error
 	Delete(user *models.User) error
 	GetWithPagination(limit, offset int) ([]models.User, int64, error)
+	GetTokenVersion(id uuid.UUID) (int, error)
+	GetAuthState(id uuid.UUID) (*models.User, error)
+	IncrementTokenVersion(id uuid.UUID) error
 }
 
 // GormUserRepository implements UserRepository using GORM
@@ -63,3 +66,30 @@ func (r *GormUserRepository) GetWithPagination(limit, offset int) ([]models.User
 
 	return users, total, nil
 }
+
+// GetTokenVersion retrieves the token version of a user who has not been deleted
+func (r *GormUserRepository) GetTokenVersion(id uuid.UUID) (int, error) {
+	var user models.User
+	if err := database.DB.Select("token_version").First(&user, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+// GetAuthState retrieves only the fields checked on every authenticated request: the token
+// version and what account setup requires. Deleted users are not found.
+func (r *GormUserRepository) GetAuthState(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := database.DB.
+		Select("id", "role", "token_version", "email_verified_at", "two_factor_enabled_at").
+		First(&user, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// IncrementTokenVersion bumps a user's token version, invalidating their access tokens
+func (r *GormUserRepository) IncrementTokenVersion(id uuid.UUID) error {
+	return database.DB.Exec("UPDATE users SET token_version = token_version + 1 WHERE id = ?", id).Error
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"encoding/json"
+
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnnotationQuery narrows down the annotations of a match video that a viewer may see
+type AnnotationQuery struct {
+	MatchVideoID  uuid.UUID
+	ViewerID      uuid.UUID
+	ViewerIsStaff bool // coaching staff also sees coaching_staff annotations
+	AuthorID      *uuid.UUID
+	Visibility    models.AnnotationVisibilityEnum
+	Tags          []string // matches annotations carrying any of the tags
+	FromMs, ToMs  *int64   // matches annotations overlapping the range
+}
+
+// VideoAnnotationRepository defines the interface for video annotation data operations
+type VideoAnnotationRepository interface {
+	Create(annotation *models.VideoAnnotation) error
+	GetByID(id uuid.UUID) (*models.VideoAnnotation, error)
+	Find(query AnnotationQuery) ([]models.VideoAnnotation, error)
+	FindAdjacent(query AnnotationQuery, atMs int64, forward bool) (*models.VideoAnnotation, error)
+	Update(annotation *models.VideoAnnotation) error
+	Delete(id uuid.UUID) error
+}
+
+// GormVideoAnnotationRepository implements VideoAnnotationRepository using GORM
+type GormVideoAnnotationRepository struct{}
+
+// NewVideoAnnotationRepository creates a new instance of VideoAnnotationRepository
+func NewVideoAnnotationRepository() VideoAnnotationRepository {
+	return &GormVideoAnnotationRepository{}
+}
+
+// Create inserts a new annotation
+func (r *GormVideoAnnotationRepository) Create(annotation *models.VideoAnnotation) error {
+	return database.DB.Create(annotation).Error
+}
+
+// GetByID fetches an annotation by ID
+func (r *GormVideoAnnotationRepository) GetByID(id uuid.UUID) (*models.VideoAnnotation, error) {
+	var annotation models.VideoAnnotation
+	if err := database.DB.First(&annotation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// Find fetches the annotations matching the query in timecode order
+func (r *GormVideoAnnotationRepository) Find(query AnnotationQuery) ([]models.VideoAnnotation, error) {
+	var annotations []models.VideoAnnotation
+	err := r.scope(query).
+		Order("start_ms ASC, created_at ASC").
+		Find(&annotations).Error
+	return annotations, err
+}
+
+// FindAdjacent fetches the first matching annotation starting after (forward) or before atMs
+func (r *GormVideoAnnotationRepository) FindAdjacent(query AnnotationQuery, atMs int64, forward bool) (*models.VideoAnnotation, error) {
+	var annotation models.VideoAnnotation
+
+	db := r.scope(query)
+	if forward {
+		db = db.Where("start_ms > ?", atMs).Order("start_ms ASC, created_at ASC")
+	} else {
+		db = db.Where("start_ms < ?", atMs).Order("start_ms DESC, created_at DESC")
+	}
+
+	if err := db.First(&annotation).Error; err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// Update saves changes to an existing annotation
+func (r *GormVideoAnnotationRepository) Update(annotation *models.VideoAnnotation) error {
+	return database.DB.Save(annotation).Error
+}
+
+// Delete soft deletes an annotation by ID
+func (r *GormVideoAnnotationRepository) Delete(id uuid.UUID) error {
+	return database.DB.Delete(&models.VideoAnnotation{}, "id = ?", id).Error
+}
+
+// scope applies the visibility rules and filters of a query
+func (r *GormVideoAnnotationRepository) scope(query AnnotationQuery) *gorm.DB {
+	db := database.DB.Model(&models.VideoAnnotation{}).Where("match_video_id = ?", query.MatchVideoID)
+
+	visible := database.DB.Where("author_id = ?", query.ViewerID).
+		Or("visibility = ?", models.AnnotationTeam)
+	if query.ViewerIsStaff {
+		visible = visible.Or("visibility = ?", models.AnnotationCoachingStaff)
+	}
+	db = db.Where(visible)
+
+	if query.AuthorID != nil {
+		db = db.Where("author_id = ?", *query.AuthorID)
+	}
+	if query.Visibility != "" {
+		db = db.Where("visibility = ?", query.Visibility)
+	}
+	if query.FromMs != nil {
+		db = db.Where("end_ms >= ?", *query.FromMs)
+	}
+	if query.ToMs != nil {
+		db = db.Where("start_ms <= ?", *query.ToMs)
+	}
+
+	if len(query.Tags) > 0 {
+		tagged := database.DB
+		for i, tag := range query.Tags {
+			containment, _ := json.Marshal([]string{tag})
+			if i == 0 {
+				tagged = tagged.Where("tags @> ?", string(containment))
+			} else {
+				tagged = tagged.Or("tags @> ?", string(containment))
+			}
+		}
+		db = db.Where(tagged)
+	}
+
+	return db
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VideoArtifactRepository defines the interface for processed video artifact data operations
+type VideoArtifactRepository interface {
+	ReplaceForMatchVideo(matchVideoID uuid.UUID, artifacts []models.VideoArtifact) error
+	ReplaceKindsForMatchVideo(matchVideoID uuid.UUID, kinds []models.VideoArtifactKindEnum, artifacts []models.VideoArtifact) error
+	GetByMatchVideoIDs(matchVideoIDs []uuid.UUID) ([]models.VideoArtifact, error)
+	DeleteByMatchVideoID(matchVideoID uuid.UUID) error
+	SumSizeByKind(seasonID *uuid.UUID) ([]StorageUsage, error)
+}
+
+// StorageUsage is the number of objects and bytes stored for one category
+type StorageUsage struct {
+	Category string
+	Objects  int64
+	Bytes    int64
+}
+
+// GormVideoArtifactRepository implements VideoArtifactRepository using GORM
+type GormVideoArtifactRepository struct{}
+
+// NewVideoArtifactRepository creates a new instance of VideoArtifactRepository
+func NewVideoArtifactRepository() VideoArtifactRepository {
+	return &GormVideoArtifactRepository{}
+}
+
+// ReplaceForMatchVideo swaps the recorded artifacts of a match video for a new set in one transaction
+func (r *GormVideoArtifactRepository) ReplaceForMatchVideo(matchVideoID uuid.UUID, artifacts []models.VideoArtifact) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.VideoArtifact{}, "match_video_id = ?", matchVideoID).Error; err != nil {
+			return err
+		}
+		if len(artifacts) == 0 {
+			return nil
+		}
+		return tx.Create(&artifacts).Error
+	})
+}
+
+// ReplaceKindsForMatchVideo swaps only the artifacts of the given kinds, e.g. for a derived rendition
+func (r *GormVideoArtifactRepository) ReplaceKindsForMatchVideo(matchVideoID uuid.UUID, kinds []models.VideoArtifactKindEnum, artifacts []models.VideoArtifact) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.VideoArtifact{}, "match_video_id = ? AND kind IN ?", matchVideoID, kinds).Error; err != nil {
+			return err
+		}
+		if len(artifacts) == 0 {
+			return nil
+		}
+		return tx.Create(&artifacts).Error
+	})
+}
+
+// GetByMatchVideoIDs fetches the artifacts of several match videos at once
+func (r *GormVideoArtifactRepository) GetByMatchVideoIDs(matchVideoIDs []uuid.UUID) ([]models.VideoArtifact, error) {
+	var artifacts []models.VideoArtifact
+	if len(matchVideoIDs) == 0 {
+		return artifacts, nil
+	}
+
+	err := database.DB.
+		Where("match_video_id IN ?", matchVideoIDs).
+		Order("kind ASC, label ASC").
+		Find(&artifacts).Error
+	return artifacts, err
+}
+
+// DeleteByMatchVideoID removes every artifact record of a match video
+func (r *GormVideoArtifactRepository) DeleteByMatchVideoID(matchVideoID uuid.UUID) error {
+	return database.DB.Delete(&models.VideoArtifact{}, "match_video_id = ?", matchVideoID).Error
+}
+
+// SumSizeByKind adds up the size of ready artifacts per kind, optionally limited to one season
+func (r *GormVideoArtifactRepository) SumSizeByKind(seasonID *uuid.UUID) ([]StorageUsage, error) {
+	var usage []StorageUsage
+	query := scopeToSeason(database.DB.Model(&models.VideoArtifact{}), "video_artifacts", seasonID)
+	err := query.
+		Select("video_artifacts.kind AS category, COUNT(*) AS objects, COALESCE(SUM(video_artifacts.size), 0) AS bytes").
+		Where("video_artifacts.status = ?", models.ArtifactReady).
+		Group("video_artifacts.kind").
+		Scan(&usage).Error
+	return usage, err
+}
+
+// scopeToSeason limits a query on a table with a match_id column to the matches of one season
+func scopeToSeason(query *gorm.DB, table string, seasonID *uuid.UUID) *gorm.DB {
+	if seasonID == nil {
+		return query
+	}
+	return query.
+		Joins("JOIN matches ON matches.id = "+table+".match_id AND matches.deleted_at IS NULL").
+		Where("matches.season_id = ?", *seasonID)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"go-gin-starter/database"
+	"go-gin-starter/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoUploadRepository defines the interface for presigned video upload data operations
+type VideoUploadRepository interface {
+	Create(upload *models.VideoUpload) error
+	GetByID(id uuid.UUID) (*models.VideoUpload, error)
+	Update(upload *models.VideoUpload) error
+	GetExpiredPending(before time.Time) ([]models.VideoUpload, error)
+}
+
+// GormVideoUploadRepository implements VideoUploadRepository using GORM
+type GormVideoUploadRepository struct{}
+
+// NewVideoUploadRepository creates a new instance of VideoUploadRepository
+func NewVideoUploadRepository() VideoUploadRepository {
+	return &GormVideoUploadRepository{}
+}
+
+// Create inserts a new video upload record
+func (r *GormVideoUploadRepository) Create(upload *models.VideoUpload) error {
+	return database.DB.Create(upload).Error
+}
+
+// GetByID fetches a video upload by ID
+func (r *GormVideoUploadRepository) GetByID(id uuid.UUID) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	if err := database.DB.First(&upload, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// Update saves changes to an existing video upload
+func (r *GormVideoUploadRepository) Update(upload *models.VideoUpload) error {
+	return database.DB.Save(upload).Error
+}
+
+// GetExpiredPending fetches pending uploads whose presigned URLs expired before the given time
+func (r *GormVideoUploadRepository) GetExpiredPending(before time.Time) ([]models.VideoUpload, error) {
+	var uploads []models.VideoUpload
+	err := database.DB.
+		Where("status = ? AND expires_at < ?", models.VideoUploadPending, before).
+		Find(&uploads).Error
+	return uploads, err
+}
@@ -8,9 +8,8 @@ import (
 )
 
 // SetupRoutes registers all routes on the given router group
-func SetupRoutes(router gin.IRouter) {
-	// Create the container and get the controllers
-	container := di.NewContainer()
+func SetupRoutes(router gin.IRouter, container *di.Container) {
+	// Get the controllers from the container
 	userCtrl := container.UserController
 	adminUserCtrl := container.AdminUserController
 	adminPermissionsCtrl := container.AdminUserPermissionsController
@@ -21,6 +20,15 @@ func SetupRoutes(router gin.IRouter) {
 	matchCtrl := container.MatchController
 	seasonCtrl := container.SeasonController
 	healthCtrl := container.HealthController
+	videoUploadCtrl := container.VideoUploadController
+	matchVideoCtrl := container.MatchVideoController
+	annotationCtrl := container.VideoAnnotationController
+	rallyCtrl := container.RallyController
+	playlistCtrl := container.PlaylistController
+	retentionCtrl := container.RetentionController
+	twoFactorCtrl := container.TwoFactorController
+	oidcCtrl := container.OIDCController
+	sessionCtrl := container.SessionController
 
 	// Health check routes
 	router.GET("/health", healthCtrl.HealthCheck)
@@ -31,9 +39,15 @@ func SetupRoutes(router gin.IRouter) {
 	// Public Routes (No authentication)
 	router.POST("/register", authCtrl.Register)
 	router.POST("/login", authCtrl.Login)
+	router.POST("/login/2fa", authCtrl.LoginTwoFactor)
+	router.POST("/login/unlock", authCtrl.UnlockAccount)
+	router.GET("/oidc/providers", oidcCtrl.GetProviders)
+	router.GET("/oidc/:provider/authorize", oidcCtrl.Authorize)
+	router.POST("/oidc/callback", oidcCtrl.Callback)
 	router.POST("/refresh-token", authCtrl.RefreshToken)
 	router.POST("/password/forgot", authCtrl.ForgotPassword)
 	router.POST("/password/reset", authCtrl.ResetPassword)
+	router.POST("/email/verify", authCtrl.VerifyEmail)
 	router.POST("/waitlist/submit", waitlistCtrl.SubmitWaitlist)
 
 	// Authenticated Routes (JWT required)
@@ -46,26 +60,80 @@ func SetupRoutes(router gin.IRouter) {
 	auth.PUT("/profile", userCtrl.UpdateProfile)
 	auth.DELETE("/profile", userCtrl.DeleteProfile)
 	auth.PUT("/profile/change-password", userCtrl.ChangePassword)
+	auth.POST("/email/verify/resend", authCtrl.ResendVerification)
+	auth.POST("/logout", sessionCtrl.Logout)
+
+	// Sessions, one per device the user is logged in on
+	auth.GET("/profile/sessions", sessionCtrl.GetSessions)
+	auth.DELETE("/profile/sessions", sessionCtrl.RevokeOtherSessions)
+	auth.DELETE("/profile/sessions/:id", sessionCtrl.RevokeSession)
+
+	// Two-factor settings stay reachable so roles that require 2FA can enable it
+	auth.GET("/profile/2fa", twoFactorCtrl.GetStatus)
+	auth.POST("/profile/2fa/setup", twoFactorCtrl.Setup)
+	auth.POST("/profile/2fa/enable", twoFactorCtrl.Enable)
+	auth.POST("/profile/2fa/disable", twoFactorCtrl.Disable)
+	auth.POST("/profile/2fa/recovery-codes", twoFactorCtrl.RegenerateRecoveryCodes)
+
+	// Everything below requires a verified email address and, for roles that require it, 2FA
+	verified := auth.Group("/")
+	verified.Use(middleware.RequireAccountSetup())
 
 	// Public read-only season routes (available to all authenticated users)
-	auth.GET("/seasons", seasonCtrl.GetAllSeasons)
-	auth.GET("/seasons/:id", seasonCtrl.GetSeasonByID)
+	verified.GET("/seasons", seasonCtrl.GetAllSeasons)
+	verified.GET("/seasons/:id", seasonCtrl.GetSeasonByID)
 
 	// Public read-only team routes (available to all authenticated users)
-	auth.GET("/teams", teamCtrl.GetAllTeams)
-	auth.GET("/teams/:id", teamCtrl.GetTeamByID)
+	verified.GET("/teams", teamCtrl.GetAllTeams)
+	verified.GET("/teams/:id", teamCtrl.GetTeamByID)
 
 	// Public read-only match routes (available to all authenticated users)
-	auth.GET("/matches", matchCtrl.GetAllMatches)
-	auth.GET("/matches/:id", matchCtrl.GetMatchByID)
+	verified.GET("/matches", matchCtrl.GetAllMatches)
+	verified.GET("/matches/:id", matchCtrl.GetMatchByID)
+	verified.GET("/matches/:id/videos", matchVideoCtrl.GetMatchVideos)
+	verified.GET("/matches/:id/playback", matchVideoCtrl.GetMatchPlayback)
+
+	// Video annotations; visibility is enforced per annotation
+	verified.GET("/matches/:id/videos/:video_id/annotations", annotationCtrl.GetAnnotations)
+	verified.GET("/matches/:id/videos/:video_id/annotations/jump", annotationCtrl.JumpToAnnotation)
+	verified.POST("/matches/:id/videos/:video_id/annotations", middleware.RequirePermission("annotate_video"), annotationCtrl.CreateAnnotation)
+	verified.PATCH("/matches/:id/videos/:video_id/annotations/:annotation_id", middleware.RequirePermission("annotate_video"), annotationCtrl.UpdateAnnotation)
+	verified.DELETE("/matches/:id/videos/:video_id/annotations/:annotation_id", annotationCtrl.DeleteAnnotation)
+
+	// Rally segments; proposed by audio detection, corrected by scoutmen
+	verified.GET("/matches/:id/videos/:video_id/rallies", rallyCtrl.GetRallies)
+	verified.POST("/matches/:id/videos/:video_id/rallies/detect", middleware.RequirePermission("edit_rallies"), rallyCtrl.DetectRallies)
+	verified.POST("/matches/:id/videos/:video_id/rallies", middleware.RequirePermission("edit_rallies"), rallyCtrl.CreateRally)
+	verified.PATCH("/matches/:id/videos/:video_id/rallies/:rally_id", middleware.RequirePermission("edit_rallies"), rallyCtrl.UpdateRally)
+	verified.DELETE("/matches/:id/videos/:video_id/rallies/:rally_id", middleware.RequirePermission("edit_rallies"), rallyCtrl.DeleteRally)
+	verified.POST("/matches/:id/videos/:video_id/condensed", middleware.RequirePermission("edit_rallies"), rallyCtrl.GenerateCondensedVideo)
+
+	// Clip playlists; owners curate, shared users watch
+	verified.GET("/playlists", playlistCtrl.GetPlaylists)
+	verified.GET("/playlists/:id", playlistCtrl.GetPlaylist)
+	verified.POST("/playlists/:id/items/:item_id/views", playlistCtrl.RecordPlaylistView)
+	verified.POST("/playlists", middleware.RequirePermission("manage_playlists"), playlistCtrl.CreatePlaylist)
+	verified.PATCH("/playlists/:id", middleware.RequirePermission("manage_playlists"), playlistCtrl.UpdatePlaylist)
+	verified.DELETE("/playlists/:id", middleware.RequirePermission("manage_playlists"), playlistCtrl.DeletePlaylist)
+	verified.POST("/playlists/:id/items", middleware.RequirePermission("manage_playlists"), playlistCtrl.AddPlaylistItem)
+	verified.PUT("/playlists/:id/items/order", middleware.RequirePermission("manage_playlists"), playlistCtrl.ReorderPlaylistItems)
+	verified.PATCH("/playlists/:id/items/:item_id", middleware.RequirePermission("manage_playlists"), playlistCtrl.UpdatePlaylistItem)
+	verified.DELETE("/playlists/:id/items/:item_id", middleware.RequirePermission("manage_playlists"), playlistCtrl.RemovePlaylistItem)
+	verified.PUT("/playlists/:id/shares", middleware.RequirePermission("manage_playlists"), playlistCtrl.SharePlaylist)
+	verified.GET("/playlists/:id/views", middleware.RequirePermission("manage_playlists"), playlistCtrl.GetPlaylistViews)
 
 	// Admin permission-based routes
-	admin := auth.Group("/admin")
+	admin := verified.Group("/admin")
 	{
 		// Admin User Management
 		admin.GET("/users", middleware.RequirePermission("manage_users"), userCtrl.GetAllUsers)
 		admin.PUT("/users/:id", middleware.RequirePermission("manage_users"), adminUserCtrl.UpdateUserByAdmin)
 		admin.DELETE("/users/:id", middleware.RequirePermission("manage_users"), adminUserCtrl.DeleteUserByAdmin)
+		admin.PATCH("/users/:id/email-verification", middleware.RequirePermission("manage_users"), adminUserCtrl.UpdateEmailVerification)
+		admin.DELETE("/users/:id/2fa", middleware.RequirePermission("manage_users"), adminUserCtrl.ResetTwoFactor)
+		admin.DELETE("/users/:id/lock", middleware.RequirePermission("manage_users"), adminUserCtrl.UnlockAccount)
+		admin.GET("/users/:id/sessions", middleware.RequirePermission("manage_users"), adminUserCtrl.GetUserSessions)
+		admin.DELETE("/users/:id/sessions", middleware.RequirePermission("manage_users"), adminUserCtrl.RevokeUserSessions)
 
 		// Admin User Permissions Management
 		admin.PATCH("/users/:id/permissions", middleware.RequirePermission("manage_users"), adminPermissionsCtrl.UpdateUserPermissions)
@@ -95,6 +163,12 @@ func SetupRoutes(router gin.IRouter) {
 		admin.PUT("/seasons/:id", middleware.RequirePermission("manage_season"), seasonCtrl.UpdateSeason)
 		admin.DELETE("/seasons/:id", middleware.RequirePermission("manage_season"), seasonCtrl.DeleteSeason)
 		admin.PATCH("/seasons/:id/upload-logo", middleware.RequirePermission("manage_season"), seasonCtrl.UploadSeasonLogo)
+		admin.GET("/seasons/:id/retention-policy", middleware.RequirePermission("manage_season"), retentionCtrl.GetSeasonRetentionPolicy)
+		admin.PUT("/seasons/:id/retention-policy", middleware.RequirePermission("manage_season"), retentionCtrl.UpdateSeasonRetentionPolicy)
+
+		// Admin Storage Reporting
+		admin.GET("/storage/report", middleware.RequirePermission("manage_reports"), retentionCtrl.GetStorageReport)
+		admin.GET("/storage/orphans", middleware.RequirePermission("manage_reports"), retentionCtrl.GetOrphanReport)
 
 		// Admin Match Management
 		admin.POST("/matches", middleware.RequirePermission("manage_matches"), matchCtrl.CreateMatch)
@@ -103,12 +177,17 @@ func SetupRoutes(router gin.IRouter) {
 		admin.PUT("/matches/:id", middleware.RequirePermission("manage_matches"), matchCtrl.UpdateMatch)
 		admin.DELETE("/matches/:id", middleware.RequirePermission("manage_matches"), matchCtrl.DeleteMatch)
 		admin.PATCH("/matches/:id/upload-video", middleware.RequirePermission("upload_video"), matchCtrl.UploadMatchVideo)
+		admin.POST("/matches/:id/video-uploads", middleware.RequirePermission("upload_video"), videoUploadCtrl.InitiateVideoUpload)
+		admin.POST("/matches/:id/video-uploads/:upload_id/complete", middleware.RequirePermission("upload_video"), videoUploadCtrl.CompleteVideoUpload)
+		admin.DELETE("/matches/:id/video-uploads/:upload_id", middleware.RequirePermission("upload_video"), videoUploadCtrl.AbortVideoUpload)
+		admin.PATCH("/matches/:id/videos/:video_id", middleware.RequirePermission("upload_video"), matchVideoCtrl.UpdateMatchVideo)
+		admin.DELETE("/matches/:id/videos/:video_id", middleware.RequirePermission("upload_video"), matchVideoCtrl.DeleteMatchVideo)
 		admin.GET("/matches/:id/scout/preview", middleware.RequirePermission("upload_scout"), matchCtrl.PreviewScoutMetadata)
 		admin.PATCH("/matches/:id/upload-scout", middleware.RequirePermission("upload_scout"), matchCtrl.UploadMatchScout)
 	}
 
 	// AdminOrSelf routes
-	user := auth.Group("/users")
+	user := verified.Group("/users")
 	user.Use(middleware.AdminOrSelf())
 	{
 		user.PUT("/:id/update", userCtrl.UpdateUserProfile)
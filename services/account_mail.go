@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/mail"
+
+	"go.uber.org/zap"
+)
+
+// appLink builds a link into the web app carrying a token
+func appLink(path, token string) string {
+	return config.AppBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// sendVerificationLink emails a signed link confirming the user's current email address
+func sendVerificationLink(user *models.User) error {
+	token, err := authPkg.GenerateVerificationToken(user.ID, user.Email, constants.EmailVerificationTTL)
+	if err != nil {
+		return errors.New(constants.ErrVerificationFailed)
+	}
+
+	err = mail.Send(user.Email, user.Locale, mail.TemplateVerification, mail.Data{
+		"Username":     user.Username,
+		"Email":        user.Email,
+		"Link":         appLink("/verify-email", token),
+		"ExpiresHours": int(constants.EmailVerificationTTL.Hours()),
+	})
+	if err != nil {
+		logger.Error("Failed to queue verification email", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return errors.New(constants.ErrVerificationFailed)
+	}
+	return nil
+}
+
+// sendNotice emails a user a security notice, e.g. mail.NoticePasswordChanged, to the given
+// address. Notices are best effort: failures are logged and never fail the change itself.
+func sendNotice(user *models.User, to, notice string) {
+	err := mail.Send(to, user.Locale, mail.TemplateNotification, mail.Data{
+		"Username": user.Username,
+		"Email":    user.Email,
+		"Notice":   notice,
+	})
+	if err != nil {
+		logger.Error("Failed to queue notification email",
+			zap.String("user_id", user.ID.String()),
+			zap.String("notice", notice),
+			zap.Error(err))
+	}
+}
+
+// sendUnlockLink emails a locked out user a link that lifts the lockout
+func sendUnlockLink(user *models.User, lockedUntil time.Time) {
+	token, err := authPkg.GenerateUnlockToken(user.ID, lockedUntil)
+	if err == nil {
+		err = mail.Send(user.Email, user.Locale, mail.TemplateAccountLocked, mail.Data{
+			"Username":      user.Username,
+			"Email":         user.Email,
+			"Link":          appLink("/unlock-account", token),
+			"LockedMinutes": int(time.Until(lockedUntil).Round(time.Minute).Minutes()),
+		})
+	}
+	if err != nil {
+		logger.Error("Failed to queue account locked email", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+}
@@ -22,6 +22,12 @@ func LogAdminAction(adminID uuid.UUID, actionType string, targetUserID, targetTe
 	return repositories.CreateAdminActionLog(log)
 }
 
+// LogSecurityEvent logs an event the system detected, about a user or, with a nil userID,
+// about none in particular. It has no acting admin, so AdminID is the nil UUID.
+func LogSecurityEvent(actionType string, userID *uuid.UUID, metadata models.JSONBMap) error {
+	return LogAdminAction(uuid.Nil, actionType, userID, nil, nil, nil, metadata)
+}
+
 // GetAuditLogs returns audit logs with optional filter and pagination
 func GetAuditLogs(actionType string, offset, limit int) ([]models.AdminActionLog, error) {
 	return repositories.GetAuditLogs(actionType, offset, limit)
@@ -2,38 +2,57 @@ package services
 
 import (
 	"errors"
+	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	auditPkg "go-gin-starter/pkg/audit"
 	authPkg "go-gin-starter/pkg/auth"
 	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/mail"
 	"go-gin-starter/repositories"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // AuthService defines the interface for authentication business logic
 type AuthService interface {
-	Register(username, email, password, gender string) (*models.User, error)
-	Login(email, password string) (string, string, error)     // Returns access token, refresh token, error
-	RefreshToken(refreshToken string) (string, string, error) // Returns new access token, new refresh token, error
-	ForgotPassword(email string) (string, error)              // Returns reset token, error
+	Register(username, email, password, gender, locale string) (*models.User, error)
+	Login(email, password string, client ClientInfo) (*dto.LoginResult, error)
+	CompleteLogin(user *models.User, client ClientInfo) (*dto.LoginResult, error)
+	CompleteTwoFactorLogin(challengeToken, code string, client ClientInfo) (*dto.LoginResult, error)
+	RefreshToken(refreshToken string, client ClientInfo) (*dto.LoginResult, error)
+	ForgotPassword(email string) error
 	ResetPassword(token, newPassword string) error
+	InviteUser(user *models.User) error
+	ResendVerification(userID uuid.UUID) error
+	VerifyEmail(token string) (*models.User, error)
+	UnlockAccount(token string) error
+	AdminUnlockAccount(userID uuid.UUID) error
 }
 
 // AuthServiceImpl implements AuthService
 type AuthServiceImpl struct {
-	authRepo repositories.AuthRepository
-	userRepo repositories.UserRepository
+	authRepo       repositories.AuthRepository
+	userRepo       repositories.UserRepository
+	twoFactorRepo  repositories.TwoFactorRepository
+	sessionService SessionService
 }
 
 // NewAuthService creates a new instance of AuthService
-func NewAuthService(authRepo repositories.AuthRepository, userRepo repositories.UserRepository) AuthService {
+func NewAuthService(authRepo repositories.AuthRepository, userRepo repositories.UserRepository, twoFactorRepo repositories.TwoFactorRepository, sessionService SessionService) AuthService {
 	return &AuthServiceImpl{
-		authRepo: authRepo,
-		userRepo: userRepo,
+		authRepo:       authRepo,
+		userRepo:       userRepo,
+		twoFactorRepo:  twoFactorRepo,
+		sessionService: sessionService,
 	}
 }
 
 // Register handles user registration
-func (s *AuthServiceImpl) Register(username, email, password, gender string) (*models.User, error) {
+func (s *AuthServiceImpl) Register(username, email, password, gender, locale string) (*models.User, error) {
 	// Check if password is strong
 	if !authPkg.IsStrongPassword(password) {
 		return nil, errors.New(constants.ErrStrongPassword)
@@ -69,6 +88,11 @@ func (s *AuthServiceImpl) Register(username, email, password, gender string) (*m
 		return nil, errors.New(constants.ErrInvalidGender)
 	}
 
+	// Emails are sent in the default language unless one is chosen
+	if locale != "" && !mail.IsSupportedLocale(locale) {
+		return nil, errors.New(constants.ErrUnsupportedLocale)
+	}
+
 	// Set default avatar based on gender
 	var avatar string
 	if gender == "male" {
@@ -97,110 +121,165 @@ func (s *AuthServiceImpl) Register(username, email, password, gender string) (*m
 		Password: hashedPassword,
 		Gender:   genderEnum,
 		Avatar:   avatar,
+		Locale:   locale,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
+	// The account is usable for its profile only until the address is confirmed
+	if err := s.issueVerification(user); err != nil {
+		logger.Error("Failed to send verification email", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
 	return user, nil
 }
 
-// Login handles user authentication
-func (s *AuthServiceImpl) Login(email, password string) (string, string, error) {
-	// Find the user by email
+// Login checks the password. Accounts with two-factor authentication get a challenge
+// to complete with CompleteTwoFactorLogin instead of tokens. Repeated failures delay
+// further attempts and lock the account; a RetryAfterError says for how long.
+func (s *AuthServiceImpl) Login(email, password string, client ClientInfo) (*dto.LoginResult, error) {
+	// Blocked addresses and throttled accounts are refused before spending a bcrypt comparison
+	if wait := ipFailures.blocked(client.IPAddress); wait > 0 {
+		return nil, &RetryAfterError{Message: constants.ErrLoginThrottled, RetryAfter: wait}
+	}
+
+	// Find the user by email; unknown emails are refused, delayed and compared like accounts
 	user, err := s.authRepo.GetUserByEmail(email)
 	if err != nil {
-		return "", "", errors.New(constants.ErrInvalidCredentials)
+		if err := unknownEmailFailures.refusal(email, time.Now()); err != nil {
+			return nil, err
+		}
+		authPkg.CheckDummyPassword(password)
+		return nil, s.failLogin(nil, email, "failed_login", client)
+	}
+
+	if err := loginRefusal(user, time.Now()); err != nil {
+		return nil, err
 	}
 
 	// Check password
 	if !authPkg.CheckPasswordHash(password, user.Password) {
-		return "", "", errors.New(constants.ErrInvalidCredentials)
+		return nil, s.failLogin(user, email, "failed_login", client)
 	}
 
-	// Generate access token
-	accessToken, err := authPkg.GenerateJWT(user.ID)
+	result, err := s.CompleteLogin(user, client)
 	if err != nil {
-		return "", "", errors.New(constants.ErrTokenGenerationFailed)
+		return nil, err
 	}
 
-	// Generate refresh token
-	refreshToken, err := authPkg.GenerateSecureToken(32)
-	if err != nil {
-		return "", "", errors.New("failed to generate refresh token")
+	// A correct password alone must not reset the count of wrong second factors
+	if !result.TwoFactorRequired {
+		s.clearFailedLogins(user)
 	}
+	return result, nil
+}
 
-	// Set refresh token expiry
-	refreshExpiry := time.Now().Add(7 * 24 * time.Hour)
-
-	// Update user with refresh token
-	if err := s.authRepo.UpdateRefreshToken(user.ID, refreshToken, refreshExpiry); err != nil {
-		return "", "", errors.New("failed to save refresh token")
+// CompleteLogin finishes the login of a user whose first factor, a password or an OIDC
+// provider, was checked: accounts with two-factor authentication get a challenge
+func (s *AuthServiceImpl) CompleteLogin(user *models.User, client ClientInfo) (*dto.LoginResult, error) {
+	if user.TwoFactorEnabledAt != nil {
+		challenge, err := authPkg.GenerateTwoFactorChallenge(user.ID, constants.TwoFactorChallengeTTL)
+		if err != nil {
+			return nil, errors.New(constants.ErrTokenGenerationFailed)
+		}
+		return &dto.LoginResult{TwoFactorRequired: true, ChallengeToken: challenge}, nil
 	}
 
-	return accessToken, refreshToken, nil
+	return s.sessionService.Start(user, client)
 }
 
-// RefreshToken handles refreshing the JWT using a refresh token
-func (s *AuthServiceImpl) RefreshToken(refreshToken string) (string, string, error) {
-	// Find the user by refresh token
-	user, err := s.authRepo.GetUserByRefreshToken(refreshToken)
+// CompleteTwoFactorLogin exchanges a login challenge and a TOTP or recovery code for tokens.
+// Wrong codes count as failed logins of the user, so they are delayed and lock the account
+// however many challenges a known password mints.
+func (s *AuthServiceImpl) CompleteTwoFactorLogin(challengeToken, code string, client ClientInfo) (*dto.LoginResult, error) {
+	claims, err := authPkg.ParseTwoFactorChallenge(challengeToken)
 	if err != nil {
-		return "", "", errors.New(constants.ErrInvalidToken)
+		return nil, errors.New(constants.ErrInvalidToken)
 	}
+	expiresAt := claims.ExpiresAt.Time
 
-	// Check if refresh token is expired
-	if user.RefreshTokenExpiry.Before(time.Now()) {
-		return "", "", errors.New(constants.ErrInvalidToken)
+	if challengeAttempts.exhausted(claims.ID) {
+		return nil, errors.New(constants.ErrTooManyCodeAttempts)
 	}
 
-	// Generate new access token
-	accessToken, err := authPkg.GenerateJWT(user.ID)
-	if err != nil {
-		return "", "", errors.New(constants.ErrTokenGenerationFailed)
+	if wait := ipFailures.blocked(client.IPAddress); wait > 0 {
+		return nil, &RetryAfterError{Message: constants.ErrLoginThrottled, RetryAfter: wait}
 	}
 
-	// Generate new refresh token
-	newRefreshToken, err := authPkg.GenerateSecureToken(32)
+	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return "", "", errors.New("failed to generate refresh token")
+		return nil, errors.New(constants.ErrInvalidToken)
 	}
 
-	// Set new refresh token expiry
-	newExpiry := time.Now().Add(7 * 24 * time.Hour)
+	if err := loginRefusal(user, time.Now()); err != nil {
+		return nil, err
+	}
 
-	// Update user with new refresh token
-	if err := s.authRepo.UpdateRefreshToken(user.ID, newRefreshToken, newExpiry); err != nil {
-		return "", "", errors.New("failed to update refresh token")
+	if err := verifySecondFactor(s.twoFactorRepo, user, code); err != nil {
+		if err.Error() == constants.ErrInvalidTwoFactorCode {
+			challengeAttempts.fail(claims.ID, expiresAt)
+			var locked *RetryAfterError
+			if errors.As(s.failLogin(user, user.Email, "failed_two_factor", client), &locked) {
+				return nil, locked
+			}
+		}
+		return nil, err
 	}
+	challengeAttempts.close(claims.ID, expiresAt)
+	s.clearFailedLogins(user)
+
+	return s.sessionService.Start(user, client)
+}
 
-	return accessToken, newRefreshToken, nil
+// RefreshToken replaces the refresh token of a session and issues a new access token
+func (s *AuthServiceImpl) RefreshToken(refreshToken string, client ClientInfo) (*dto.LoginResult, error) {
+	return s.sessionService.Refresh(refreshToken, client)
 }
 
-// ForgotPassword handles password reset request
-func (s *AuthServiceImpl) ForgotPassword(email string) (string, error) {
+// ForgotPassword emails a password reset link
+func (s *AuthServiceImpl) ForgotPassword(email string) error {
 	// Check if user exists
-	_, err := s.authRepo.GetUserByEmail(email)
+	user, err := s.authRepo.GetUserByEmail(email)
 	if err != nil {
-		return "", errors.New(constants.ErrUserNotFound)
+		return errors.New(constants.ErrUserNotFound)
 	}
 
+	return s.sendPasswordLink(user, mail.TemplatePasswordReset, constants.PasswordResetTTL)
+}
+
+// InviteUser emails a user created on their behalf a link to choose their password
+func (s *AuthServiceImpl) InviteUser(user *models.User) error {
+	return s.sendPasswordLink(user, mail.TemplateInvitation, constants.InvitationTTL)
+}
+
+// sendPasswordLink stores a new reset token and emails the link to use it
+func (s *AuthServiceImpl) sendPasswordLink(user *models.User, template string, ttl time.Duration) error {
 	// Generate reset token
 	resetToken, err := authPkg.GenerateSecureToken(16)
 	if err != nil {
-		return "", errors.New(constants.ErrResetTokenFailed)
+		return errors.New(constants.ErrResetTokenFailed)
 	}
 
-	// Set token expiry (12 hours)
-	expiry := time.Now().Add(12 * time.Hour)
-
 	// Update user with reset token
-	if err := s.authRepo.UpdateResetToken(email, resetToken, expiry); err != nil {
-		return "", errors.New(constants.ErrResetTokenFailed)
+	if err := s.authRepo.UpdateResetToken(user.Email, authPkg.HashToken(resetToken), time.Now().Add(ttl)); err != nil {
+		return errors.New(constants.ErrResetTokenFailed)
+	}
+
+	err = mail.Send(user.Email, user.Locale, template, mail.Data{
+		"Username":     user.Username,
+		"Email":        user.Email,
+		"Link":         appLink("/reset-password", resetToken),
+		"ExpiresHours": int(ttl.Hours()),
+		"ExpiresDays":  int(ttl.Hours() / 24),
+	})
+	if err != nil {
+		logger.Error("Failed to queue password email", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return errors.New(constants.ErrMailFailed)
 	}
 
-	return resetToken, nil
+	return nil
 }
 
 // ResetPassword handles password reset using a token
@@ -211,7 +290,7 @@ func (s *AuthServiceImpl) ResetPassword(token, newPassword string) error {
 	}
 
 	// Find the user by reset token
-	user, err := s.authRepo.GetUserByResetToken(token)
+	user, err := s.authRepo.GetUserByResetToken(authPkg.HashToken(token))
 	if err != nil {
 		return errors.New(constants.ErrInvalidToken)
 	}
@@ -231,6 +310,136 @@ func (s *AuthServiceImpl) ResetPassword(token, newPassword string) error {
 	if err := s.authRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
 		return errors.New(constants.ErrDatabase)
 	}
+	if err := revokeAccessTokens(s.userRepo, user.ID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+	// Whoever made the reset necessary may hold a refresh token, so every session ends
+	if _, err := s.sessionService.RevokeAll(user.ID); err != nil {
+		return err
+	}
+
+	// A new password also lifts a lockout, so "Forgot password" works while locked out
+	if err := s.authRepo.ClearFailedLogins(user.ID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+
+	// The token was emailed, so using it also proves the address; this is how invited users verify
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := s.authRepo.UpdateEmailVerifiedAt(user.ID, &now); err != nil {
+			return errors.New(constants.ErrDatabase)
+		}
+	}
+
+	sendNotice(user, user.Email, mail.NoticePasswordChanged)
+
+	return nil
+}
+
+// ResendVerification sends a new verification link to a user who has not confirmed their email
+func (s *AuthServiceImpl) ResendVerification(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New(constants.ErrUserNotFound)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return errors.New(constants.ErrEmailAlreadyVerified)
+	}
+	if user.VerificationSentAt != nil && time.Since(*user.VerificationSentAt) < constants.VerificationResendCooldown {
+		return errors.New(constants.ErrVerificationTooSoon)
+	}
+
+	return s.issueVerification(user)
+}
+
+// VerifyEmail confirms the email address a verification link was sent to
+func (s *AuthServiceImpl) VerifyEmail(token string) (*models.User, error) {
+	claims, err := authPkg.ParseVerificationToken(token)
+	if err != nil {
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+
+	// Links sent to a previous address are void
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil, errors.New(constants.ErrEmailAlreadyVerified)
+	}
+
+	now := time.Now()
+	if err := s.authRepo.UpdateEmailVerifiedAt(user.ID, &now); err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+	user.EmailVerifiedAt = &now
+
+	return user, nil
+}
+
+// UnlockAccount lifts the lockout an emailed unlock link was sent for
+func (s *AuthServiceImpl) UnlockAccount(token string) error {
+	claims, err := authPkg.ParseUnlockToken(token)
+	if err != nil {
+		return errors.New(constants.ErrInvalidToken)
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return errors.New(constants.ErrInvalidToken)
+	}
+
+	// Links of an earlier, already lifted lockout are void
+	if user.LockedUntil == nil || user.LockedUntil.Unix() != claims.LockedUntil {
+		return errors.New(constants.ErrInvalidToken)
+	}
+
+	if err := s.authRepo.ClearFailedLogins(user.ID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+
+	if err := LogSecurityEvent("account_unlocked", &user.ID, auditPkg.BuildAccountUnlockMetadata(user)); err != nil {
+		logger.Warn("LogSecurityEvent failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// AdminUnlockAccount lifts the lockout of a user and forgets their failed logins, which
+// also ends a login delay
+func (s *AuthServiceImpl) AdminUnlockAccount(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New(constants.ErrUserNotFound)
+	}
+
+	if user.FailedLoginCount == 0 && (user.LockedUntil == nil || user.LockedUntil.Before(time.Now())) {
+		return errors.New(constants.ErrAccountNotLocked)
+	}
+
+	if err := s.authRepo.ClearFailedLogins(user.ID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+
+	return nil
+}
+
+// issueVerification sends a verification link and records when it was sent
+func (s *AuthServiceImpl) issueVerification(user *models.User) error {
+	if err := sendVerificationLink(user); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.authRepo.UpdateVerificationSentAt(user.ID, now); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+	user.VerificationSentAt = &now
 
 	return nil
 }
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+	auditPkg "go-gin-starter/pkg/audit"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetryAfterError is an error the client may retry after a known time
+type RetryAfterError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Message
+}
+
+// loginRefusal returns why a password login of a user is refused before the password is
+// checked, or nil if it may be tried
+func loginRefusal(user *models.User, now time.Time) error {
+	return refuseLogin(user.FailedLoginCount, user.LastFailedLoginAt, user.LockedUntil, now)
+}
+
+// refuseLogin applies the lockout and the delay after recent failures
+func refuseLogin(failures int, lastFailure, lockedUntil *time.Time, now time.Time) error {
+	if lockedUntil != nil && now.Before(*lockedUntil) {
+		return &RetryAfterError{Message: constants.ErrAccountLocked, RetryAfter: lockedUntil.Sub(now)}
+	}
+	if lastFailure != nil && now.Sub(*lastFailure) <= config.LoginFailureWindow {
+		if wait := lastFailure.Add(loginDelay(failures)).Sub(now); wait > 0 {
+			return &RetryAfterError{Message: constants.ErrLoginThrottled, RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// failLogin records a failed login for an email, of user or of no known user if nil, and
+// returns the error to answer the attempt with. Unknown emails are counted in memory and
+// answered like accounts, so delays and lockouts do not reveal which emails are registered.
+// The event names the factor that was wrong: failed_login for a password, failed_two_factor
+// for a second factor.
+func (s *AuthServiceImpl) failLogin(user *models.User, email, event string, client ClientInfo) error {
+	now := time.Now()
+	failed := errors.New(constants.ErrInvalidCredentials)
+
+	var userID *uuid.UUID
+	failures := 0
+	if user != nil {
+		userID = &user.ID
+		count, err := s.authRepo.RecordFailedLogin(user.ID, now, config.LoginFailureWindow)
+		if err != nil {
+			logger.Error("Failed to record failed login", zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+		failures = count
+	} else {
+		failures = unknownEmailFailures.fail(email, now)
+	}
+
+	metadata := auditPkg.BuildFailedLoginMetadata(truncate(email, 255), failures, truncate(client.UserAgent, 255), client.IPAddress)
+	if err := LogSecurityEvent(event, userID, metadata); err != nil {
+		logger.Warn("LogSecurityEvent failed", zap.Error(err))
+	}
+
+	if ipFailures.fail(client.IPAddress) {
+		logger.Warn("Blocked logins from IP address", zap.String("ip", client.IPAddress))
+		metadata := auditPkg.BuildLoginIPBlockedMetadata(client.IPAddress, config.LoginIPBlockDuration)
+		if err := LogSecurityEvent("login_ip_blocked", nil, metadata); err != nil {
+			logger.Warn("LogSecurityEvent failed", zap.Error(err))
+		}
+	}
+
+	if config.LoginLockoutFailures == 0 || failures < config.LoginLockoutFailures {
+		return failed
+	}
+
+	lockedUntil := now.Add(config.LoginLockoutDuration)
+	if user == nil {
+		unknownEmailFailures.lock(email, lockedUntil)
+		return &RetryAfterError{Message: constants.ErrAccountLocked, RetryAfter: config.LoginLockoutDuration}
+	}
+	if err := s.authRepo.LockAccount(user.ID, lockedUntil); err != nil {
+		logger.Error("Failed to lock account", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return failed
+	}
+
+	logger.Warn("Locked account after failed logins", zap.String("user_id", user.ID.String()), zap.Int("failures", failures))
+	metadata = auditPkg.BuildAccountLockedMetadata(user, failures, lockedUntil, client.IPAddress)
+	if err := LogSecurityEvent("account_locked", userID, metadata); err != nil {
+		logger.Warn("LogSecurityEvent failed", zap.Error(err))
+	}
+	sendUnlockLink(user, lockedUntil)
+
+	return &RetryAfterError{Message: constants.ErrAccountLocked, RetryAfter: config.LoginLockoutDuration}
+}
+
+// clearFailedLogins forgets the failed logins of a user who just logged in
+func (s *AuthServiceImpl) clearFailedLogins(user *models.User) {
+	if user.FailedLoginCount == 0 && user.LockedUntil == nil {
+		return
+	}
+	if err := s.authRepo.ClearFailedLogins(user.ID); err != nil {
+		logger.Error("Failed to clear failed logins", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+}
+
+// loginDelay returns how long after its last failure an account with the given number of
+// recent failures has to wait before the next attempt
+func loginDelay(failures int) time.Duration {
+	if config.LoginDelayAfter == 0 || failures < config.LoginDelayAfter {
+		return 0
+	}
+	delay := config.LoginDelayBase
+	for i := config.LoginDelayAfter; i < failures && delay < config.LoginDelayMax; i++ {
+		delay *= 2
+	}
+	return min(delay, config.LoginDelayMax)
+}
+
+// ipFailures counts failed logins per client IP address, on any accounts. It is shared by
+// all service instances but not across processes, like the request rate limiter.
+var ipFailures = &ipFailureTracker{entries: make(map[string]*ipFailureEntry)}
+
+type ipFailureEntry struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+type ipFailureTracker struct {
+	mu         sync.Mutex
+	entries    map[string]*ipFailureEntry
+	lastPruned time.Time
+}
+
+// blocked returns how long logins from an IP address are still refused
+func (t *ipFailureTracker) blocked(ip string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[ip]; ok {
+		return time.Until(entry.blockedUntil)
+	}
+	return 0
+}
+
+// fail counts a failed login from an IP address and reports whether that blocked it
+func (t *ipFailureTracker) fail(ip string) bool {
+	if config.LoginIPMaxFailures == 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(t.lastPruned) > config.LoginFailureWindow {
+		for key, entry := range t.entries {
+			if now.Sub(entry.lastFailure) > config.LoginFailureWindow && now.After(entry.blockedUntil) {
+				delete(t.entries, key)
+			}
+		}
+		t.lastPruned = now
+	}
+
+	entry, ok := t.entries[ip]
+	if !ok || now.Sub(entry.lastFailure) > config.LoginFailureWindow {
+		entry = &ipFailureEntry{}
+		t.entries[ip] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+
+	if entry.failures < config.LoginIPMaxFailures {
+		return false
+	}
+	entry.failures = 0
+	entry.blockedUntil = now.Add(config.LoginIPBlockDuration)
+	return true
+}
+
+// unknownEmailFailures counts failed logins per email address that belongs to no account,
+// the way users count theirs, so such logins are delayed and locked out like real accounts.
+// It is shared by all service instances but not across processes, like ipFailures.
+var unknownEmailFailures = &emailFailureTracker{entries: make(map[string]*emailFailureEntry)}
+
+type emailFailureEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+type emailFailureTracker struct {
+	mu         sync.Mutex
+	entries    map[string]*emailFailureEntry
+	lastPruned time.Time
+}
+
+// refusal returns why a login with an unknown email is refused before it is tried, or nil
+func (t *emailFailureTracker) refusal(email string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[emailKey(email)]
+	if !ok {
+		return nil
+	}
+	return refuseLogin(entry.failures, &entry.lastFailure, &entry.lockedUntil, now)
+}
+
+// fail counts a failed login with an unknown email and returns the recent failures
+func (t *emailFailureTracker) fail(email string, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastPruned) > config.LoginFailureWindow {
+		for key, entry := range t.entries {
+			if now.Sub(entry.lastFailure) > config.LoginFailureWindow && now.After(entry.lockedUntil) {
+				delete(t.entries, key)
+			}
+		}
+		t.lastPruned = now
+	}
+
+	key := emailKey(email)
+	entry, ok := t.entries[key]
+	if !ok {
+		entry = &emailFailureEntry{}
+		t.entries[key] = entry
+	}
+	if now.Sub(entry.lastFailure) > config.LoginFailureWindow {
+		entry.failures = 0
+	}
+	entry.failures++
+	entry.lastFailure = now
+	return entry.failures
+}
+
+// lock refuses logins with an unknown email until the given time
+func (t *emailFailureTracker) lock(email string, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[emailKey(email)]; ok {
+		entry.lockedUntil = until
+	}
+}
+
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
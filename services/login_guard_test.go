@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+)
+
+// useLoginLimits sets the login limits for one test
+func useLoginLimits(t *testing.T) {
+	t.Helper()
+	delayAfter, delayBase, delayMax := config.LoginDelayAfter, config.LoginDelayBase, config.LoginDelayMax
+	lockoutFailures, lockoutDuration, window := config.LoginLockoutFailures, config.LoginLockoutDuration, config.LoginFailureWindow
+	t.Cleanup(func() {
+		config.LoginDelayAfter, config.LoginDelayBase, config.LoginDelayMax = delayAfter, delayBase, delayMax
+		config.LoginLockoutFailures, config.LoginLockoutDuration, config.LoginFailureWindow = lockoutFailures, lockoutDuration, window
+	})
+
+	config.LoginDelayAfter, config.LoginDelayBase, config.LoginDelayMax = 3, time.Second, time.Minute
+	config.LoginLockoutFailures, config.LoginLockoutDuration = 10, 30*time.Minute
+	config.LoginFailureWindow = 15 * time.Minute
+}
+
+// refusalOf reduces a refusal to what a client sees of it
+func refusalOf(err error) string {
+	var retry *RetryAfterError
+	if errors.As(err, &retry) {
+		return retry.Message + " after " + retry.RetryAfter.String()
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return "allowed"
+}
+
+// TestUnknownEmailsAreRefusedLikeAccounts fails logins of an account and of an unknown email
+// side by side and expects the same delays and lockout for both
+func TestUnknownEmailsAreRefusedLikeAccounts(t *testing.T) {
+	useLoginLimits(t)
+	tracker := &emailFailureTracker{entries: make(map[string]*emailFailureEntry)}
+	user := &models.User{}
+	now := time.Now()
+
+	for attempt := 1; attempt <= config.LoginLockoutFailures; attempt++ {
+		if got, want := refusalOf(tracker.refusal("Nobody@Example.com", now)), refusalOf(loginRefusal(user, now)); got != want {
+			t.Fatalf("attempt %d: unknown email %s, account %s", attempt, got, want)
+		}
+
+		failedAt := now
+		user.FailedLoginCount++
+		user.LastFailedLoginAt = &failedAt
+		if failures := tracker.fail(" nobody@example.com", now); failures != user.FailedLoginCount {
+			t.Fatalf("attempt %d: %d failures counted for the unknown email", attempt, failures)
+		}
+		if user.FailedLoginCount == config.LoginLockoutFailures {
+			lockedUntil := now.Add(config.LoginLockoutDuration)
+			user.LockedUntil = &lockedUntil
+			tracker.lock("nobody@example.com", lockedUntil)
+		}
+
+		now = now.Add(loginDelay(user.FailedLoginCount))
+	}
+
+	err := tracker.refusal("nobody@example.com", now)
+	if got, want := refusalOf(err), refusalOf(loginRefusal(user, now)); got != want {
+		t.Fatalf("after the lockout: unknown email %s, account %s", got, want)
+	}
+	if err == nil || err.Error() != constants.ErrAccountLocked {
+		t.Fatalf("unknown email is not locked: %v", err)
+	}
+
+	// Both are counted from zero again once the lockout has passed
+	now = now.Add(config.LoginLockoutDuration)
+	if err := tracker.refusal("nobody@example.com", now); err != nil {
+		t.Fatalf("unknown email still refused after the lockout: %v", err)
+	}
+	if failures := tracker.fail("nobody@example.com", now); failures != 1 {
+		t.Fatalf("%d failures counted after the lockout, want 1", failures)
+	}
+}
@@ -7,17 +7,16 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/constants"
-	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/pkg/logger"
 	scoutPkg "go-gin-starter/pkg/scout"
 	storagePkg "go-gin-starter/pkg/storage"
+	"go-gin-starter/pkg/upload"
 	"go-gin-starter/pkg/video"
 	"go-gin-starter/repositories"
 
@@ -28,34 +27,47 @@ import (
 // MatchService defines the interface for match-related business logic
 type MatchService interface {
 	CreateMatch(input *dto.CreateMatchInput) (*dto.MatchResponse, error)
-	GetAllMatches() ([]dto.MatchListResponse, error)
-	GetMatchByID(id uuid.UUID) (*dto.MatchResponse, error)
+	GetAllMatches(viewerID uuid.UUID) ([]dto.MatchListResponse, error)
+	GetMatchByID(id, viewerID uuid.UUID) (*dto.MatchResponse, error)
 	UpdateMatch(id uuid.UUID, input *dto.UpdateMatchInput) (*dto.MatchResponse, error)
 	DeleteMatch(id uuid.UUID) error
-	UploadMatchVideo(matchID uuid.UUID, file io.Reader, fileHeader *multipart.FileHeader) (string, error)
-	UploadMatchScout(matchID uuid.UUID, file io.Reader, fileHeader *multipart.FileHeader) (string, error)
+	UploadMatchVideo(matchID uuid.UUID, angle string, file io.ReadSeeker, fileHeader *multipart.FileHeader) (string, error)
+	UploadMatchScout(matchID uuid.UUID, file io.ReadSeeker, fileHeader *multipart.FileHeader) (string, error)
+	GetScoutMetadata(matchID, viewerID uuid.UUID) (*dto.ScoutMetadataResponse, error)
 }
 
 // MatchServiceImpl implements MatchService
 type MatchServiceImpl struct {
-	matchRepo  repositories.MatchRepository
-	teamRepo   repositories.TeamRepository
-	seasonRepo repositories.SeasonRepository
-	videoQueue *video.QueueManager
+	matchRepo      repositories.MatchRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	teamRepo       repositories.TeamRepository
+	seasonRepo     repositories.SeasonRepository
+	userRepo       repositories.UserRepository
+	videoQueue     *video.QueueManager
+	store          storagePkg.ObjectStore
 }
 
 // NewMatchService creates a new instance of MatchService
 func NewMatchService(
 	matchRepo repositories.MatchRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	artifactRepo repositories.VideoArtifactRepository,
 	teamRepo repositories.TeamRepository,
 	seasonRepo repositories.SeasonRepository,
+	userRepo repositories.UserRepository,
 	videoQueue *video.QueueManager,
+	store storagePkg.ObjectStore,
 ) MatchService {
 	return &MatchServiceImpl{
-		matchRepo:  matchRepo,
-		teamRepo:   teamRepo,
-		seasonRepo: seasonRepo,
-		videoQueue: videoQueue,
+		matchRepo:      matchRepo,
+		matchVideoRepo: matchVideoRepo,
+		artifactRepo:   artifactRepo,
+		teamRepo:       teamRepo,
+		seasonRepo:     seasonRepo,
+		userRepo:       userRepo,
+		videoQueue:     videoQueue,
+		store:          store,
 	}
 }
 
@@ -95,8 +107,8 @@ func (s *MatchServiceImpl) CreateMatch(input *dto.CreateMatchInput) (*dto.MatchR
 		AwayTeamName: s.getTeamName(awayTeam),
 		Round:        match.Round,
 		Location:     match.Location,
-		VideoURL:     match.VideoURL,
-		ScoutJSON:    match.ScoutJSON,
+		VideoURL:     fullMediaAccess.videoURL(match.VideoURL),
+		ScoutJSON:    fullMediaAccess.scoutURL(match.ScoutJSON),
 		CreatedAt:    match.CreatedAt,
 		UpdatedAt:    match.UpdatedAt,
 	}
@@ -104,13 +116,15 @@ func (s *MatchServiceImpl) CreateMatch(input *dto.CreateMatchInput) (*dto.MatchR
 	return &response, nil
 }
 
-// GetAllMatches returns all matches
-func (s *MatchServiceImpl) GetAllMatches() ([]dto.MatchListResponse, error) {
+// GetAllMatches returns all matches, with media URLs signed for the viewer
+func (s *MatchServiceImpl) GetAllMatches(viewerID uuid.UUID) ([]dto.MatchListResponse, error) {
 	matches, err := s.matchRepo.GetAll()
 	if err != nil {
 		return nil, err
 	}
 
+	access := resolveMediaAccess(s.userRepo, viewerID)
+
 	var responses []dto.MatchListResponse
 	for _, m := range matches {
 		season, _ := s.seasonRepo.GetByID(m.SeasonID)
@@ -132,8 +146,8 @@ func (s *MatchServiceImpl) GetAllMatches() ([]dto.MatchListResponse, error) {
 			AwayTeamName: s.getTeamName(awayTeam),
 			Round:        m.Round,
 			Location:     m.Location,
-			VideoURL:     m.VideoURL,
-			ScoutJSONURL: m.ScoutJSON,
+			VideoURL:     access.videoURL(m.VideoURL),
+			ScoutJSONURL: access.scoutURL(m.ScoutJSON),
 			JsonStatus:   status,
 			CreatedAt:    m.CreatedAt,
 			UpdatedAt:    m.UpdatedAt,
@@ -143,8 +157,9 @@ func (s *MatchServiceImpl) GetAllMatches() ([]dto.MatchListResponse, error) {
 	return responses, nil
 }
 
-// GetMatchByID returns a single match by ID
-func (s *MatchServiceImpl) GetMatchByID(id uuid.UUID) (*dto.MatchResponse, error) {
+// GetMatchByID returns a single match by ID. Video and scout URLs are signed per request
+// for viewers allowed to see them and left empty for everyone else.
+func (s *MatchServiceImpl) GetMatchByID(id, viewerID uuid.UUID) (*dto.MatchResponse, error) {
 	match, err := s.matchRepo.GetByID(id)
 	if err != nil {
 		return nil, errors.New(constants.ErrMatchNotFound)
@@ -154,32 +169,24 @@ func (s *MatchServiceImpl) GetMatchByID(id uuid.UUID) (*dto.MatchResponse, error
 	homeTeam, _ := s.teamRepo.GetByID(match.HomeTeamID)
 	awayTeam, _ := s.teamRepo.GetByID(match.AwayTeamID)
 
-	// Fetch and parse JSON from S3
+	access := resolveMediaAccess(s.userRepo, viewerID)
+
 	var jsonData map[string]interface{}
-	if match.ScoutJSON != "" {
-		jsonData, _ = fetchJSONFromS3(match.ScoutJSON)
+	if match.ScoutJSON != "" && access.scout {
+		jsonData, _ = s.fetchScoutJSON(match.ScoutJSON)
 	}
 
-	// Build base path for video formats
-	safeSeasonName := strings.ReplaceAll(strings.ToLower(string(season.Name)), " ", "_")
-	safeSeasonYear := strings.ReplaceAll(season.SeasonYear, "/", "_")
-	safeGender := strings.ToLower(string(season.Gender))
-	safeCountry := strings.ToLower(string(season.Country))
-	basePath := fmt.Sprintf("videos/%s_%s/%s_%s/%s",
-		safeSeasonYear,
-		safeCountry,
-		safeSeasonName,
-		safeGender,
-		match.ID.String(),
-	)
-
-	// Extarct filename from video_url to use in all formats
-	videoUUID := getVideoUUIDFromURL(match.VideoURL)
+	matchVideos, err := s.matchVideoRepo.GetByMatchID(match.ID)
+	if err != nil {
+		logger.Error("Failed to fetch match videos", zap.String("match_id", match.ID.String()), zap.Error(err))
+	}
+	videos := buildMatchVideoResponses(s.artifactRepo, matchVideos)
+	access.signMatchVideos(videos)
 
-	videoQualities := map[string]string{
-		"1080p": fmt.Sprintf("https://%s/%s/compressed/1080p/%s", os.Getenv("VIDEO_CLOUDFRONT_DOMAIN"), basePath, videoUUID),
-		"720p":  fmt.Sprintf("https://%s/%s/compressed/720p/%s", os.Getenv("VIDEO_CLOUDFRONT_DOMAIN"), basePath, videoUUID),
-		"480p":  fmt.Sprintf("https://%s/%s/compressed/480p/%s", os.Getenv("VIDEO_CLOUDFRONT_DOMAIN"), basePath, videoUUID),
+	// The top-level qualities follow the reference angle
+	var videoQualities map[string]string
+	if len(videos) > 0 && videos[0].IsReference {
+		videoQualities = videos[0].VideoQualities
 	}
 
 	return &dto.MatchResponse{
@@ -192,23 +199,32 @@ func (s *MatchServiceImpl) GetMatchByID(id uuid.UUID) (*dto.MatchResponse, error
 		AwayTeamName:   s.getTeamName(awayTeam),
 		Round:          match.Round,
 		Location:       match.Location,
-		VideoURL:       match.VideoURL,
+		VideoURL:       access.videoURL(match.VideoURL),
 		VideoQualities: videoQualities,
-		ThumbnailURL:   match.ThumbnailURL,
-		ScoutJSON:      match.ScoutJSON,
+		Videos:         videos,
+		ThumbnailURL:   access.videoURL(match.ThumbnailURL),
+		ScoutJSON:      access.scoutURL(match.ScoutJSON),
 		JsonData:       jsonData,
 		CreatedAt:      match.CreatedAt,
 		UpdatedAt:      match.UpdatedAt,
 	}, nil
 }
 
-// getVideoUUIDFromURL extracts the final part of the URL (filename with .mp4)
-func getVideoUUIDFromURL(url string) string {
-	parts := strings.Split(url, "/")
-	if len(parts) == 0 {
-		return ""
+// buildVideoMetadataResponse maps probed metadata to its DTO, or nil if the video was never probed
+func buildVideoMetadataResponse(meta *models.VideoMetadata) *dto.VideoMetadataResponse {
+	if meta.DurationMs == 0 && meta.VideoCodec == "" {
+		return nil
+	}
+
+	return &dto.VideoMetadataResponse{
+		DurationMs: meta.DurationMs,
+		Width:      meta.Width,
+		Height:     meta.Height,
+		FrameRate:  meta.FrameRate,
+		VideoCodec: meta.VideoCodec,
+		AudioCodec: meta.AudioCodec,
+		FileSize:   meta.FileSize,
 	}
-	return parts[len(parts)-1]
 }
 
 // UpdateMatch updates an existing match
@@ -231,10 +247,10 @@ func (s *MatchServiceImpl) UpdateMatch(id uuid.UUID, input *dto.UpdateMatchInput
 		match.Location = input.Location
 	}
 	if input.VideoURL != "" {
-		match.VideoURL = input.VideoURL
+		match.VideoURL = assets.StoredRef(input.VideoURL)
 	}
 	if input.ScoutJSON != "" {
-		match.ScoutJSON = input.ScoutJSON
+		match.ScoutJSON = assets.StoredRef(input.ScoutJSON)
 	}
 
 	if err := s.matchRepo.Update(match); err != nil {
@@ -255,8 +271,8 @@ func (s *MatchServiceImpl) UpdateMatch(id uuid.UUID, input *dto.UpdateMatchInput
 		AwayTeamName: s.getTeamName(awayTeam),
 		Round:        match.Round,
 		Location:     match.Location,
-		VideoURL:     match.VideoURL,
-		ScoutJSON:    match.ScoutJSON,
+		VideoURL:     fullMediaAccess.videoURL(match.VideoURL),
+		ScoutJSON:    fullMediaAccess.scoutURL(match.ScoutJSON),
 		CreatedAt:    match.CreatedAt,
 		UpdatedAt:    match.UpdatedAt,
 	}, nil
@@ -267,12 +283,23 @@ func (s *MatchServiceImpl) DeleteMatch(id uuid.UUID) error {
 	return s.matchRepo.Delete(id)
 }
 
-// UploadMatchVideo handles uploading a match video to S3
+// UploadMatchVideo handles uploading a match video for one camera angle
 func (s *MatchServiceImpl) UploadMatchVideo(
 	matchID uuid.UUID,
-	file io.Reader,
+	angle string,
+	file io.ReadSeeker,
 	fileHeader *multipart.FileHeader,
 ) (string, error) {
+	angle, err := normalizeVideoAngle(angle)
+	if err != nil {
+		return "", err
+	}
+
+	contentType, err := upload.ValidateContent(upload.MatchVideo, file, fileHeader.Size)
+	if err != nil {
+		return "", err
+	}
+
 	match, err := s.matchRepo.GetByID(matchID)
 	if err != nil {
 		return "", errors.New(constants.ErrMatchNotFound)
@@ -283,51 +310,36 @@ func (s *MatchServiceImpl) UploadMatchVideo(
 		return "", errors.New(constants.ErrSeasonNotFound)
 	}
 
-	// Create the folder structure
-	safeSeasonName := strings.ReplaceAll(strings.ToLower(string(season.Name)), " ", "_")
-	safeSeasonYear := strings.ReplaceAll(season.SeasonYear, "/", "_")
-	safeGender := strings.ToLower(string(season.Gender))
-	safeCountry := strings.ToLower(string(season.Country))
-
 	// Generate paths
-	basePath := fmt.Sprintf("videos/%s_%s/%s_%s/%s",
-		safeSeasonYear,
-		safeCountry,
-		safeSeasonName,
-		safeGender,
-		matchID.String())
+	basePath := video.BuildMatchBasePath(season, matchID)
 
 	logger.Info("Upload path",
 		zap.String("rawKey", basePath),
 		zap.String("compressedKey", basePath))
 
-	rawKey := fmt.Sprintf("%s/%s/%s%s",
-		basePath,
-		video.RawVideoFolder,
-		uuid.New().String(),
-		filepath.Ext(fileHeader.Filename))
-
-	compressedKey := fmt.Sprintf("%s/%s/%s.mp4",
-		basePath,
-		video.CompressedFolder,
-		uuid.New().String())
+	rawKey := video.BuildRawVideoKey(basePath, filepath.Ext(fileHeader.Filename))
+	compressedKey := video.BuildCompressedVideoKey(basePath)
 
-	// Upload raw video to S3
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, file); err != nil {
+	// Stream the raw video to storage; the tag lets lifecycle rules target raw files
+	err = s.store.Put(rawKey, file, storagePkg.PutOptions{
+		ContentType: contentType,
+		Tagging:     "storage=raw",
+	})
+	if err != nil {
 		return "", err
 	}
 
-	_, err = storagePkg.UploadBytesToS3(buf.Bytes(), rawKey, fileHeader.Header.Get("Content-Type"))
+	matchVideo, err := attachMatchVideo(s.matchVideoRepo, s.matchRepo, s.artifactRepo, match, angle, rawKey, compressedKey)
 	if err != nil {
 		return "", err
 	}
 
 	// Create and enqueue processing job
 	job := &video.VideoProcessingJob{
-		MatchID:   matchID.String(),
-		InputKey:  rawKey,
-		OutputKey: compressedKey,
+		MatchID:      matchID.String(),
+		MatchVideoID: matchVideo.ID.String(),
+		InputKey:     rawKey,
+		OutputKey:    compressedKey,
 	}
 
 	if err := s.videoQueue.EnqueueVideo(job); err != nil {
@@ -336,21 +348,12 @@ func (s *MatchServiceImpl) UploadMatchVideo(
 			zap.Error(err))
 	}
 
-	// build CloudFront compressed video URL
-	compressedURL := fmt.Sprintf("https://%s/%s", os.Getenv("VIDEO_CLOUDFRONT_DOMAIN"), compressedKey)
-
-	// Save the compressed URL instead of raw URL
-	match.VideoURL = compressedURL
-	if err := s.matchRepo.Update(match); err != nil {
-		return "", err
-	}
-
-	return compressedURL, nil
+	return fullMediaAccess.videoURL(matchVideo.VideoURL), nil
 }
 
 // UploadMatchScout handles uploading and processing a match scout file
 func (s *MatchServiceImpl) UploadMatchScout(matchID uuid.UUID,
-	file io.Reader,
+	file io.ReadSeeker,
 	fileHeader *multipart.FileHeader,
 ) (string, error) {
 	match, err := s.matchRepo.GetByID(matchID)
@@ -359,7 +362,12 @@ func (s *MatchServiceImpl) UploadMatchScout(matchID uuid.UUID,
 	}
 
 	if filepath.Ext(fileHeader.Filename) != ".dvw" {
-		return "", errors.New("invalid file type: only .dvw supported")
+		return "", errors.New(constants.ErrInvalidFileType)
+	}
+
+	contentType, err := upload.ValidateContent(upload.MatchScout, file, fileHeader.Size)
+	if err != nil {
+		return "", err
 	}
 
 	// Read file into memory
@@ -368,11 +376,10 @@ func (s *MatchServiceImpl) UploadMatchScout(matchID uuid.UUID,
 		return "", err
 	}
 
-	// Upload original .dvw file to S3
-	s3InputKey := fmt.Sprintf("scouts/%s.dvw", matchID.String())
-	contentType := fileHeader.Header.Get("Content-Type")
+	// Upload original .dvw file
+	s3InputKey := scoutPkg.BuildSourceKey(matchID)
 
-	_, err = storagePkg.UploadBytesToS3(buf.Bytes(), s3InputKey, contentType)
+	err = s.store.Put(s3InputKey, bytes.NewReader(buf.Bytes()), storagePkg.PutOptions{ContentType: contentType})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload .dvw file: %w", err)
 	}
@@ -389,32 +396,70 @@ func (s *MatchServiceImpl) UploadMatchScout(matchID uuid.UUID,
 		return "", fmt.Errorf("failed to marshal parsed data: %w", err)
 	}
 
-	// Upload parsed JSON to S3 using the scout CloudFront domain
-	s3OutputKey := fmt.Sprintf("scout-files/%s.json", matchID.String())
-	jsonURL, err := storagePkg.UploadBytesToS3(jsonBytes, s3OutputKey, "application/json")
+	// Upload parsed JSON, served from the scout CloudFront domain
+	s3OutputKey := scoutPkg.BuildParsedKey(matchID)
+	err = s.store.Put(s3OutputKey, bytes.NewReader(jsonBytes), storagePkg.PutOptions{ContentType: "application/json"})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload scout json: %w", err)
 	}
 
-	// Save JSON URL to DB
-	match.ScoutJSON = jsonURL
+	// Save the JSON key to DB
+	match.ScoutJSON = s3OutputKey
 	if err := s.matchRepo.Update(match); err != nil {
 		return "", err
 	}
 
-	return jsonURL, nil
+	s.enqueueScoutCondensedVideo(match.ID)
+
+	return fullMediaAccess.scoutURL(s3OutputKey), nil
 }
 
-// Helper function to fetch JSON from S3
-func fetchJSONFromS3(url string) (map[string]interface{}, error) {
-	if url == "" {
-		return nil, nil
+// enqueueScoutCondensedVideo recuts the condensed video of the reference angle from fresh scout timecodes
+func (s *MatchServiceImpl) enqueueScoutCondensedVideo(matchID uuid.UUID) {
+	matchVideos, err := s.matchVideoRepo.GetByMatchID(matchID)
+	if err != nil || len(matchVideos) == 0 {
+		return
 	}
 
-	// Use the existing HTTP utility to fetch and parse JSON
-	jsonData, err := httpPkg.FetchJSONFromS3(url)
+	reference := &matchVideos[0]
+	if !reference.IsReference || reference.Status != video.StatusCompleted {
+		return
+	}
+
+	artifacts := loadArtifacts(s.artifactRepo, []uuid.UUID{reference.ID})[reference.ID]
+	inputKey := video.BuildBestRenditionKey(reference.OutputKey, readyRenditionFormats(artifacts))
+	if err := s.videoQueue.EnqueueCondensedVideo(reference, inputKey); err != nil {
+		logger.Error("Failed to enqueue condensed video",
+			zap.String("match_id", matchID.String()),
+			zap.Error(err))
+	}
+}
+
+// GetScoutMetadata extracts the match summary from the parsed scout file
+func (s *MatchServiceImpl) GetScoutMetadata(matchID, viewerID uuid.UUID) (*dto.ScoutMetadataResponse, error) {
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchNotFound)
+	}
+	if !resolveMediaAccess(s.userRepo, viewerID).scout {
+		return nil, errors.New(constants.ErrMediaAccessDenied)
+	}
+	if match.ScoutJSON == "" {
+		return nil, errors.New(constants.ErrScoutNotFound)
+	}
+
+	jsonData, err := s.fetchScoutJSON(match.ScoutJSON)
+	if err != nil {
+		return nil, err
+	}
+	return scoutPkg.ExtractScoutMetadata(jsonData)
+}
+
+// fetchScoutJSON reads a parsed scout file from storage
+func (s *MatchServiceImpl) fetchScoutJSON(key string) (map[string]interface{}, error) {
+	jsonData, err := storagePkg.GetJSON(s.store, key)
 	if err != nil {
-		logger.Error("Failed to fetch JSON from S3", zap.Error(err), zap.String("url", url))
+		logger.Error("Failed to fetch scout JSON", zap.Error(err), zap.String("key", key))
 		return nil, err
 	}
 
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
+	"go-gin-starter/pkg/cdn"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// videoAnglePattern restricts angle labels to something safe to show and to use in URLs
+var videoAnglePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// MatchVideoService defines the interface for managing the camera angles of a match
+type MatchVideoService interface {
+	GetMatchVideos(matchID, viewerID uuid.UUID) ([]dto.MatchVideoResponse, error)
+	GetMatchPlayback(matchID, viewerID uuid.UUID) (*dto.MatchPlaybackResponse, error)
+	UpdateMatchVideo(matchID, videoID uuid.UUID, input *dto.UpdateMatchVideoInput) (*dto.MatchVideoResponse, error)
+	DeleteMatchVideo(matchID, videoID uuid.UUID) error
+}
+
+// MatchVideoServiceImpl implements MatchVideoService
+type MatchVideoServiceImpl struct {
+	matchVideoRepo repositories.MatchVideoRepository
+	matchRepo      repositories.MatchRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewMatchVideoService creates a new instance of MatchVideoService
+func NewMatchVideoService(
+	matchVideoRepo repositories.MatchVideoRepository,
+	matchRepo repositories.MatchRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	userRepo repositories.UserRepository,
+) MatchVideoService {
+	return &MatchVideoServiceImpl{
+		matchVideoRepo: matchVideoRepo,
+		matchRepo:      matchRepo,
+		artifactRepo:   artifactRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// GetMatchVideos lists every angle of a match, reference angle first.
+// URLs are signed for viewers allowed to watch and left empty for everyone else.
+func (s *MatchVideoServiceImpl) GetMatchVideos(matchID, viewerID uuid.UUID) ([]dto.MatchVideoResponse, error) {
+	if _, err := s.matchRepo.GetByID(matchID); err != nil {
+		return nil, errors.New(constants.ErrMatchNotFound)
+	}
+
+	matchVideos, err := s.matchVideoRepo.GetByMatchID(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := buildMatchVideoResponses(s.artifactRepo, matchVideos)
+	resolveMediaAccess(s.userRepo, viewerID).signMatchVideos(videos)
+	return videos, nil
+}
+
+// GetMatchPlayback issues signed URLs for every angle and the scout file of a match,
+// plus the folder signed cookies should cover so relative storyboard sprites load too
+func (s *MatchVideoServiceImpl) GetMatchPlayback(matchID, viewerID uuid.UUID) (*dto.MatchPlaybackResponse, error) {
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchNotFound)
+	}
+
+	access := resolveMediaAccess(s.userRepo, viewerID)
+	if !access.video {
+		return nil, errors.New(constants.ErrMediaAccessDenied)
+	}
+
+	matchVideos, err := s.matchVideoRepo.GetByMatchID(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := buildMatchVideoResponses(s.artifactRepo, matchVideos)
+	access.signMatchVideos(videos)
+
+	response := &dto.MatchPlaybackResponse{
+		MatchID:      match.ID,
+		Videos:       videos,
+		ScoutJSONURL: access.scoutURL(match.ScoutJSON),
+		ExpiresAt:    cdn.ExpiresAt(),
+	}
+	if len(matchVideos) > 0 && matchVideos[0].OutputKey != "" {
+		response.CookieFolder = matchVideoFolder(matchVideos[0].OutputKey)
+	}
+	return response, nil
+}
+
+// UpdateMatchVideo renames an angle, changes its sync offset or makes it the reference
+func (s *MatchVideoServiceImpl) UpdateMatchVideo(matchID, videoID uuid.UUID, input *dto.UpdateMatchVideoInput) (*dto.MatchVideoResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Angle != nil {
+		angle, err := normalizeVideoAngle(*input.Angle)
+		if err != nil {
+			return nil, err
+		}
+		if angle != matchVideo.Angle {
+			if _, err := s.matchVideoRepo.GetByMatchAndAngle(matchID, angle); err == nil {
+				return nil, errors.New(constants.ErrDuplicateVideoAngle)
+			}
+			matchVideo.Angle = angle
+		}
+	}
+
+	if input.IsReference != nil && *input.IsReference && !matchVideo.IsReference {
+		if err := s.promoteToReference(matchVideo); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.OffsetMs != nil {
+		if matchVideo.IsReference && *input.OffsetMs != 0 {
+			return nil, errors.New(constants.ErrReferenceAngleOffset)
+		}
+		matchVideo.OffsetMs = *input.OffsetMs
+	}
+
+	if err := s.matchVideoRepo.Update(matchVideo); err != nil {
+		return nil, err
+	}
+
+	artifacts := loadArtifacts(s.artifactRepo, []uuid.UUID{matchVideo.ID})
+	response := buildMatchVideoResponse(matchVideo, artifacts[matchVideo.ID])
+	fullMediaAccess.signMatchVideo(&response)
+	return &response, nil
+}
+
+// DeleteMatchVideo removes an angle; if it was the reference, the oldest remaining angle takes over
+func (s *MatchVideoServiceImpl) DeleteMatchVideo(matchID, videoID uuid.UUID) error {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.matchVideoRepo.Delete(matchVideo.ID); err != nil {
+		return err
+	}
+
+	if !matchVideo.IsReference {
+		return nil
+	}
+
+	remaining, err := s.matchVideoRepo.GetByMatchID(matchID)
+	if err != nil {
+		return err
+	}
+
+	if len(remaining) == 0 {
+		match, err := s.matchRepo.GetByID(matchID)
+		if err != nil {
+			return errors.New(constants.ErrMatchNotFound)
+		}
+		match.VideoURL = ""
+		match.ThumbnailURL = ""
+		return s.matchRepo.Update(match)
+	}
+
+	next := &remaining[0]
+	if err := s.promoteToReference(next); err != nil {
+		return err
+	}
+	return s.matchVideoRepo.Update(next)
+}
+
+// getMatchVideo loads an angle and checks that it belongs to the match
+func (s *MatchVideoServiceImpl) getMatchVideo(matchID, videoID uuid.UUID) (*models.MatchVideo, error) {
+	matchVideo, err := s.matchVideoRepo.GetByID(videoID)
+	if err != nil || matchVideo.MatchID != matchID {
+		return nil, errors.New(constants.ErrMatchVideoNotFound)
+	}
+	return matchVideo, nil
+}
+
+// promoteToReference makes the given angle the reference and re-bases every other angle's
+// offset so positions stay in sync. The caller persists the promoted angle itself.
+func (s *MatchVideoServiceImpl) promoteToReference(matchVideo *models.MatchVideo) error {
+	siblings, err := s.matchVideoRepo.GetByMatchID(matchVideo.MatchID)
+	if err != nil {
+		return err
+	}
+
+	shift := matchVideo.OffsetMs
+	for i := range siblings {
+		sibling := &siblings[i]
+		if sibling.ID == matchVideo.ID {
+			continue
+		}
+
+		sibling.IsReference = false
+		sibling.OffsetMs -= shift
+		if err := s.matchVideoRepo.Update(sibling); err != nil {
+			return err
+		}
+	}
+
+	matchVideo.IsReference = true
+	matchVideo.OffsetMs = 0
+
+	match, err := s.matchRepo.GetByID(matchVideo.MatchID)
+	if err != nil {
+		return errors.New(constants.ErrMatchNotFound)
+	}
+	match.VideoURL = matchVideo.VideoURL
+	match.ThumbnailURL = matchVideo.ThumbnailURL
+	return s.matchRepo.Update(match)
+}
+
+// attachMatchVideo records a freshly uploaded raw video for the given angle, ready to be processed.
+// Re-uploading an existing angle replaces its video; the first angle of a match becomes the reference.
+func attachMatchVideo(
+	matchVideoRepo repositories.MatchVideoRepository,
+	matchRepo repositories.MatchRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	match *models.Match,
+	angle, rawKey, outputKey string,
+) (*models.MatchVideo, error) {
+	matchVideo, err := matchVideoRepo.GetByMatchAndAngle(match.ID, angle)
+	if err != nil {
+		existing, err := matchVideoRepo.GetByMatchID(match.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		matchVideo = &models.MatchVideo{
+			MatchID:     match.ID,
+			Angle:       angle,
+			IsReference: len(existing) == 0,
+		}
+	}
+
+	matchVideo.RawKey = rawKey
+	matchVideo.OutputKey = outputKey
+	matchVideo.VideoURL = outputKey
+	matchVideo.ThumbnailURL = ""
+	matchVideo.StoryboardURL = ""
+	matchVideo.SpriteURLs = nil
+	matchVideo.Status = video.StatusPending
+	matchVideo.Error = ""
+	matchVideo.Metadata = models.VideoMetadata{}
+	matchVideo.RawStatus = models.RawFileStored
+	matchVideo.RawStatusReason = ""
+	matchVideo.RawStatusAt = nil
+
+	if matchVideo.ID == uuid.Nil {
+		err = matchVideoRepo.Create(matchVideo)
+	} else {
+		// Artifacts of the replaced video must not be served for the new one
+		if err := artifactRepo.DeleteByMatchVideoID(matchVideo.ID); err != nil {
+			return nil, err
+		}
+		err = matchVideoRepo.Update(matchVideo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if matchVideo.IsReference {
+		match.VideoURL = outputKey
+		match.ThumbnailURL = ""
+		if err := matchRepo.Update(match); err != nil {
+			return nil, err
+		}
+	}
+
+	return matchVideo, nil
+}
+
+// normalizeVideoAngle lowercases an angle label, falling back to the default angle when empty
+func normalizeVideoAngle(angle string) (string, error) {
+	angle = strings.ToLower(strings.TrimSpace(angle))
+	if angle == "" {
+		return constants.DefaultVideoAngle, nil
+	}
+
+	if len(angle) > constants.MaxVideoAngleLen || !videoAnglePattern.MatchString(angle) {
+		return "", errors.New(constants.ErrInvalidVideoAngle)
+	}
+	return angle, nil
+}
+
+// loadArtifacts fetches the recorded artifacts of several match videos, grouped by match video
+func loadArtifacts(artifactRepo repositories.VideoArtifactRepository, matchVideoIDs []uuid.UUID) map[uuid.UUID][]models.VideoArtifact {
+	grouped := make(map[uuid.UUID][]models.VideoArtifact, len(matchVideoIDs))
+
+	artifacts, err := artifactRepo.GetByMatchVideoIDs(matchVideoIDs)
+	if err != nil {
+		logger.Error("Failed to fetch video artifacts", zap.Error(err))
+		return grouped
+	}
+
+	for _, artifact := range artifacts {
+		grouped[artifact.MatchVideoID] = append(grouped[artifact.MatchVideoID], artifact)
+	}
+	return grouped
+}
+
+// buildRenditionURLs lists the renditions that were actually produced and uploaded
+func buildRenditionURLs(artifacts []models.VideoArtifact) map[string]string {
+	urls := make(map[string]string)
+	for _, artifact := range artifacts {
+		if artifact.Kind == models.ArtifactRendition && artifact.Status == models.ArtifactReady {
+			urls[artifact.Label] = assets.URL(artifact.S3Key)
+		}
+	}
+	return urls
+}
+
+// findArtifactURL returns the URL of the first ready artifact of a kind, or an empty string
+func findArtifactURL(artifacts []models.VideoArtifact, kind models.VideoArtifactKindEnum) string {
+	for _, artifact := range artifacts {
+		if artifact.Kind == kind && artifact.Status == models.ArtifactReady {
+			return assets.URL(artifact.S3Key)
+		}
+	}
+	return ""
+}
+
+// buildCondensedVideoResponse describes the rallies-only rendition, or nil if none was requested
+func buildCondensedVideoResponse(matchVideo *models.MatchVideo, artifacts []models.VideoArtifact) *dto.CondensedVideoResponse {
+	if matchVideo.CondensedStatus == "" {
+		return nil
+	}
+
+	response := &dto.CondensedVideoResponse{
+		Status: matchVideo.CondensedStatus,
+		Error:  matchVideo.CondensedError,
+		URL:    findArtifactURL(artifacts, models.ArtifactCondensed),
+	}
+	for _, artifact := range artifacts {
+		if artifact.Kind != models.ArtifactChapters || artifact.Status != models.ArtifactReady {
+			continue
+		}
+		switch artifact.Label {
+		case "vtt":
+			response.ChaptersURL = assets.URL(artifact.S3Key)
+		case "json":
+			response.ChapterMapURL = assets.URL(artifact.S3Key)
+		}
+	}
+	return response
+}
+
+// readyRenditionFormats lists the formats whose rendition was produced and uploaded
+func readyRenditionFormats(artifacts []models.VideoArtifact) []string {
+	var formats []string
+	for _, artifact := range artifacts {
+		if artifact.Kind == models.ArtifactRendition && artifact.Status == models.ArtifactReady {
+			formats = append(formats, artifact.Label)
+		}
+	}
+	return formats
+}
+
+// buildMatchVideoResponse maps a camera angle and its artifacts to the DTO
+func buildMatchVideoResponse(matchVideo *models.MatchVideo, artifacts []models.VideoArtifact) dto.MatchVideoResponse {
+	return dto.MatchVideoResponse{
+		ID:             matchVideo.ID,
+		MatchID:        matchVideo.MatchID,
+		Angle:          matchVideo.Angle,
+		IsReference:    matchVideo.IsReference,
+		OffsetMs:       matchVideo.OffsetMs,
+		VideoURL:       assets.URL(matchVideo.VideoURL),
+		VideoQualities: buildRenditionURLs(artifacts),
+		ManifestURL:    findArtifactURL(artifacts, models.ArtifactManifest),
+		Status:         matchVideo.Status,
+		Error:          matchVideo.Error,
+		Metadata:       buildVideoMetadataResponse(&matchVideo.Metadata),
+		ThumbnailURL:   assets.URL(matchVideo.ThumbnailURL),
+		StoryboardURL:  assets.URL(matchVideo.StoryboardURL),
+		SpriteURLs:     assets.URLs(matchVideo.SpriteURLs),
+		Condensed:      buildCondensedVideoResponse(matchVideo, artifacts),
+		CreatedAt:      matchVideo.CreatedAt,
+		UpdatedAt:      matchVideo.UpdatedAt,
+	}
+}
+
+// buildMatchVideoResponses maps a list of camera angles to DTOs, loading their artifacts in one query
+func buildMatchVideoResponses(artifactRepo repositories.VideoArtifactRepository, matchVideos []models.MatchVideo) []dto.MatchVideoResponse {
+	ids := make([]uuid.UUID, 0, len(matchVideos))
+	for _, matchVideo := range matchVideos {
+		ids = append(ids, matchVideo.ID)
+	}
+	artifacts := loadArtifacts(artifactRepo, ids)
+
+	responses := make([]dto.MatchVideoResponse, 0, len(matchVideos))
+	for i := range matchVideos {
+		responses = append(responses, buildMatchVideoResponse(&matchVideos[i], artifacts[matchVideos[i].ID]))
+	}
+	return responses
+}
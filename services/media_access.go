@@ -0,0 +1,113 @@
+package services
+
+import (
+	"path"
+	"strings"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/cdn"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+)
+
+// mediaAccess records which protected assets a viewer may receive signed URLs for
+type mediaAccess struct {
+	video bool
+	scout bool
+}
+
+// fullMediaAccess is used for responses on routes that already require an upload or manage permission
+var fullMediaAccess = mediaAccess{video: true, scout: true}
+
+// resolveMediaAccess checks the viewer's permissions for match videos and scout files.
+// Watching a match does not grant its scout data; that takes view_scout_data or a role
+// that uploads or manages it. Access is global, like every permission: users belong to
+// no club or team that could narrow it.
+func resolveMediaAccess(userRepo repositories.UserRepository, viewerID uuid.UUID) mediaAccess {
+	viewer, err := userRepo.FindByID(viewerID)
+	if err != nil {
+		return mediaAccess{}
+	}
+
+	return mediaAccess{
+		video: hasAnyPermission(viewer, "view_match", "manage_matches", "upload_video"),
+		scout: hasAnyPermission(viewer, "view_scout_data", "manage_matches", "upload_scout"),
+	}
+}
+
+// hasAnyPermission reports whether the user holds at least one of the permissions
+func hasAnyPermission(user *models.User, permissions ...string) bool {
+	for _, permission := range permissions {
+		if authPkg.HasPermission(user, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// videoURL renders and signs a stored video reference, or hides it if the viewer may not watch
+func (a mediaAccess) videoURL(ref string) string {
+	if !a.video {
+		return ""
+	}
+	return cdn.SignURL(assets.URL(ref))
+}
+
+// scoutURL renders and signs a stored scout file reference, or hides it if the viewer may not read scout data
+func (a mediaAccess) scoutURL(ref string) string {
+	if !a.scout {
+		return ""
+	}
+	return cdn.SignURL(assets.URL(ref))
+}
+
+// signMatchVideo signs every URL of an angle in place, or strips them if the viewer may not watch
+func (a mediaAccess) signMatchVideo(response *dto.MatchVideoResponse) {
+	response.VideoURL = a.videoURL(response.VideoURL)
+	response.ManifestURL = a.videoURL(response.ManifestURL)
+	response.ThumbnailURL = a.videoURL(response.ThumbnailURL)
+	response.StoryboardURL = a.videoURL(response.StoryboardURL)
+
+	qualities := make(map[string]string, len(response.VideoQualities))
+	if a.video {
+		for format, rawURL := range response.VideoQualities {
+			qualities[format] = cdn.SignURL(rawURL)
+		}
+	}
+	response.VideoQualities = qualities
+
+	if a.video {
+		response.SpriteURLs = cdn.SignURLs(response.SpriteURLs)
+	} else {
+		response.SpriteURLs = nil
+	}
+
+	if response.Condensed != nil {
+		response.Condensed.URL = a.videoURL(response.Condensed.URL)
+		response.Condensed.ChaptersURL = a.videoURL(response.Condensed.ChaptersURL)
+		response.Condensed.ChapterMapURL = a.videoURL(response.Condensed.ChapterMapURL)
+	}
+}
+
+// signMatchVideos applies signMatchVideo to a list of angles
+func (a mediaAccess) signMatchVideos(responses []dto.MatchVideoResponse) {
+	for i := range responses {
+		a.signMatchVideo(&responses[i])
+	}
+}
+
+// matchVideoFolder returns the S3 folder holding every processed asset of an angle,
+// i.e. the match base path above compressed/ and thumbnails/
+func matchVideoFolder(outputKey string) string {
+	marker := "/" + video.CompressedFolder + "/"
+	index := strings.Index(outputKey, marker)
+	if index < 0 {
+		return path.Dir(outputKey)
+	}
+	return outputKey[:index]
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-starter/config"
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/mail"
+	"go-gin-starter/pkg/oidc"
+	"go-gin-starter/repositories"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// OIDCService defines the interface for signing in with OpenID Connect providers
+type OIDCService interface {
+	Providers() []dto.OIDCProviderResponse
+	Authorize(ctx context.Context, provider string) (*dto.OIDCAuthorizeResponse, error)
+	Callback(ctx context.Context, code, state string, client ClientInfo) (*dto.LoginResult, error)
+}
+
+// OIDCServiceImpl implements OIDCService
+type OIDCServiceImpl struct {
+	providers    *oidc.Registry
+	identityRepo repositories.IdentityRepository
+	authRepo     repositories.AuthRepository
+	userRepo     repositories.UserRepository
+	authService  AuthService
+}
+
+// NewOIDCService creates a new instance of OIDCService
+func NewOIDCService(providers *oidc.Registry, identityRepo repositories.IdentityRepository, authRepo repositories.AuthRepository, userRepo repositories.UserRepository, authService AuthService) OIDCService {
+	return &OIDCServiceImpl{
+		providers:    providers,
+		identityRepo: identityRepo,
+		authRepo:     authRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+	}
+}
+
+// Providers lists the configured identity providers
+func (s *OIDCServiceImpl) Providers() []dto.OIDCProviderResponse {
+	providers := make([]dto.OIDCProviderResponse, 0, len(s.providers.Names()))
+	for _, name := range s.providers.Names() {
+		providers = append(providers, dto.OIDCProviderResponse{Name: name})
+	}
+	return providers
+}
+
+// Authorize starts a login: it remembers state, nonce and PKCE verifier and returns the
+// provider URL to send the user to
+func (s *OIDCServiceImpl) Authorize(ctx context.Context, providerName string) (*dto.OIDCAuthorizeResponse, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return nil, errors.New(constants.ErrUnknownProvider)
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, config.OIDCRedirectURL, state, nonce, challenge)
+	if err != nil {
+		logger.Error("OIDC discovery failed", zap.String("provider", providerName), zap.Error(err))
+		return nil, errors.New(constants.ErrProviderUnavailable)
+	}
+
+	err = s.identityRepo.CreateLoginState(&models.OIDCLoginState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(constants.OIDCLoginTTL),
+	})
+	if err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	return &dto.OIDCAuthorizeResponse{AuthorizationURL: authURL}, nil
+}
+
+// Callback completes a login with the code the provider sent back. The identity is linked
+// to a user by provider and subject; the first login links it by verified email address
+// or creates a user with the default role.
+func (s *OIDCServiceImpl) Callback(ctx context.Context, code, state string, client ClientInfo) (*dto.LoginResult, error) {
+	pending, err := s.identityRepo.ConsumeLoginState(state)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(constants.ErrInvalidLoginState)
+		}
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	provider, err := s.providers.Get(pending.Provider)
+	if err != nil {
+		return nil, errors.New(constants.ErrUnknownProvider)
+	}
+
+	claims, err := provider.Exchange(ctx, config.OIDCRedirectURL, code, pending.CodeVerifier)
+	if err != nil {
+		logger.Warn("OIDC code exchange failed", zap.String("provider", pending.Provider), zap.Error(err))
+		return nil, errors.New(constants.ErrProviderLoginFailed)
+	}
+	if claims.Nonce != pending.Nonce {
+		logger.Warn("OIDC nonce mismatch", zap.String("provider", pending.Provider))
+		return nil, errors.New(constants.ErrProviderLoginFailed)
+	}
+
+	user, err := s.resolveUser(pending.Provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.CompleteLogin(user, client)
+}
+
+// resolveUser finds or creates the user an ID token belongs to
+func (s *OIDCServiceImpl) resolveUser(providerName string, claims *oidc.IDTokenClaims) (*models.User, error) {
+	now := time.Now()
+
+	identity, err := s.identityRepo.FindIdentity(providerName, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, errors.New(constants.ErrUserNotFound)
+		}
+		if err := s.identityRepo.RecordLogin(identity.ID, claims.Email, now); err != nil {
+			logger.Error("Failed to record OIDC login", zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	// Linking by email is only safe if the provider vouches for the address
+	if claims.Email == "" || !bool(claims.EmailVerified) {
+		return nil, errors.New(constants.ErrProviderEmailMissing)
+	}
+
+	identity = &models.UserIdentity{
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}
+
+	user, err := s.identityRepo.FindUserByEmail(claims.Email)
+	if err == nil {
+		// Whoever registered the address without verifying it may not be its owner
+		if user.EmailVerifiedAt == nil {
+			return nil, errors.New(constants.ErrAccountUnverified)
+		}
+		identity.UserID = user.ID
+		if err := s.identityRepo.CreateIdentity(identity); err != nil {
+			return nil, errors.New(constants.ErrDatabase)
+		}
+		logger.Info("Linked OIDC identity to existing user",
+			zap.String("provider", providerName), zap.String("user_id", user.ID.String()))
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	return s.createUser(identity, claims)
+}
+
+// createUser creates the account of a first-time OIDC user. The password is random, so
+// the account has none until the user sets one through a password reset.
+func (s *OIDCServiceImpl) createUser(identity *models.UserIdentity, claims *oidc.IDTokenClaims) (*models.User, error) {
+	username, err := s.uniqueUsername(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := authPkg.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := authPkg.HashPassword(password)
+	if err != nil {
+		return nil, errors.New(constants.ErrPasswordHashFailed)
+	}
+
+	// Providers send BCP 47 tags such as "de-AT"; mail templates exist per language
+	locale, _, _ := strings.Cut(strings.ToLower(claims.Locale), "-")
+	if !mail.IsSupportedLocale(locale) {
+		locale = ""
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Username:        username,
+		Email:           claims.Email,
+		Password:        hashedPassword,
+		Role:            models.RoleEnum(config.OIDCDefaultRole),
+		Locale:          locale,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.identityRepo.CreateUserWithIdentity(user, identity); err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	logger.Info("Created user from OIDC login",
+		zap.String("provider", identity.Provider), zap.String("user_id", user.ID.String()))
+	return user, nil
+}
+
+// uniqueUsername derives a free username from the preferred username or the email address
+func (s *OIDCServiceImpl) uniqueUsername(claims *oidc.IDTokenClaims) (string, error) {
+	source := claims.PreferredUsername
+	if source == "" {
+		source, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(source) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	base := b.String()
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 15 {
+		base = base[:15]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 10; attempt++ {
+		if _, err := s.authRepo.GetUserByUsername(candidate); errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		} else if err != nil {
+			return "", errors.New(constants.ErrDatabase)
+		}
+		candidate = fmt.Sprintf("%s%04d", base, rand.IntN(10000))
+	}
+	return "", errors.New(constants.ErrUserAlreadyExists)
+}
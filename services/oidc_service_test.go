@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/pkg/oidc"
+	"go-gin-starter/pkg/oidc/oidctest"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	testClientID     = "volleymate"
+	testClientSecret = "mock-secret"
+)
+
+// memoryIdentityRepository keeps pending logins, identities and users in memory
+type memoryIdentityRepository struct {
+	states     map[string]*models.OIDCLoginState
+	identities []*models.UserIdentity
+	users      map[uuid.UUID]*models.User
+}
+
+func (r *memoryIdentityRepository) CreateLoginState(state *models.OIDCLoginState) error {
+	r.states[state.State] = state
+	return nil
+}
+
+func (r *memoryIdentityRepository) ConsumeLoginState(state string) (*models.OIDCLoginState, error) {
+	pending, ok := r.states[state]
+	delete(r.states, state)
+	if !ok || time.Now().After(pending.ExpiresAt) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return pending, nil
+}
+
+func (r *memoryIdentityRepository) FindIdentity(provider, subject string) (*models.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryIdentityRepository) FindUserByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryIdentityRepository) CreateIdentity(identity *models.UserIdentity) error {
+	identity.ID = uuid.New()
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryIdentityRepository) CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	user.ID = uuid.New()
+	r.users[user.ID] = user
+	identity.UserID = user.ID
+	return r.CreateIdentity(identity)
+}
+
+func (r *memoryIdentityRepository) RecordLogin(uuid.UUID, string, time.Time) error {
+	return nil
+}
+
+// memoryUserRepository serves FindByID from the identity repository's users
+type memoryUserRepository struct {
+	repositories.UserRepository
+	identities *memoryIdentityRepository
+}
+
+func (r *memoryUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	if user, ok := r.identities.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// memoryAuthRepository only answers the username lookups of new OIDC users
+type memoryAuthRepository struct {
+	repositories.AuthRepository
+	identities *memoryIdentityRepository
+}
+
+func (r *memoryAuthRepository) GetUserByUsername(username string) (*models.User, error) {
+	for _, user := range r.identities.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// recordingAuthService records whom the OIDC flow logged in instead of issuing tokens
+type recordingAuthService struct {
+	AuthService
+	loggedIn []*models.User
+}
+
+func (s *recordingAuthService) CompleteLogin(user *models.User, _ ClientInfo) (*dto.LoginResult, error) {
+	s.loggedIn = append(s.loggedIn, user)
+	return &dto.LoginResult{AccessToken: "access-" + user.ID.String()}, nil
+}
+
+// oidcHarness runs the OIDC service against a local mock issuer
+type oidcHarness struct {
+	issuer     *oidctest.Issuer
+	identities *memoryIdentityRepository
+	auth       *recordingAuthService
+	service    OIDCService
+}
+
+func newOIDCHarness(t *testing.T) *oidcHarness {
+	t.Helper()
+	logger.Init()
+	config.OIDCRedirectURL = "http://app.test/oidc/callback"
+	config.OIDCDefaultRole = string(models.RolePlayer)
+
+	h := &oidcHarness{
+		identities: &memoryIdentityRepository{
+			states: make(map[string]*models.OIDCLoginState),
+			users:  make(map[uuid.UUID]*models.User),
+		},
+		auth: &recordingAuthService{},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	issuer, err := oidctest.NewIssuer(server.URL, testClientID, testClientSecret)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	h.issuer = issuer
+
+	registry := oidc.NewRegistry([]config.OIDCProvider{{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+	}})
+	h.service = NewOIDCService(registry, h.identities,
+		&memoryAuthRepository{identities: h.identities},
+		&memoryUserRepository{identities: h.identities},
+		h.auth)
+	return h
+}
+
+// login runs Authorize, lets tamper change the authorization request the browser sends to
+// the issuer, follows the issuer's redirect and completes the login with the returned code
+func (h *oidcHarness) login(t *testing.T, tamper func(query url.Values)) (*dto.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authorize, err := h.service.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	authURL, err := url.Parse(authorize.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("parse authorization URL: %v", err)
+	}
+	if tamper != nil {
+		query := authURL.Query()
+		tamper(query)
+		authURL.RawQuery = query.Encode()
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL.String())
+	if err != nil {
+		t.Fatalf("authorization request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization request returned %d", resp.StatusCode)
+	}
+
+	redirect, err := resp.Location()
+	if err != nil {
+		t.Fatalf("authorization redirect: %v", err)
+	}
+	if !strings.HasPrefix(redirect.String(), config.OIDCRedirectURL) {
+		t.Fatalf("redirected to %s", redirect)
+	}
+	return h.service.Callback(ctx, redirect.Query().Get("code"), redirect.Query().Get("state"), ClientInfo{})
+}
+
+// addUser stores an account that registered with a password
+func (h *oidcHarness) addUser(email string, verified bool) *models.User {
+	user := &models.User{ID: uuid.New(), Username: "existing", Email: email, Role: models.RoleHeadCoach}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	h.identities.users[user.ID] = user
+	return user
+}
+
+func expectError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %s", err, want)
+	}
+}
+
+func TestOIDCCallbackFirstLoginCreatesUserWithDefaultRole(t *testing.T) {
+	h := newOIDCHarness(t)
+
+	result, err := h.login(t, func(query url.Values) { query.Set("login_hint", "New.Player@example.com") })
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if result.AccessToken == "" || len(h.auth.loggedIn) != 1 {
+		t.Fatalf("login was not completed: %+v", result)
+	}
+
+	user := h.auth.loggedIn[0]
+	if user.Role != models.RolePlayer {
+		t.Errorf("role = %s, want the default role %s", user.Role, models.RolePlayer)
+	}
+	if user.Email != "New.Player@example.com" || user.EmailVerifiedAt == nil {
+		t.Errorf("email = %s, verified at %v", user.Email, user.EmailVerifiedAt)
+	}
+	if user.Username != "new.player" {
+		t.Errorf("username = %s, want new.player", user.Username)
+	}
+	if len(h.identities.identities) != 1 || h.identities.identities[0].UserID != user.ID {
+		t.Fatalf("identity not linked to the new user: %+v", h.identities.identities)
+	}
+
+	// The second login finds the identity by subject and creates nothing new
+	if _, err := h.login(t, func(query url.Values) { query.Set("login_hint", "New.Player@example.com") }); err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if len(h.identities.users) != 1 || len(h.identities.identities) != 1 || h.auth.loggedIn[1].ID != user.ID {
+		t.Fatalf("second login created %d users and %d identities", len(h.identities.users), len(h.identities.identities))
+	}
+}
+
+func TestOIDCCallbackLinksVerifiedEmail(t *testing.T) {
+	h := newOIDCHarness(t)
+	existing := h.addUser("coach@example.com", true)
+
+	if _, err := h.login(t, func(query url.Values) { query.Set("login_hint", "coach@example.com") }); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if h.auth.loggedIn[0].ID != existing.ID || h.auth.loggedIn[0].Role != models.RoleHeadCoach {
+		t.Fatalf("logged in %+v, want the existing user", h.auth.loggedIn[0])
+	}
+	if len(h.identities.users) != 1 {
+		t.Fatalf("a new user was created next to the existing one")
+	}
+}
+
+func TestOIDCCallbackRefusesUnverifiedProviderEmail(t *testing.T) {
+	h := newOIDCHarness(t)
+	h.addUser("coach@example.com", true)
+
+	_, err := h.login(t, func(query url.Values) {
+		query.Set("login_hint", "coach@example.com")
+		query.Set("email_verified", "false")
+	})
+	expectError(t, err, constants.ErrProviderEmailMissing)
+	if len(h.identities.identities) != 0 || len(h.auth.loggedIn) != 0 {
+		t.Fatal("identity linked through an unverified email address")
+	}
+}
+
+func TestOIDCCallbackRefusesUnverifiedAccount(t *testing.T) {
+	h := newOIDCHarness(t)
+	h.addUser("coach@example.com", false)
+
+	_, err := h.login(t, func(query url.Values) { query.Set("login_hint", "coach@example.com") })
+	expectError(t, err, constants.ErrAccountUnverified)
+	if len(h.identities.identities) != 0 || len(h.auth.loggedIn) != 0 {
+		t.Fatal("identity linked to an account whose owner never verified the address")
+	}
+}
+
+func TestOIDCCallbackRefusesNonceMismatch(t *testing.T) {
+	h := newOIDCHarness(t)
+
+	_, err := h.login(t, func(query url.Values) { query.Set("nonce", "nonce-of-another-login") })
+	expectError(t, err, constants.ErrProviderLoginFailed)
+	if len(h.identities.users) != 0 || len(h.auth.loggedIn) != 0 {
+		t.Fatal("login completed with a foreign nonce")
+	}
+}
+
+func TestOIDCCallbackRefusesPKCEVerifierMismatch(t *testing.T) {
+	h := newOIDCHarness(t)
+
+	// The issuer binds the code to this challenge, which the stored verifier does not match
+	_, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE: %v", err)
+	}
+	_, err = h.login(t, func(query url.Values) { query.Set("code_challenge", challenge) })
+	expectError(t, err, constants.ErrProviderLoginFailed)
+	if len(h.auth.loggedIn) != 0 {
+		t.Fatal("login completed with a code bound to another verifier")
+	}
+}
+
+func TestOIDCCallbackRefusesTokensForOtherClients(t *testing.T) {
+	tests := []struct {
+		name            string
+		audience        []string
+		authorizedParty string
+		ok              bool
+	}{
+		{name: "other audience", audience: []string{"another-client"}},
+		{name: "several audiences without azp", audience: []string{testClientID, "another-client"}},
+		{name: "several audiences, azp of another client", audience: []string{testClientID, "another-client"}, authorizedParty: "another-client"},
+		{name: "single audience, azp of another client", audience: []string{testClientID}, authorizedParty: "another-client"},
+		{name: "single audience with our azp", audience: []string{testClientID}, authorizedParty: testClientID, ok: true},
+		{name: "several audiences with our azp", audience: []string{testClientID, "another-client"}, authorizedParty: testClientID, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newOIDCHarness(t)
+			h.issuer.Audience = tt.audience
+			h.issuer.AuthorizedParty = tt.authorizedParty
+
+			_, err := h.login(t, nil)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Callback: %v", err)
+				}
+				return
+			}
+			expectError(t, err, constants.ErrProviderLoginFailed)
+			if len(h.auth.loggedIn) != 0 {
+				t.Fatal("login completed with a token issued to another client")
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackRefusesUnknownState(t *testing.T) {
+	h := newOIDCHarness(t)
+
+	_, err := h.service.Callback(context.Background(), "any-code", "unknown-state", ClientInfo{})
+	expectError(t, err, constants.ErrInvalidLoginState)
+}
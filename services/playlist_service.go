@@ -0,0 +1,520 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/cdn"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+)
+
+// PlaylistService defines the interface for coach-curated clip playlists
+type PlaylistService interface {
+	CreatePlaylist(ownerID uuid.UUID, input *dto.CreatePlaylistInput) (*dto.PlaylistResponse, error)
+	GetPlaylists(userID uuid.UUID) ([]dto.PlaylistResponse, error)
+	GetPlaylist(id, userID uuid.UUID) (*dto.PlaylistResponse, error)
+	UpdatePlaylist(id, userID uuid.UUID, input *dto.UpdatePlaylistInput) (*dto.PlaylistResponse, error)
+	DeletePlaylist(id, userID uuid.UUID) error
+	AddItem(id, userID uuid.UUID, input *dto.PlaylistItemInput) (*dto.PlaylistResponse, error)
+	UpdateItem(id, itemID, userID uuid.UUID, input *dto.UpdatePlaylistItemInput) (*dto.PlaylistResponse, error)
+	RemoveItem(id, itemID, userID uuid.UUID) error
+	ReorderItems(id, userID uuid.UUID, input *dto.ReorderPlaylistItemsInput) (*dto.PlaylistResponse, error)
+	SharePlaylist(id, userID uuid.UUID, input *dto.SharePlaylistInput) (*dto.PlaylistResponse, error)
+	RecordView(id, itemID, userID uuid.UUID, input *dto.RecordPlaylistViewInput) error
+	GetViews(id, userID uuid.UUID) ([]dto.PlaylistViewResponse, error)
+}
+
+// PlaylistServiceImpl implements PlaylistService
+type PlaylistServiceImpl struct {
+	playlistRepo   repositories.PlaylistRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewPlaylistService creates a new instance of PlaylistService
+func NewPlaylistService(
+	playlistRepo repositories.PlaylistRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	userRepo repositories.UserRepository,
+) PlaylistService {
+	return &PlaylistServiceImpl{
+		playlistRepo:   playlistRepo,
+		matchVideoRepo: matchVideoRepo,
+		artifactRepo:   artifactRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// CreatePlaylist creates a playlist, optionally with an initial list of clips
+func (s *PlaylistServiceImpl) CreatePlaylist(ownerID uuid.UUID, input *dto.CreatePlaylistInput) (*dto.PlaylistResponse, error) {
+	playlist := &models.Playlist{
+		OwnerID:     ownerID,
+		Title:       input.Title,
+		Description: input.Description,
+	}
+
+	for position, itemInput := range input.Items {
+		item, err := s.buildItem(&itemInput, position)
+		if err != nil {
+			return nil, err
+		}
+		playlist.Items = append(playlist.Items, *item)
+	}
+
+	if err := s.playlistRepo.Create(playlist); err != nil {
+		return nil, err
+	}
+
+	return s.respondWithPlaylist(playlist.ID, ownerID)
+}
+
+// GetPlaylists lists the playlists the user owns or can watch, without their items
+func (s *PlaylistServiceImpl) GetPlaylists(userID uuid.UUID) ([]dto.PlaylistResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+
+	playlists, err := s.playlistRepo.GetAccessible(user.ID, user.Role == models.RolePlayer)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PlaylistResponse, 0, len(playlists))
+	for i := range playlists {
+		response := buildPlaylistSummary(&playlists[i])
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// GetPlaylist returns a playlist with playable clips if the user may watch it
+func (s *PlaylistServiceImpl) GetPlaylist(id, userID uuid.UUID) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getViewablePlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildPlaylistResponse(playlist, userID), nil
+}
+
+// UpdatePlaylist changes the title or description of an owned playlist
+func (s *PlaylistServiceImpl) UpdatePlaylist(id, userID uuid.UUID, input *dto.UpdatePlaylistInput) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		playlist.Title = *input.Title
+	}
+	if input.Description != nil {
+		playlist.Description = *input.Description
+	}
+
+	if err := s.playlistRepo.Update(playlist); err != nil {
+		return nil, err
+	}
+	return s.buildPlaylistResponse(playlist, userID), nil
+}
+
+// DeletePlaylist removes an owned playlist
+func (s *PlaylistServiceImpl) DeletePlaylist(id, userID uuid.UUID) error {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return err
+	}
+	return s.playlistRepo.Delete(playlist.ID)
+}
+
+// AddItem appends a clip to an owned playlist
+func (s *PlaylistServiceImpl) AddItem(id, userID uuid.UUID, input *dto.PlaylistItemInput) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.buildItem(input, len(playlist.Items))
+	if err != nil {
+		return nil, err
+	}
+	item.PlaylistID = playlist.ID
+
+	if err := s.playlistRepo.CreateItem(item); err != nil {
+		return nil, err
+	}
+	if err := s.playlistRepo.Update(playlist); err != nil {
+		return nil, err
+	}
+
+	return s.respondWithPlaylist(playlist.ID, userID)
+}
+
+// UpdateItem changes the segment or comment of a clip in an owned playlist
+func (s *PlaylistServiceImpl) UpdateItem(id, itemID, userID uuid.UUID, input *dto.UpdatePlaylistItemInput) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.playlistRepo.GetItemByID(itemID)
+	if err != nil || item.PlaylistID != playlist.ID {
+		return nil, errors.New(constants.ErrPlaylistItemNotFound)
+	}
+
+	if input.StartMs != nil {
+		item.StartMs = *input.StartMs
+	}
+	if input.EndMs != nil {
+		item.EndMs = *input.EndMs
+	}
+	if input.Comment != nil {
+		item.Comment = *input.Comment
+	}
+
+	matchVideo, err := s.matchVideoRepo.GetByID(item.MatchVideoID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchVideoNotFound)
+	}
+	if err := validateClipRange(item.StartMs, item.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	if err := s.playlistRepo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.respondWithPlaylist(playlist.ID, userID)
+}
+
+// RemoveItem deletes a clip from an owned playlist and closes the gap in positions
+func (s *PlaylistServiceImpl) RemoveItem(id, itemID, userID uuid.UUID) error {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return err
+	}
+
+	item, err := s.playlistRepo.GetItemByID(itemID)
+	if err != nil || item.PlaylistID != playlist.ID {
+		return errors.New(constants.ErrPlaylistItemNotFound)
+	}
+
+	if err := s.playlistRepo.DeleteItem(item.ID); err != nil {
+		return err
+	}
+
+	remaining := make([]uuid.UUID, 0, len(playlist.Items))
+	for _, existing := range playlist.Items {
+		if existing.ID != item.ID {
+			remaining = append(remaining, existing.ID)
+		}
+	}
+	return s.playlistRepo.UpdateItemPositions(playlist.ID, remaining)
+}
+
+// ReorderItems sets a new clip order; the list must contain every item exactly once
+func (s *PlaylistServiceImpl) ReorderItems(id, userID uuid.UUID, input *dto.ReorderPlaylistItemsInput) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.ItemIDs) != len(playlist.Items) {
+		return nil, errors.New(constants.ErrInvalidItemOrder)
+	}
+
+	known := make(map[uuid.UUID]bool, len(playlist.Items))
+	for _, item := range playlist.Items {
+		known[item.ID] = true
+	}
+	for _, itemID := range input.ItemIDs {
+		if !known[itemID] {
+			return nil, errors.New(constants.ErrInvalidItemOrder)
+		}
+		delete(known, itemID)
+	}
+
+	if err := s.playlistRepo.UpdateItemPositions(playlist.ID, input.ItemIDs); err != nil {
+		return nil, err
+	}
+
+	return s.respondWithPlaylist(playlist.ID, userID)
+}
+
+// SharePlaylist replaces who an owned playlist is shared with
+func (s *PlaylistServiceImpl) SharePlaylist(id, userID uuid.UUID, input *dto.SharePlaylistInput) (*dto.PlaylistResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(input.UserIDs))
+	userIDs := make([]uuid.UUID, 0, len(input.UserIDs))
+	for _, sharedWith := range input.UserIDs {
+		if seen[sharedWith] || sharedWith == playlist.OwnerID {
+			continue
+		}
+		if _, err := s.userRepo.FindByID(sharedWith); err != nil {
+			return nil, errors.New(constants.ErrUserNotFound)
+		}
+		seen[sharedWith] = true
+		userIDs = append(userIDs, sharedWith)
+	}
+
+	if err := s.playlistRepo.ReplaceShares(playlist.ID, userIDs); err != nil {
+		return nil, err
+	}
+
+	playlist.SharedWithPlayers = input.AllPlayers
+	if err := s.playlistRepo.Update(playlist); err != nil {
+		return nil, err
+	}
+
+	return s.respondWithPlaylist(playlist.ID, userID)
+}
+
+// RecordView stores viewing progress of a clip for the current user
+func (s *PlaylistServiceImpl) RecordView(id, itemID, userID uuid.UUID, input *dto.RecordPlaylistViewInput) error {
+	playlist, err := s.getViewablePlaylist(id, userID)
+	if err != nil {
+		return err
+	}
+
+	var item *models.PlaylistItem
+	for i := range playlist.Items {
+		if playlist.Items[i].ID == itemID {
+			item = &playlist.Items[i]
+			break
+		}
+	}
+	if item == nil {
+		return errors.New(constants.ErrPlaylistItemNotFound)
+	}
+
+	watchedMs := input.WatchedMs
+	if clipLength := item.EndMs - item.StartMs; watchedMs > clipLength {
+		watchedMs = clipLength
+	}
+
+	return s.playlistRepo.RecordView(&models.PlaylistView{
+		PlaylistID:     playlist.ID,
+		PlaylistItemID: item.ID,
+		UserID:         userID,
+		WatchedMs:      watchedMs,
+		Completed:      input.Completed,
+	})
+}
+
+// GetViews reports who watched which clip of an owned playlist
+func (s *PlaylistServiceImpl) GetViews(id, userID uuid.UUID) ([]dto.PlaylistViewResponse, error) {
+	playlist, err := s.getOwnedPlaylist(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := s.playlistRepo.GetViews(playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make(map[uuid.UUID]string)
+	responses := make([]dto.PlaylistViewResponse, 0, len(views))
+	for _, view := range views {
+		username, ok := usernames[view.UserID]
+		if !ok {
+			if viewer, err := s.userRepo.FindByID(view.UserID); err == nil {
+				username = viewer.Username
+			}
+			usernames[view.UserID] = username
+		}
+
+		responses = append(responses, dto.PlaylistViewResponse{
+			UserID:         view.UserID,
+			Username:       username,
+			PlaylistItemID: view.PlaylistItemID,
+			WatchedMs:      view.WatchedMs,
+			Completed:      view.Completed,
+			ViewCount:      view.ViewCount,
+			LastViewedAt:   view.LastViewedAt,
+		})
+	}
+	return responses, nil
+}
+
+// getOwnedPlaylist loads a playlist the user may modify
+func (s *PlaylistServiceImpl) getOwnedPlaylist(id, userID uuid.UUID) (*models.Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New(constants.ErrPlaylistNotFound)
+	}
+	if playlist.OwnerID != userID {
+		return nil, errors.New(constants.ErrForbidden)
+	}
+	return playlist, nil
+}
+
+// getViewablePlaylist loads a playlist the user owns or that was shared with them
+func (s *PlaylistServiceImpl) getViewablePlaylist(id, userID uuid.UUID) (*models.Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New(constants.ErrPlaylistNotFound)
+	}
+	if playlist.OwnerID == userID {
+		return playlist, nil
+	}
+
+	for _, share := range playlist.Shares {
+		if share.UserID == userID {
+			return playlist, nil
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+	if (playlist.SharedWithPlayers && user.Role == models.RolePlayer) || authPkg.HasPermission(user, "all") {
+		return playlist, nil
+	}
+
+	// Don't reveal playlists the user was not given access to
+	return nil, errors.New(constants.ErrPlaylistNotFound)
+}
+
+// buildItem validates a clip against its video and prepares it for storage
+func (s *PlaylistServiceImpl) buildItem(input *dto.PlaylistItemInput, position int) (*models.PlaylistItem, error) {
+	matchVideo, err := s.matchVideoRepo.GetByID(input.MatchVideoID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchVideoNotFound)
+	}
+
+	if err := validateClipRange(input.StartMs, input.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	return &models.PlaylistItem{
+		MatchID:      matchVideo.MatchID,
+		MatchVideoID: matchVideo.ID,
+		Position:     position,
+		StartMs:      input.StartMs,
+		EndMs:        input.EndMs,
+		Comment:      input.Comment,
+	}, nil
+}
+
+// respondWithPlaylist reloads a playlist after a change and maps it for the given user
+func (s *PlaylistServiceImpl) respondWithPlaylist(id, userID uuid.UUID) (*dto.PlaylistResponse, error) {
+	playlist, err := s.playlistRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New(constants.ErrPlaylistNotFound)
+	}
+	return s.buildPlaylistResponse(playlist, userID), nil
+}
+
+// buildPlaylistResponse maps a playlist with its clips; shares are only listed for the owner
+func (s *PlaylistServiceImpl) buildPlaylistResponse(playlist *models.Playlist, userID uuid.UUID) *dto.PlaylistResponse {
+	response := buildPlaylistSummary(playlist)
+
+	matchVideos := make(map[uuid.UUID]*models.MatchVideo)
+	for _, item := range playlist.Items {
+		if _, ok := matchVideos[item.MatchVideoID]; !ok {
+			matchVideos[item.MatchVideoID], _ = s.matchVideoRepo.GetByID(item.MatchVideoID)
+		}
+	}
+
+	matchVideoIDs := make([]uuid.UUID, 0, len(matchVideos))
+	for id := range matchVideos {
+		matchVideoIDs = append(matchVideoIDs, id)
+	}
+	artifacts := loadArtifacts(s.artifactRepo, matchVideoIDs)
+
+	response.Items = make([]dto.PlaylistItemResponse, 0, len(playlist.Items))
+	for _, item := range playlist.Items {
+		matchVideo := matchVideos[item.MatchVideoID]
+
+		itemResponse := dto.PlaylistItemResponse{
+			ID:           item.ID,
+			MatchID:      item.MatchID,
+			MatchVideoID: item.MatchVideoID,
+			Position:     item.Position,
+			StartMs:      item.StartMs,
+			EndMs:        item.EndMs,
+			Comment:      item.Comment,
+		}
+
+		// The angle may have been deleted since; keep the clip but without playback URLs
+		if matchVideo != nil {
+			itemResponse.Angle = matchVideo.Angle
+			itemResponse.ThumbnailURL = cdn.SignURL(assets.URL(matchVideo.ThumbnailURL))
+			itemResponse.PlaybackURLs = buildClipURLs(artifacts[matchVideo.ID], item.StartMs, item.EndMs)
+		}
+
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	if playlist.OwnerID == userID {
+		response.SharedWith = make([]uuid.UUID, 0, len(playlist.Shares))
+		for _, share := range playlist.Shares {
+			response.SharedWith = append(response.SharedWith, share.UserID)
+		}
+	}
+
+	return &response
+}
+
+// buildPlaylistSummary maps the playlist fields shared by list and detail responses
+func buildPlaylistSummary(playlist *models.Playlist) dto.PlaylistResponse {
+	var totalDurationMs int64
+	for _, item := range playlist.Items {
+		totalDurationMs += item.EndMs - item.StartMs
+	}
+
+	return dto.PlaylistResponse{
+		ID:                playlist.ID,
+		OwnerID:           playlist.OwnerID,
+		Title:             playlist.Title,
+		Description:       playlist.Description,
+		SharedWithPlayers: playlist.SharedWithPlayers,
+		ItemCount:         len(playlist.Items),
+		TotalDurationMs:   totalDurationMs,
+		CreatedAt:         playlist.CreatedAt,
+		UpdatedAt:         playlist.UpdatedAt,
+	}
+}
+
+// validateClipRange checks that a clip lies inside a fully processed video
+func validateClipRange(startMs, endMs int64, matchVideo *models.MatchVideo) error {
+	if matchVideo.Status != video.StatusCompleted || matchVideo.Metadata.DurationMs <= 0 {
+		return errors.New(constants.ErrVideoNotReady)
+	}
+	if startMs < 0 || endMs <= startMs || endMs > matchVideo.Metadata.DurationMs {
+		return errors.New(constants.ErrInvalidClipRange)
+	}
+	return nil
+}
+
+// buildClipURLs signs every rendition URL and appends a media fragment (#t=start,end in seconds).
+// Access to the playlist already authorizes the clips, and the fragment never reaches CloudFront.
+func buildClipURLs(artifacts []models.VideoArtifact, startMs, endMs int64) map[string]string {
+	fragment := "#t=" + formatFragmentSeconds(startMs) + "," + formatFragmentSeconds(endMs)
+
+	urls := buildRenditionURLs(artifacts)
+	for format, url := range urls {
+		urls[format] = cdn.SignURL(url) + fragment
+	}
+	return urls
+}
+
+// formatFragmentSeconds renders milliseconds as seconds for media fragments, e.g. 1500 -> 1.5
+func formatFragmentSeconds(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+}
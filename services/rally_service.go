@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RallyService defines the interface for the rally segments of match videos
+type RallyService interface {
+	GetRallies(matchID, videoID uuid.UUID) (*dto.MatchVideoRalliesResponse, error)
+	DetectRallies(matchID, videoID uuid.UUID) (*dto.MatchVideoRalliesResponse, error)
+	CreateRally(matchID, videoID, userID uuid.UUID, input *dto.CreateRallyInput) (*dto.RallyResponse, error)
+	UpdateRally(matchID, videoID, rallyID, userID uuid.UUID, input *dto.UpdateRallyInput) (*dto.RallyResponse, error)
+	DeleteRally(matchID, videoID, rallyID uuid.UUID) error
+	GenerateCondensedVideo(matchID, videoID uuid.UUID) (*dto.CondensedVideoResponse, error)
+}
+
+// RallyServiceImpl implements RallyService
+type RallyServiceImpl struct {
+	rallyRepo      repositories.RallySegmentRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	videoQueue     *video.QueueManager
+}
+
+// NewRallyService creates a new instance of RallyService
+func NewRallyService(
+	rallyRepo repositories.RallySegmentRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	videoQueue *video.QueueManager,
+) RallyService {
+	return &RallyServiceImpl{
+		rallyRepo:      rallyRepo,
+		matchVideoRepo: matchVideoRepo,
+		artifactRepo:   artifactRepo,
+		videoQueue:     videoQueue,
+	}
+}
+
+// GetRallies lists the rallies of a match video in playing order, with the detection state
+func (s *RallyServiceImpl) GetRallies(matchID, videoID uuid.UUID) (*dto.MatchVideoRalliesResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := s.rallyRepo.GetByMatchVideoID(matchVideo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rallies := make([]dto.RallyResponse, len(segments))
+	for i := range segments {
+		rallies[i] = buildRallyResponse(&segments[i])
+		rallies[i].Number = i + 1
+	}
+
+	return &dto.MatchVideoRalliesResponse{
+		MatchVideoID:    matchVideo.ID,
+		DetectionStatus: matchVideo.RallyStatus,
+		DetectionError:  matchVideo.RallyError,
+		Rallies:         rallies,
+	}, nil
+}
+
+// DetectRallies queues audio rally detection for a processed match video.
+// Unreviewed segments are replaced once detection finishes; reviewed ones are kept.
+func (s *RallyServiceImpl) DetectRallies(matchID, videoID uuid.UUID) (*dto.MatchVideoRalliesResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if matchVideo.Status != video.StatusCompleted {
+		return nil, errors.New(constants.ErrVideoNotReady)
+	}
+	if matchVideo.Metadata.AudioCodec == "" {
+		return nil, errors.New(constants.ErrNoAudioStream)
+	}
+
+	artifacts := loadArtifacts(s.artifactRepo, []uuid.UUID{matchVideo.ID})[matchVideo.ID]
+	inputKey := video.BuildAnalysisInputKey(matchVideo.OutputKey, readyRenditionFormats(artifacts))
+	if inputKey == "" {
+		return nil, errors.New(constants.ErrVideoNotReady)
+	}
+
+	if err := s.videoQueue.EnqueueRallyDetection(matchVideo, inputKey); err != nil {
+		return nil, err
+	}
+
+	return s.GetRallies(matchID, videoID)
+}
+
+// CreateRally adds a rally by hand; manual rallies count as reviewed
+func (s *RallyServiceImpl) CreateRally(matchID, videoID, userID uuid.UUID, input *dto.CreateRallyInput) (*dto.RallyResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRallyRange(input.StartMs, input.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	segment := &models.RallySegment{
+		MatchID:      matchVideo.MatchID,
+		MatchVideoID: matchVideo.ID,
+		StartMs:      input.StartMs,
+		EndMs:        input.EndMs,
+		Source:       models.RallyManual,
+		Confidence:   1,
+		Reviewed:     true,
+		UpdatedByID:  &userID,
+	}
+
+	if err := s.rallyRepo.Create(segment); err != nil {
+		return nil, err
+	}
+
+	response := buildRallyResponse(segment)
+	return &response, nil
+}
+
+// UpdateRally corrects a rally's boundaries and marks it reviewed, so re-detection keeps it
+func (s *RallyServiceImpl) UpdateRally(matchID, videoID, rallyID, userID uuid.UUID, input *dto.UpdateRallyInput) (*dto.RallyResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	segment, err := s.getRally(matchVideo.ID, rallyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StartMs != nil {
+		segment.StartMs = *input.StartMs
+	}
+	if input.EndMs != nil {
+		segment.EndMs = *input.EndMs
+	}
+	if err := validateRallyRange(segment.StartMs, segment.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	segment.Reviewed = true
+	segment.UpdatedByID = &userID
+
+	if err := s.rallyRepo.Update(segment); err != nil {
+		return nil, err
+	}
+
+	response := buildRallyResponse(segment)
+	return &response, nil
+}
+
+// DeleteRally removes a rally, e.g. a false detection
+func (s *RallyServiceImpl) DeleteRally(matchID, videoID, rallyID uuid.UUID) error {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return err
+	}
+
+	segment, err := s.getRally(matchVideo.ID, rallyID)
+	if err != nil {
+		return err
+	}
+
+	return s.rallyRepo.Delete(segment.ID)
+}
+
+// GenerateCondensedVideo queues a rallies-only rendition of a processed match video, cut from
+// the scout timecodes or, without a scout file, from the current rally segments
+func (s *RallyServiceImpl) GenerateCondensedVideo(matchID, videoID uuid.UUID) (*dto.CondensedVideoResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if matchVideo.Status != video.StatusCompleted {
+		return nil, errors.New(constants.ErrVideoNotReady)
+	}
+
+	artifacts := loadArtifacts(s.artifactRepo, []uuid.UUID{matchVideo.ID})[matchVideo.ID]
+	inputKey := video.BuildBestRenditionKey(matchVideo.OutputKey, readyRenditionFormats(artifacts))
+	if inputKey == "" {
+		return nil, errors.New(constants.ErrVideoNotReady)
+	}
+
+	if err := s.videoQueue.EnqueueCondensedVideo(matchVideo, inputKey); err != nil {
+		return nil, err
+	}
+
+	return &dto.CondensedVideoResponse{Status: video.StatusPending}, nil
+}
+
+// getMatchVideo loads an angle and checks that it belongs to the match
+func (s *RallyServiceImpl) getMatchVideo(matchID, videoID uuid.UUID) (*models.MatchVideo, error) {
+	matchVideo, err := s.matchVideoRepo.GetByID(videoID)
+	if err != nil || matchVideo.MatchID != matchID {
+		return nil, errors.New(constants.ErrMatchVideoNotFound)
+	}
+	return matchVideo, nil
+}
+
+// getRally loads a rally and checks that it belongs to the match video
+func (s *RallyServiceImpl) getRally(matchVideoID, rallyID uuid.UUID) (*models.RallySegment, error) {
+	segment, err := s.rallyRepo.GetByID(rallyID)
+	if err != nil || segment.MatchVideoID != matchVideoID {
+		return nil, errors.New(constants.ErrRallyNotFound)
+	}
+	return segment, nil
+}
+
+// validateRallyRange checks that a rally has a positive length and lies within the video
+func validateRallyRange(startMs, endMs int64, matchVideo *models.MatchVideo) error {
+	if startMs < 0 || endMs <= startMs {
+		return errors.New(constants.ErrInvalidRallyTime)
+	}
+	if duration := matchVideo.Metadata.DurationMs; duration > 0 && endMs > duration {
+		return errors.New(constants.ErrInvalidRallyTime)
+	}
+	return nil
+}
+
+// buildRallyResponse maps a rally segment to its response
+func buildRallyResponse(segment *models.RallySegment) dto.RallyResponse {
+	return dto.RallyResponse{
+		ID:           segment.ID,
+		MatchID:      segment.MatchID,
+		MatchVideoID: segment.MatchVideoID,
+		StartMs:      segment.StartMs,
+		EndMs:        segment.EndMs,
+		Source:       segment.Source,
+		Confidence:   segment.Confidence,
+		Reviewed:     segment.Reviewed,
+		UpdatedByID:  segment.UpdatedByID,
+		UpdatedAt:    segment.UpdatedAt,
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/orphans"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RetentionService defines the interface for raw video retention policies and storage reporting
+type RetentionService interface {
+	GetSeasonPolicy(seasonID uuid.UUID) (*dto.RetentionPolicyResponse, error)
+	UpdateSeasonPolicy(seasonID uuid.UUID, input *dto.UpdateRetentionPolicyInput) (*dto.RetentionPolicyResponse, error)
+	GetStorageReport(seasonID *uuid.UUID) (*dto.StorageReportResponse, error)
+	GetOrphanReport() (*dto.OrphanReportResponse, error)
+}
+
+// RetentionServiceImpl implements RetentionService
+type RetentionServiceImpl struct {
+	policyRepo     repositories.RetentionPolicyRepository
+	seasonRepo     repositories.SeasonRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	collector      *orphans.Collector
+}
+
+// NewRetentionService creates a new instance of RetentionService
+func NewRetentionService(
+	policyRepo repositories.RetentionPolicyRepository,
+	seasonRepo repositories.SeasonRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	collector *orphans.Collector,
+) RetentionService {
+	return &RetentionServiceImpl{
+		policyRepo:     policyRepo,
+		seasonRepo:     seasonRepo,
+		matchVideoRepo: matchVideoRepo,
+		artifactRepo:   artifactRepo,
+		collector:      collector,
+	}
+}
+
+// GetSeasonPolicy returns the retention policy of a season, or the default if none is stored
+func (s *RetentionServiceImpl) GetSeasonPolicy(seasonID uuid.UUID) (*dto.RetentionPolicyResponse, error) {
+	if _, err := s.seasonRepo.GetByID(seasonID); err != nil {
+		return nil, errors.New(constants.ErrSeasonNotFound)
+	}
+
+	policy, err := s.policyRepo.GetBySeasonID(seasonID)
+	if err != nil {
+		return buildRetentionPolicyResponse(models.DefaultRetentionPolicy(seasonID), true), nil
+	}
+	return buildRetentionPolicyResponse(policy, false), nil
+}
+
+// UpdateSeasonPolicy stores the retention policy of a season; the next retention pass applies it
+func (s *RetentionServiceImpl) UpdateSeasonPolicy(seasonID uuid.UUID, input *dto.UpdateRetentionPolicyInput) (*dto.RetentionPolicyResponse, error) {
+	if _, err := s.seasonRepo.GetByID(seasonID); err != nil {
+		return nil, errors.New(constants.ErrSeasonNotFound)
+	}
+
+	action := models.RawRetentionActionEnum(input.RawAction)
+	if !models.IsValidRawRetentionAction(action) {
+		return nil, errors.New(constants.ErrInvalidRawAction)
+	}
+
+	storageClass := input.ArchiveStorageClass
+	if storageClass == "" {
+		storageClass = constants.DefaultArchiveStorageClass
+	}
+
+	policy := &models.RetentionPolicy{
+		SeasonID:            seasonID,
+		RawAction:           action,
+		RawGraceDays:        input.RawGraceDays,
+		ArchiveStorageClass: storageClass,
+		FailedRawFlagDays:   input.FailedRawFlagDays,
+	}
+	if err := s.policyRepo.Upsert(policy); err != nil {
+		return nil, err
+	}
+
+	return buildRetentionPolicyResponse(policy, false), nil
+}
+
+// GetStorageReport sums up stored video bytes per category and lists raw uploads needing attention
+func (s *RetentionServiceImpl) GetStorageReport(seasonID *uuid.UUID) (*dto.StorageReportResponse, error) {
+	if seasonID != nil {
+		if _, err := s.seasonRepo.GetByID(*seasonID); err != nil {
+			return nil, errors.New(constants.ErrSeasonNotFound)
+		}
+	}
+
+	rawUsage, err := s.matchVideoRepo.SumRawSizeByStatus(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	artifactUsage, err := s.artifactRepo.SumSizeByKind(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.StorageReportResponse{
+		SeasonID:    seasonID,
+		Categories:  make(map[string]dto.StorageCategoryResponse),
+		FlaggedRaw:  []dto.FlaggedRawVideoResponse{},
+		GeneratedAt: time.Now(),
+	}
+
+	for _, usage := range append(rawUsage, artifactUsage...) {
+		report.Categories[usage.Category] = dto.StorageCategoryResponse{Objects: usage.Objects, Bytes: usage.Bytes}
+		// Deleted raw files are listed for reference but no longer cost anything
+		if usage.Category != "raw_"+string(models.RawFileDeleted) {
+			report.TotalBytes += usage.Bytes
+		}
+	}
+
+	flagged, err := s.matchVideoRepo.GetByRawStatus(models.RawFileFlagged, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	for _, matchVideo := range flagged {
+		report.FlaggedRaw = append(report.FlaggedRaw, dto.FlaggedRawVideoResponse{
+			MatchVideoID: matchVideo.ID,
+			MatchID:      matchVideo.MatchID,
+			Angle:        matchVideo.Angle,
+			RawKey:       matchVideo.RawKey,
+			Bytes:        matchVideo.Metadata.FileSize,
+			Reason:       matchVideo.RawStatusReason,
+			FlaggedAt:    matchVideo.RawStatusAt,
+		})
+	}
+
+	return report, nil
+}
+
+// GetOrphanReport is a dry run of the orphan collector: it lists unreferenced objects
+// and when they will be collected, without touching storage or the tracked orphans
+func (s *RetentionServiceImpl) GetOrphanReport() (*dto.OrphanReportResponse, error) {
+	report, err := s.collector.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.OrphanReportResponse{
+		Action:      report.Action,
+		GraceDays:   int(report.GracePeriod.Hours() / 24),
+		Prefixes:    make(map[string]dto.OrphanPrefixResponse, len(report.Prefixes)),
+		Orphans:     []dto.OrphanedObjectResponse{},
+		TotalCount:  len(report.Orphans),
+		GeneratedAt: report.GeneratedAt,
+	}
+
+	for prefix, summary := range report.Prefixes {
+		response.Prefixes[prefix] = dto.OrphanPrefixResponse{
+			Objects:       summary.Objects,
+			Bytes:         summary.Bytes,
+			Orphans:       summary.Orphans,
+			OrphanBytes:   summary.OrphanBytes,
+			EligibleNow:   summary.EligibleNow,
+			EligibleBytes: summary.EligibleSize,
+		}
+	}
+
+	sort.Slice(report.Orphans, func(i, j int) bool {
+		return report.Orphans[i].EligibleAt.Before(report.Orphans[j].EligibleAt)
+	})
+	for _, orphan := range report.Orphans {
+		if len(response.Orphans) == constants.OrphanReportLimit {
+			response.Truncated = true
+			break
+		}
+		response.Orphans = append(response.Orphans, dto.OrphanedObjectResponse{
+			Key:          orphan.Key,
+			Bytes:        orphan.Size,
+			LastModified: orphan.LastModified,
+			FirstSeenAt:  orphan.FirstSeenAt,
+			EligibleAt:   orphan.EligibleAt,
+		})
+	}
+
+	return response, nil
+}
+
+// buildRetentionPolicyResponse maps a retention policy to its DTO
+func buildRetentionPolicyResponse(policy *models.RetentionPolicy, isDefault bool) *dto.RetentionPolicyResponse {
+	response := &dto.RetentionPolicyResponse{
+		SeasonID:            policy.SeasonID,
+		RawAction:           string(policy.RawAction),
+		RawGraceDays:        policy.RawGraceDays,
+		ArchiveStorageClass: policy.ArchiveStorageClass,
+		FailedRawFlagDays:   policy.FailedRawFlagDays,
+		IsDefault:           isDefault,
+	}
+	if !isDefault {
+		response.UpdatedAt = &policy.UpdatedAt
+	}
+	return response
+}
@@ -2,15 +2,14 @@ package services
 
 import (
 	"errors"
-	"fmt"
-	"go-gin-starter/config"
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	"go-gin-starter/pkg/constants"
+	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/pkg/upload"
 	"go-gin-starter/repositories"
 	"mime/multipart"
-	"strings"
 
 	"github.com/google/uuid"
 )
@@ -22,7 +21,7 @@ type SeasonService interface {
 	GetSeasonByID(id uuid.UUID) (*dto.SeasonResponse, error)
 	UpdateSeason(id uuid.UUID, input *dto.UpdateSeasonInput) (*dto.SeasonResponse, error)
 	DeleteSeason(id uuid.UUID) error
-	UpdateSeasonLogo(seasonID uuid.UUID, logoFilename string) error
+	UpdateSeasonLogo(seasonID uuid.UUID, logo models.ImageVariants) error
 
 	// Deprecated: Use FileUploadService directly from controllers instead.
 	UploadAndSaveSeasonLogo(seasonID uuid.UUID, file *multipart.FileHeader) (string, error)
@@ -68,7 +67,8 @@ func (s *SeasonServiceImpl) CreateSeason(input *dto.CreateSeasonInput) (*dto.Sea
 		SeasonYear: season.SeasonYear,
 		StartDate:  season.StartDate,
 		EndDate:    season.EndDate,
-		LogoURL:    fmt.Sprintf("https://%s/logos/seasons/%s", config.AssetCloudFrontDomain, season.Logo),
+		LogoURL:    httpPkg.SeasonLogoURL(&season),
+		LogoURLs:   assets.VariantURLs(season.LogoVariants),
 		CreatedAt:  season.CreatedAt,
 		UpdatedAt:  season.UpdatedAt,
 	}
@@ -93,7 +93,8 @@ func (s *SeasonServiceImpl) GetAllSeasons() ([]dto.SeasonResponse, error) {
 			SeasonYear: season.SeasonYear,
 			StartDate:  season.StartDate,
 			EndDate:    season.EndDate,
-			LogoURL:    fmt.Sprintf("https://%s/logos/seasons/%s", config.AssetCloudFrontDomain, season.Logo),
+			LogoURL:    httpPkg.SeasonLogoURL(&season),
+			LogoURLs:   assets.VariantURLs(season.LogoVariants),
 			CreatedAt:  season.CreatedAt,
 			UpdatedAt:  season.UpdatedAt,
 		})
@@ -117,7 +118,8 @@ func (s *SeasonServiceImpl) GetSeasonByID(id uuid.UUID) (*dto.SeasonResponse, er
 		SeasonYear: season.SeasonYear,
 		StartDate:  season.StartDate,
 		EndDate:    season.EndDate,
-		LogoURL:    fmt.Sprintf("https://%s/logos/seasons/%s", config.AssetCloudFrontDomain, season.Logo),
+		LogoURL:    httpPkg.SeasonLogoURL(season),
+		LogoURLs:   assets.VariantURLs(season.LogoVariants),
 		CreatedAt:  season.CreatedAt,
 		UpdatedAt:  season.UpdatedAt,
 	}
@@ -166,7 +168,8 @@ func (s *SeasonServiceImpl) UpdateSeason(id uuid.UUID, input *dto.UpdateSeasonIn
 		SeasonYear: season.SeasonYear,
 		StartDate:  season.StartDate,
 		EndDate:    season.EndDate,
-		LogoURL:    fmt.Sprintf("https://%s/logos/seasons/%s", config.AssetCloudFrontDomain, season.Logo),
+		LogoURL:    httpPkg.SeasonLogoURL(season),
+		LogoURLs:   assets.VariantURLs(season.LogoVariants),
 		CreatedAt:  season.CreatedAt,
 		UpdatedAt:  season.UpdatedAt,
 	}
@@ -178,18 +181,15 @@ func (s *SeasonServiceImpl) DeleteSeason(id uuid.UUID) error {
 	return s.seasonRepo.Delete(id)
 }
 
-// UpdateSeasonLogo updates the logo of a season
-func (s *SeasonServiceImpl) UpdateSeasonLogo(seasonID uuid.UUID, logoFilename string) error {
+// UpdateSeasonLogo stores the renditions of a newly uploaded season logo
+func (s *SeasonServiceImpl) UpdateSeasonLogo(seasonID uuid.UUID, logo models.ImageVariants) error {
 	season, err := s.seasonRepo.GetByID(seasonID)
 	if err != nil {
 		return errors.New(constants.ErrSeasonNotFound)
 	}
 
-	// Extract just the filename from the full URL
-	parts := strings.Split(logoFilename, "/")
-	filename := parts[len(parts)-1]
-
-	season.Logo = filename
+	season.Logo = logo[models.ImageSizeLarge]
+	season.LogoVariants = logo
 	return s.seasonRepo.Update(season)
 }
 
@@ -209,42 +209,3 @@ func (s *SeasonServiceImpl) UploadAndSaveSeasonLogo(seasonID uuid.UUID, file *mu
 
 	return "", nil
 }
-
-// Legacy functions for backward compatibility
-// These will be removed once migration is complete
-
-func CreateSeasonService(input *dto.CreateSeasonInput) (*dto.SeasonResponse, error) {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.CreateSeason(input)
-}
-
-func GetAllSeasonsService() ([]dto.SeasonResponse, error) {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.GetAllSeasons()
-}
-
-func GetSeasonByIDService(id uuid.UUID) (*dto.SeasonResponse, error) {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.GetSeasonByID(id)
-}
-
-func UpdateSeasonService(id uuid.UUID, input *dto.UpdateSeasonInput) (*dto.SeasonResponse, error) {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.UpdateSeason(id, input)
-}
-
-func DeleteSeasonService(id uuid.UUID) error {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.DeleteSeason(id)
-}
-
-func UpdateSeasonLogoService(seasonID uuid.UUID, logoFilename string) error {
-	seasonRepo := repositories.NewSeasonRepository()
-	seasonService := NewSeasonService(seasonRepo, upload.NewFileUploadService())
-	return seasonService.UpdateSeasonLogo(seasonID, logoFilename)
-}
@@ -6,12 +6,31 @@ import (
 	"github.com/google/uuid"
 )
 
-// Global userService instance
-var globalUserService UserService
+// Global service instances
+var (
+	globalUserService    UserService
+	globalSessionService SessionService
+)
 
 // InitGlobalServices initializes global service references
-func InitGlobalServices(userService UserService) {
+func InitGlobalServices(userService UserService, sessionService SessionService) {
 	globalUserService = userService
+	globalSessionService = sessionService
+}
+
+// IsSessionActive reports whether access tokens of a session are still accepted
+func IsSessionActive(sessionID uuid.UUID) bool {
+	return globalSessionService.IsActive(sessionID)
+}
+
+// IsTokenVersionCurrent reports whether access tokens issued at a user's token version are still accepted
+func IsTokenVersionCurrent(userID uuid.UUID, tokenVersion int) bool {
+	return globalSessionService.IsTokenVersionCurrent(userID, tokenVersion)
+}
+
+// CheckAccountSetup returns why a user is limited to the account setup routes, or nil
+func CheckAccountSetup(userID uuid.UUID) error {
+	return globalSessionService.CheckAccountSetup(userID)
 }
 
 // GetUserByID wrapper for backward compatibility
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"errors"
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	auditPkg "go-gin-starter/pkg/audit"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	"go-gin-starter/repositories"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ClientInfo describes the device a session is used from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionService defines the interface for per-device sessions
+type SessionService interface {
+	Start(user *models.User, client ClientInfo) (*dto.LoginResult, error)
+	Refresh(refreshToken string, client ClientInfo) (*dto.LoginResult, error)
+	List(userID, currentID uuid.UUID) ([]dto.SessionResponse, error)
+	Revoke(userID, sessionID uuid.UUID) error
+	RevokeOthers(userID, currentID uuid.UUID) (int64, error)
+	RevokeAll(userID uuid.UUID) (int64, error)
+	IsActive(sessionID uuid.UUID) bool
+	IsTokenVersionCurrent(userID uuid.UUID, tokenVersion int) bool
+	CheckAccountSetup(userID uuid.UUID) error
+}
+
+// SessionServiceImpl implements SessionService
+type SessionServiceImpl struct {
+	sessionRepo repositories.SessionRepository
+	userRepo    repositories.UserRepository
+}
+
+// NewSessionService creates a new instance of SessionService
+func NewSessionService(sessionRepo repositories.SessionRepository, userRepo repositories.UserRepository) SessionService {
+	return &SessionServiceImpl{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// Start creates a session for a user who just logged in and issues its tokens
+func (s *SessionServiceImpl) Start(user *models.User, client ClientInfo) (*dto.LoginResult, error) {
+	refreshToken, err := authPkg.GenerateSecureToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: authPkg.HashToken(refreshToken),
+		UserAgent:        truncate(client.UserAgent, 255),
+		IPAddress:        client.IPAddress,
+		ExpiresAt:        now.Add(constants.SessionTTL),
+		LastUsedAt:       now,
+	}
+	if err := s.sessionRepo.Create(session, constants.MaxSessionsPerUser); err != nil {
+		return nil, errors.New("failed to save refresh token")
+	}
+
+	accessToken, err := authPkg.GenerateJWT(user.ID, session.ID, user.TokenVersion)
+	if err != nil {
+		return nil, errors.New(constants.ErrTokenGenerationFailed)
+	}
+
+	return &dto.LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Refresh replaces the refresh token of a session and issues a new access token. A token
+// that was already replaced means someone kept a copy, so its session is revoked.
+func (s *SessionServiceImpl) Refresh(refreshToken string, client ClientInfo) (*dto.LoginResult, error) {
+	tokenHash := authPkg.HashToken(refreshToken)
+	session, err := s.sessionRepo.FindByRefreshToken(tokenHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.detectReuse(tokenHash, client)
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+	if err != nil || session.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+
+	// Sessions of deleted users end with the user
+	tokenVersion, err := s.userRepo.GetTokenVersion(session.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = s.Revoke(session.UserID, session.ID)
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+	if err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	newRefreshToken, err := authPkg.GenerateSecureToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	now := time.Now()
+	session.RefreshTokenHash = authPkg.HashToken(newRefreshToken)
+	session.UserAgent = truncate(client.UserAgent, 255)
+	session.IPAddress = client.IPAddress
+	session.ExpiresAt = now.Add(constants.SessionTTL)
+	session.LastUsedAt = now
+
+	rotated, err := s.sessionRepo.Rotate(session, tokenHash)
+	if err != nil {
+		return nil, errors.New("failed to update refresh token")
+	}
+	if !rotated {
+		return nil, errors.New(constants.ErrInvalidToken)
+	}
+
+	accessToken, err := authPkg.GenerateJWT(session.UserID, session.ID, tokenVersion)
+	if err != nil {
+		return nil, errors.New(constants.ErrTokenGenerationFailed)
+	}
+
+	return &dto.LoginResult{AccessToken: accessToken, RefreshToken: newRefreshToken}, nil
+}
+
+// detectReuse revokes the session a rotated refresh token belonged to and records the
+// reuse in the audit log. Unknown tokens are ignored.
+func (s *SessionServiceImpl) detectReuse(tokenHash string, client ClientInfo) {
+	rotated, err := s.sessionRepo.FindRotatedToken(tokenHash)
+	if err != nil {
+		return
+	}
+
+	revoked, err := s.sessionRepo.Delete(rotated.UserID, rotated.SessionID)
+	if err != nil {
+		logger.Error("Failed to revoke session after refresh token reuse",
+			zap.String("session_id", rotated.SessionID.String()), zap.Error(err))
+	} else if !revoked {
+		// Left behind by a session revoked before its rotated tokens were deleted with it;
+		// Delete has dropped them now and there is nothing to report
+		return
+	}
+	sessionChecks.forget(rotated.SessionID)
+
+	logger.Warn("Rotated refresh token reused",
+		zap.String("user_id", rotated.UserID.String()),
+		zap.String("session_id", rotated.SessionID.String()),
+		zap.String("ip", client.IPAddress))
+
+	metadata := auditPkg.BuildRefreshTokenReuseMetadata(rotated, revoked, truncate(client.UserAgent, 255), client.IPAddress)
+	if err := LogSecurityEvent("refresh_token_reuse", &rotated.UserID, metadata); err != nil {
+		logger.Warn("LogSecurityEvent failed", zap.Error(err))
+	}
+}
+
+// List returns the active sessions of a user and marks the one the request came from
+func (s *SessionServiceImpl) List(userID, currentID uuid.UUID) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.ListActive(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	response := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = dto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID == currentID,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastUsedAt: session.LastUsedAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		}
+	}
+	return response, nil
+}
+
+// Revoke ends one session of a user; logging out revokes the current one
+func (s *SessionServiceImpl) Revoke(userID, sessionID uuid.UUID) error {
+	deleted, err := s.sessionRepo.Delete(userID, sessionID)
+	if err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+	sessionChecks.forget(sessionID)
+	if !deleted {
+		return errors.New(constants.ErrSessionNotFound)
+	}
+	return nil
+}
+
+// RevokeOthers ends every session of a user except the current one
+func (s *SessionServiceImpl) RevokeOthers(userID, currentID uuid.UUID) (int64, error) {
+	revoked, err := s.sessionRepo.DeleteOthers(userID, currentID)
+	if err != nil {
+		return 0, errors.New(constants.ErrDatabase)
+	}
+	sessionChecks.forgetUser(userID, currentID)
+	return revoked, nil
+}
+
+// RevokeAll ends every session of a user
+func (s *SessionServiceImpl) RevokeAll(userID uuid.UUID) (int64, error) {
+	revoked, err := s.sessionRepo.DeleteAll(userID)
+	if err != nil {
+		return 0, errors.New(constants.ErrDatabase)
+	}
+	sessionChecks.forgetUser(userID, uuid.Nil)
+	return revoked, nil
+}
+
+// IsActive reports whether a session exists and has not expired. Answers are cached for
+// SessionCheckTTL, so another instance may accept a revoked session's access tokens that long.
+func (s *SessionServiceImpl) IsActive(sessionID uuid.UUID) bool {
+	if sessionChecks.recent(sessionID) {
+		return true
+	}
+
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil || session.ExpiresAt.Before(time.Now()) {
+		return false
+	}
+	sessionChecks.remember(session.ID, session.UserID)
+	return true
+}
+
+// IsTokenVersionCurrent reports whether access tokens issued at a token version are still
+// accepted, i.e. the user exists and the version was not bumped since. Versions are cached
+// like sessions, so another instance may accept outdated tokens for SessionCheckTTL.
+func (s *SessionServiceImpl) IsTokenVersionCurrent(userID uuid.UUID, tokenVersion int) bool {
+	current, _, ok := accountStates.recent(userID)
+	if !ok {
+		user, err := s.loadAccountState(userID)
+		if err != nil {
+			return false
+		}
+		current = user.TokenVersion
+	}
+	return tokenVersion == current
+}
+
+// CheckAccountSetup returns why a user is limited to the account setup routes, or nil if
+// they are not. Completed setups are cached with the token version, so they cost no query;
+// incomplete ones are read on every request, so finishing setup takes effect at once.
+func (s *SessionServiceImpl) CheckAccountSetup(userID uuid.UUID) error {
+	if _, setUp, ok := accountStates.recent(userID); ok && setUp {
+		return nil
+	}
+
+	user, err := s.loadAccountState(userID)
+	if err != nil {
+		return errors.New(constants.ErrUnauthorized)
+	}
+	return accountSetupError(user)
+}
+
+// loadAccountState reads the token version and setup of a user and caches them
+func (s *SessionServiceImpl) loadAccountState(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetAuthState(userID)
+	if err != nil {
+		return nil, err
+	}
+	accountStates.remember(userID, user.TokenVersion, accountSetupError(user) == nil)
+	return user, nil
+}
+
+// accountSetupError returns the setup step a user still has to complete: confirming their
+// email address, or enabling two-factor authentication if their role requires it
+func accountSetupError(user *models.User) error {
+	if user.EmailVerifiedAt == nil {
+		return errors.New(constants.ErrEmailNotVerified)
+	}
+	if user.TwoFactorEnabledAt == nil && authPkg.TwoFactorRequired(user) {
+		return errors.New(constants.ErrTwoFactorRequired)
+	}
+	return nil
+}
+
+// revokeAccessTokens bumps a user's token version, so access tokens issued before stop
+// working. Refresh tokens keep working and get access tokens at the new version.
+func revokeAccessTokens(userRepo repositories.UserRepository, userID uuid.UUID) error {
+	if err := userRepo.IncrementTokenVersion(userID); err != nil {
+		return err
+	}
+	accountStates.forget(userID)
+	return nil
+}
+
+// sessionChecks caches sessions recently found active. It is shared by all service instances.
+var sessionChecks = &sessionCache{entries: make(map[uuid.UUID]sessionCheck)}
+
+type sessionCheck struct {
+	userID    uuid.UUID
+	checkedAt time.Time
+}
+
+type sessionCache struct {
+	mu         sync.Mutex
+	entries    map[uuid.UUID]sessionCheck
+	lastPruned time.Time
+}
+
+func (c *sessionCache) recent(sessionID uuid.UUID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[sessionID]
+	return ok && time.Since(entry.checkedAt) < constants.SessionCheckTTL
+}
+
+func (c *sessionCache) remember(sessionID, userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastPruned) > constants.SessionCheckTTL {
+		for id, entry := range c.entries {
+			if now.Sub(entry.checkedAt) >= constants.SessionCheckTTL {
+				delete(c.entries, id)
+			}
+		}
+		c.lastPruned = now
+	}
+	c.entries[sessionID] = sessionCheck{userID: userID, checkedAt: now}
+}
+
+func (c *sessionCache) forget(sessionID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, sessionID)
+}
+
+// forgetUser drops the sessions of a user, except keepID
+func (c *sessionCache) forgetUser(userID, keepID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, entry := range c.entries {
+		if entry.userID == userID && id != keepID {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// accountStates caches the token version of users and whether their account setup is
+// complete. It is shared by all service instances. Changes that undo a completed setup
+// call forget, other instances notice them within SessionCheckTTL.
+var accountStates = &accountStateCache{entries: make(map[uuid.UUID]accountState)}
+
+type accountState struct {
+	version   int
+	setUp     bool
+	checkedAt time.Time
+}
+
+type accountStateCache struct {
+	mu         sync.Mutex
+	entries    map[uuid.UUID]accountState
+	lastPruned time.Time
+}
+
+func (c *accountStateCache) recent(userID uuid.UUID) (int, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || time.Since(entry.checkedAt) >= constants.SessionCheckTTL {
+		return 0, false, false
+	}
+	return entry.version, entry.setUp, true
+}
+
+func (c *accountStateCache) remember(userID uuid.UUID, version int, setUp bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastPruned) > constants.SessionCheckTTL {
+		for id, entry := range c.entries {
+			if now.Sub(entry.checkedAt) >= constants.SessionCheckTTL {
+				delete(c.entries, id)
+			}
+		}
+		c.lastPruned = now
+	}
+	c.entries[userID] = accountState{version: version, setUp: setUp, checkedAt: now}
+}
+
+func (c *accountStateCache) forget(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
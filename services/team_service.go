@@ -2,16 +2,12 @@ package services
 
 import (
 	"errors"
-	"fmt"
-	"go-gin-starter/config"
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
 	"go-gin-starter/pkg/constants"
-	storagePkg "go-gin-starter/pkg/storage"
+	httpPkg "go-gin-starter/pkg/http"
 	"go-gin-starter/pkg/upload"
 	"go-gin-starter/repositories"
-	"mime/multipart"
-	"path/filepath"
 
 	"github.com/google/uuid"
 )
@@ -23,10 +19,9 @@ type TeamService interface {
 	GetTeamByID(id uuid.UUID) (*dto.TeamResponse, error)
 	UpdateTeam(id uuid.UUID, input *dto.UpdateTeamInput) (*dto.TeamResponse, error)
 	DeleteTeam(id uuid.UUID) error
-	UpdateTeamLogo(id uuid.UUID, logoFilename string) error
+	UpdateTeamLogo(id uuid.UUID, logo models.ImageVariants) error
 
 	// Deprecated: Use FileUploadService directly from controllers instead.
-	UploadAndSaveTeamLogo(teamID uuid.UUID, file *multipart.FileHeader) (string, string, error)
 }
 
 // TeamServiceImpl implements TeamService
@@ -118,58 +113,20 @@ func (s *TeamServiceImpl) DeleteTeam(id uuid.UUID) error {
 	return s.teamRepo.Delete(id)
 }
 
-// UpdateTeamLogo updates the logo of a team
-func (s *TeamServiceImpl) UpdateTeamLogo(id uuid.UUID, logoFilename string) error {
+// UpdateTeamLogo stores the renditions of a newly uploaded team logo
+func (s *TeamServiceImpl) UpdateTeamLogo(id uuid.UUID, logo models.ImageVariants) error {
 	team, err := s.teamRepo.GetByID(id)
 	if err != nil {
 		return errors.New(constants.ErrTeamNotFound)
 	}
 
-	team.Logo = logoFilename
+	team.Logo = logo[models.ImageSizeLarge]
+	team.LogoVariants = logo
 
 	return s.teamRepo.Update(team)
 }
 
-// UploadAndSaveTeamLogo handles validation + saving + DB update
-// Deprecated: This method is kept for backward compatibility but should not be used.
-// Use the FileUploadService directly from controllers instead.
-func (s *TeamServiceImpl) UploadAndSaveTeamLogo(teamID uuid.UUID, file *multipart.FileHeader) (string, string, error) {
-	// Get team before updating
-	team, err := s.teamRepo.GetByID(teamID)
-	if err != nil {
-		return "", "", errors.New(constants.ErrTeamNotFound)
-	}
-
-	// File validation and upload is now handled by the upload service
-	// This method is kept for backward compatibility but should be deprecated
-	// in favor of directly using the upload service from the controller
-
-	ext := filepath.Ext(file.Filename)
-	newFileName := storagePkg.GenerateTeamLogoFileName(ext)
-	savePath := storagePkg.BuildTeamLogoPath(newFileName)
-
-	team.Logo = newFileName
-	if err := s.teamRepo.Update(team); err != nil {
-		return "", "", err
-	}
-
-	return newFileName, savePath, nil
-}
-
 // Helper function to map team model to response DTO
 func (s *TeamServiceImpl) mapTeamToResponse(team *models.Team) dto.TeamResponse {
-	logo := team.Logo
-	if logo == "" {
-		logo = "defaults/default-team-logo.png"
-	}
-
-	return dto.TeamResponse{
-		ID:        team.ID,
-		Name:      team.Name,
-		Country:   team.Country,
-		SeasonID:  team.SeasonID,
-		LogoURL:   fmt.Sprintf("https://%s/logos/teams/%s", config.AssetCloudFrontDomain, logo),
-		CreatedAt: team.CreatedAt,
-		UpdatedAt: team.UpdatedAt,
-	}
+	return httpPkg.BuildTeamResponse(team)
 }
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/mail"
+	"go-gin-starter/pkg/qrcode"
+	"go-gin-starter/repositories"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorService defines the interface for TOTP two-factor authentication
+type TwoFactorService interface {
+	GetStatus(userID uuid.UUID) (*dto.TwoFactorStatusResponse, error)
+	Setup(userID uuid.UUID) (*dto.TwoFactorSetupResponse, error)
+	Enable(userID uuid.UUID, code string) (*dto.RecoveryCodesResponse, error)
+	Disable(userID uuid.UUID, password, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) (*dto.RecoveryCodesResponse, error)
+	Reset(userID uuid.UUID) error
+}
+
+// TwoFactorServiceImpl implements TwoFactorService
+type TwoFactorServiceImpl struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+}
+
+// NewTwoFactorService creates a new instance of TwoFactorService
+func NewTwoFactorService(userRepo repositories.UserRepository, twoFactorRepo repositories.TwoFactorRepository) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// GetStatus reports whether 2FA is enabled and required and how many recovery codes are left
+func (s *TwoFactorServiceImpl) GetStatus(userID uuid.UUID) (*dto.TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+
+	response := &dto.TwoFactorStatusResponse{
+		Enabled:  user.TwoFactorEnabledAt != nil,
+		Required: authPkg.TwoFactorRequired(user),
+	}
+	if user.TwoFactorEnabledAt != nil {
+		response.EnabledAt = user.TwoFactorEnabledAt.Format(time.RFC3339)
+		if response.RecoveryCodesLeft, err = s.twoFactorRepo.CountRecoveryCodes(userID); err != nil {
+			return nil, errors.New(constants.ErrDatabase)
+		}
+	}
+	return response, nil
+}
+
+// Setup creates a new secret and returns it for the authenticator app. It only takes
+// effect once Enable confirms that the app produces valid codes.
+func (s *TwoFactorServiceImpl) Setup(userID uuid.UUID) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errors.New(constants.ErrTwoFactorEnabled)
+	}
+
+	secret, err := authPkg.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := authPkg.EncryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.SetPendingSecret(userID, encrypted); err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	uri := authPkg.TOTPProvisioningURI(constants.TOTPIssuer, user.Email, secret)
+	png, err := qrcode.PNG(uri, constants.TOTPQRCodeScale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURL: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code from the app and returns the recovery codes
+func (s *TwoFactorServiceImpl) Enable(userID uuid.UUID, code string) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errors.New(constants.ErrTwoFactorEnabled)
+	}
+	if user.TOTPSecret == nil {
+		return nil, errors.New(constants.ErrTwoFactorNotSetUp)
+	}
+
+	secret, err := authPkg.DecryptSecret(*user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := authPkg.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, errors.New(constants.ErrInvalidTwoFactorCode)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.Enable(userID, step, hashes); err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+
+	sendNotice(user, user.Email, mail.NoticeTwoFactorEnabled)
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns 2FA off after checking the password and a current code. Users whose role
+// requires 2FA cannot turn it off.
+func (s *TwoFactorServiceImpl) Disable(userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New(constants.ErrUserNotFound)
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return errors.New(constants.ErrTwoFactorDisabled)
+	}
+	if authPkg.TwoFactorRequired(user) {
+		return errors.New(constants.ErrTwoFactorRequired)
+	}
+	if !authPkg.CheckPasswordHash(password, user.Password) {
+		return errors.New(constants.ErrInvalidCredentials)
+	}
+	if err := verifySecondFactor(s.twoFactorRepo, user, code); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Disable(userID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+
+	sendNotice(user, user.Email, mail.NoticeTwoFactorDisabled)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code
+func (s *TwoFactorServiceImpl) RegenerateRecoveryCodes(userID uuid.UUID, code string) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(constants.ErrUserNotFound)
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return nil, errors.New(constants.ErrTwoFactorDisabled)
+	}
+	if err := verifySecondFactor(s.twoFactorRepo, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, errors.New(constants.ErrDatabase)
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset lets an admin turn off 2FA for a user who lost their authenticator and recovery codes
+func (s *TwoFactorServiceImpl) Reset(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New(constants.ErrUserNotFound)
+	}
+	if user.TwoFactorEnabledAt == nil && user.TOTPSecret == nil {
+		return errors.New(constants.ErrTwoFactorDisabled)
+	}
+
+	if err := s.twoFactorRepo.Disable(userID); err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+	accountStates.forget(userID)
+
+	sendNotice(user, user.Email, mail.NoticeTwoFactorDisabled)
+	return nil
+}
+
+// newRecoveryCodes generates recovery codes and their hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := authPkg.GenerateRecoveryCodes(constants.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = authPkg.HashRecoveryCode(code)
+	}
+	return codes, hashes, nil
+}
+
+// verifySecondFactor accepts a current TOTP code or an unused recovery code. Both work once:
+// the TOTP step is recorded and the recovery code is marked as used.
+func verifySecondFactor(repo repositories.TwoFactorRepository, user *models.User, code string) error {
+	if user.TwoFactorEnabledAt == nil || user.TOTPSecret == nil {
+		return errors.New(constants.ErrTwoFactorDisabled)
+	}
+
+	secret, err := authPkg.DecryptSecret(*user.TOTPSecret)
+	if err != nil {
+		return err
+	}
+	if step, ok := authPkg.ValidateTOTP(secret, code, time.Now()); ok {
+		accepted, err := repo.AcceptStep(user.ID, step)
+		if err != nil {
+			return errors.New(constants.ErrDatabase)
+		}
+		if !accepted {
+			return errors.New(constants.ErrInvalidTwoFactorCode)
+		}
+		return nil
+	}
+
+	used, err := repo.UseRecoveryCode(user.ID, authPkg.HashRecoveryCode(code))
+	if err != nil {
+		return errors.New(constants.ErrDatabase)
+	}
+	if !used {
+		return errors.New(constants.ErrInvalidTwoFactorCode)
+	}
+	return nil
+}
+
+// challengeAttempts counts wrong codes per login challenge. It is shared by all service
+// instances; a challenge is closed after too many failures or once it was used.
+var challengeAttempts = &attemptTracker{entries: make(map[string]attemptEntry)}
+
+type attemptEntry struct {
+	failures  int
+	expiresAt time.Time
+}
+
+type attemptTracker struct {
+	mu      sync.Mutex
+	entries map[string]attemptEntry
+}
+
+// exhausted reports whether a challenge may not be used any more
+func (t *attemptTracker) exhausted(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[id].failures >= constants.TwoFactorMaxAttempts
+}
+
+// fail counts a wrong code against a challenge
+func (t *attemptTracker) fail(id string, expiresAt time.Time) {
+	t.record(id, expiresAt, 1)
+}
+
+// close prevents a challenge from being used again
+func (t *attemptTracker) close(id string, expiresAt time.Time) {
+	t.record(id, expiresAt, constants.TwoFactorMaxAttempts)
+}
+
+func (t *attemptTracker) record(id string, expiresAt time.Time, failures int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Expired challenges are rejected by their signature anyway
+	now := time.Now()
+	for key, entry := range t.entries {
+		if entry.expiresAt.Before(now) {
+			delete(t.entries, key)
+		}
+	}
+
+	entry := t.entries[id]
+	entry.failures += failures
+	entry.expiresAt = expiresAt
+	t.entries[id] = entry
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-gin-starter/config"
+	"go-gin-starter/models"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+// memoryTwoFactorRepository keeps the last accepted TOTP step like the totp_last_step
+// column: AcceptStep only succeeds for a step later than the recorded one
+type memoryTwoFactorRepository struct {
+	lastStep      int64
+	recoveryCodes map[string]bool
+}
+
+func (r *memoryTwoFactorRepository) SetPendingSecret(uuid.UUID, string) error { return nil }
+
+func (r *memoryTwoFactorRepository) Enable(_ uuid.UUID, step int64, _ []string) error {
+	r.lastStep = step
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) Disable(uuid.UUID) error { return nil }
+
+func (r *memoryTwoFactorRepository) ReplaceRecoveryCodes(uuid.UUID, []string) error { return nil }
+
+func (r *memoryTwoFactorRepository) AcceptStep(_ uuid.UUID, step int64) (bool, error) {
+	if r.lastStep >= step {
+		return false, nil
+	}
+	r.lastStep = step
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) UseRecoveryCode(_ uuid.UUID, codeHash string) (bool, error) {
+	if !r.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	r.recoveryCodes[codeHash] = false
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) CountRecoveryCodes(uuid.UUID) (int64, error) {
+	var count int64
+	for _, unused := range r.recoveryCodes {
+		if unused {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// totpCode computes the code of a step per RFC 6238 independently of pkg/auth
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0F
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7FFFFFFF)%1_000_000)
+}
+
+// currentStep returns the current TOTP step, first waiting out the last seconds of a step so
+// the step cannot change while a test is running
+func currentStep() int64 {
+	if remaining := 30 - time.Now().Unix()%30; remaining <= 2 {
+		time.Sleep(time.Duration(remaining) * time.Second)
+	}
+	return time.Now().Unix() / 30
+}
+
+func twoFactorUser(t *testing.T) (*models.User, string) {
+	t.Helper()
+	config.TOTPEncryptionKey = "test-totp-encryption-key"
+
+	secret, err := authPkg.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	encrypted, err := authPkg.EncryptSecret(secret)
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+	enabledAt := time.Now()
+	return &models.User{ID: uuid.New(), TOTPSecret: &encrypted, TwoFactorEnabledAt: &enabledAt}, secret
+}
+
+func TestVerifySecondFactorRejectsReplayedCodes(t *testing.T) {
+	user, secret := twoFactorUser(t)
+	repo := &memoryTwoFactorRepository{}
+	current := currentStep()
+
+	if err := verifySecondFactor(repo, user, totpCode(t, secret, current)); err != nil {
+		t.Fatalf("current code rejected: %v", err)
+	}
+	if err := verifySecondFactor(repo, user, totpCode(t, secret, current)); err == nil || err.Error() != constants.ErrInvalidTwoFactorCode {
+		t.Fatalf("replayed code: err = %v, want %s", err, constants.ErrInvalidTwoFactorCode)
+	}
+	// A code of an earlier step is still inside the drift window but must not work either
+	if err := verifySecondFactor(repo, user, totpCode(t, secret, current-1)); err == nil {
+		t.Fatal("code of an earlier step accepted after a later one")
+	}
+	// The next step's code is new, so clock drift ahead is still tolerated
+	if err := verifySecondFactor(repo, user, totpCode(t, secret, current+1)); err != nil {
+		t.Fatalf("code of the next step rejected: %v", err)
+	}
+}
+
+func TestVerifySecondFactorDriftWindow(t *testing.T) {
+	user, secret := twoFactorUser(t)
+	current := currentStep()
+
+	for _, offset := range []int64{-1, 0, 1} {
+		repo := &memoryTwoFactorRepository{}
+		if err := verifySecondFactor(repo, user, totpCode(t, secret, current+offset)); err != nil {
+			t.Errorf("code of step %+d rejected: %v", offset, err)
+		}
+	}
+	for _, offset := range []int64{-2, 2} {
+		repo := &memoryTwoFactorRepository{}
+		if err := verifySecondFactor(repo, user, totpCode(t, secret, current+offset)); err == nil {
+			t.Errorf("code of step %+d accepted", offset)
+		}
+	}
+}
+
+func TestVerifySecondFactorRecoveryCodeWorksOnce(t *testing.T) {
+	user, _ := twoFactorUser(t)
+	repo := &memoryTwoFactorRepository{recoveryCodes: map[string]bool{authPkg.HashRecoveryCode("abcde-fghjk"): true}}
+
+	if err := verifySecondFactor(repo, user, "abcde-fghjk"); err != nil {
+		t.Fatalf("recovery code rejected: %v", err)
+	}
+	if err := verifySecondFactor(repo, user, "abcde-fghjk"); err == nil {
+		t.Fatal("recovery code accepted twice")
+	}
+}
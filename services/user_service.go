@@ -2,17 +2,14 @@ package services
 
 import (
 	"errors"
-	"fmt"
-	"go-gin-starter/config"
 	"go-gin-starter/dto"
 	"go-gin-starter/models"
+	"go-gin-starter/pkg/assets"
 	authPkg "go-gin-starter/pkg/auth"
 	"go-gin-starter/pkg/constants"
-	"go-gin-starter/pkg/storage"
+	httpPkg "go-gin-starter/pkg/http"
+	"go-gin-starter/pkg/mail"
 	"go-gin-starter/repositories"
-	"mime/multipart"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,20 +26,22 @@ type UserService interface {
 	UpdateUserPermissions(userID uuid.UUID, permissions []string) error
 	GetUserProfile(userID uuid.UUID) (*dto.UserResponse, error)
 	UpdateUserProfile(userID uuid.UUID, input dto.UpdateUserInput) error
-	ChangeUserPassword(userID uuid.UUID, oldPassword, newPassword string) error
+	ChangeUserPassword(userID, sessionID uuid.UUID, oldPassword, newPassword string) error
 	DeleteUserProfile(userID uuid.UUID) error
-	UploadUserAvatar(userID uuid.UUID, fileHeader *multipart.FileHeader) (string, error)
+	SetEmailVerified(userID uuid.UUID, verified bool) (*models.User, error)
 }
 
 // UserServiceImpl implements UserService
 type UserServiceImpl struct {
-	userRepo repositories.UserRepository
+	userRepo       repositories.UserRepository
+	sessionService SessionService
 }
 
 // NewUserService creates a new instance of UserService
-func NewUserService(userRepo repositories.UserRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, sessionService SessionService) UserService {
 	return &UserServiceImpl{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
 	}
 }
 
@@ -106,6 +105,9 @@ func (s *UserServiceImpl) DeleteUserByID(id uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	if err := revokeAccessTokens(s.userRepo, user.ID); err != nil {
+		return err
+	}
 	return s.userRepo.Delete(user)
 }
 
@@ -125,13 +127,17 @@ func (s *UserServiceImpl) AdminUpdateUser(id uuid.UUID, input *dto.AdminUpdateUs
 	if input.Username != "" {
 		user.Username = input.Username
 	}
-	if input.Email != "" {
+	previousEmail := user.Email
+	emailChanged := input.Email != "" && input.Email != user.Email
+	if emailChanged {
 		user.Email = input.Email
+		resetEmailVerification(user)
 	}
 	if input.Gender != "" {
 		user.Gender = input.Gender
 	}
-	if input.Role != "" {
+	roleChanged := input.Role != "" && input.Role != user.Role
+	if roleChanged {
 		user.Role = input.Role
 	}
 
@@ -141,6 +147,18 @@ func (s *UserServiceImpl) AdminUpdateUser(id uuid.UUID, input *dto.AdminUpdateUs
 		return nil, err
 	}
 
+	// Tokens issued under the previous role must not outlive it
+	if roleChanged {
+		if err := revokeAccessTokens(s.userRepo, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if emailChanged {
+		accountStates.forget(user.ID)
+		notifyEmailChange(user, previousEmail)
+	}
+
 	return user, nil
 }
 
@@ -157,7 +175,7 @@ func (s *UserServiceImpl) UpdateUserPermissions(userID uuid.UUID, permissions []
 		return err
 	}
 
-	return nil
+	return revokeAccessTokens(s.userRepo, user.ID)
 }
 
 // GetUserProfile retrieves detailed user profile information
@@ -167,15 +185,6 @@ func (s *UserServiceImpl) GetUserProfile(userID uuid.UUID) (*dto.UserResponse, e
 		return nil, err
 	}
 
-	var avatarURL string
-	if strings.HasPrefix(user.Avatar, "https://") {
-		// Already a complete URL
-		avatarURL = user.Avatar
-	} else {
-		// Default avatar or partial path, add CloudFront domain
-		avatarURL = fmt.Sprintf("https://%s/avatars/%s", config.AssetCloudFrontDomain, user.Avatar)
-	}
-
 	// Get all permissions (combines role permissions with extra permissions)
 	allPermissions := authPkg.GetAllPermissions(user)
 
@@ -190,10 +199,14 @@ func (s *UserServiceImpl) GetUserProfile(userID uuid.UUID) (*dto.UserResponse, e
 		Username:         user.Username,
 		Email:            user.Email,
 		Gender:           string(user.Gender),
-		AvatarURL:        avatarURL,
+		AvatarURL:        httpPkg.UserAvatarURL(user),
+		AvatarURLs:       assets.VariantURLs(user.AvatarVariants),
 		Role:             string(user.Role),
 		Permissions:      allPermissions,
 		ExtraPermissions: extraPermissions,
+		EmailVerified:    user.EmailVerifiedAt != nil,
+		TwoFactorEnabled: user.TwoFactorEnabledAt != nil,
+		Locale:           user.Locale,
 		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 	}, nil
@@ -209,15 +222,34 @@ func (s *UserServiceImpl) UpdateUserProfile(userID uuid.UUID, input dto.UpdateUs
 	if input.Username != "" {
 		user.Username = input.Username
 	}
-	if input.Email != "" {
+	previousEmail := user.Email
+	emailChanged := input.Email != "" && input.Email != user.Email
+	if emailChanged {
 		user.Email = input.Email
+		resetEmailVerification(user)
+	}
+	if input.Locale != "" {
+		if !mail.IsSupportedLocale(input.Locale) {
+			return errors.New(constants.ErrUnsupportedLocale)
+		}
+		user.Locale = input.Locale
 	}
 
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if emailChanged {
+		accountStates.forget(user.ID)
+		notifyEmailChange(user, previousEmail)
+	}
+
+	return nil
 }
 
-// ChangeUserPassword updates the user's password
-func (s *UserServiceImpl) ChangeUserPassword(userID uuid.UUID, oldPassword, newPassword string) error {
+// ChangeUserPassword updates the user's password and ends every other session, so a stolen
+// refresh token does not outlive the password. The session of the request stays signed in.
+func (s *UserServiceImpl) ChangeUserPassword(userID, sessionID uuid.UUID, oldPassword, newPassword string) error {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return err
@@ -233,7 +265,18 @@ func (s *UserServiceImpl) ChangeUserPassword(userID uuid.UUID, oldPassword, newP
 	}
 
 	user.Password = hashed
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := revokeAccessTokens(s.userRepo, user.ID); err != nil {
+		return err
+	}
+	if _, err := s.sessionService.RevokeOthers(user.ID, sessionID); err != nil {
+		return err
+	}
+
+	sendNotice(user, user.Email, mail.NoticePasswordChanged)
+	return nil
 }
 
 // DeleteUserProfile permanently deletes a user account
@@ -242,55 +285,47 @@ func (s *UserServiceImpl) DeleteUserProfile(userID uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	if err := revokeAccessTokens(s.userRepo, user.ID); err != nil {
+		return err
+	}
 
 	return s.userRepo.Delete(user)
 }
 
-// UploadUserAvatar handles user avatar upload
-func (s *UserServiceImpl) UploadUserAvatar(userID uuid.UUID, fileHeader *multipart.FileHeader) (string, error) {
-	if fileHeader.Size > 2*1024*1024 {
-		return "", errors.New(constants.ErrAvatarTooLarge)
+// SetEmailVerified lets an admin mark a user's email address verified or unverified
+func (s *UserServiceImpl) SetEmailVerified(userID uuid.UUID, verified bool) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	ext := filepath.Ext(fileHeader.Filename)
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-		return "", errors.New(constants.ErrInvalidFileType)
+	if verified && user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	} else if !verified {
+		user.EmailVerifiedAt = nil
 	}
 
-	src, err := fileHeader.Open()
-	if err != nil {
-		return "", errors.New(constants.ErrUploadFailed)
-	}
-	defer src.Close()
-
-	newFileName := uuid.New().String() + ext
-	objectKey := fmt.Sprintf("avatars/%s", newFileName)
-	contentType := fileHeader.Header.Get("Content-Type")
-	if contentType == "" {
-		switch ext {
-		case ".jpg", ".jpeg":
-			contentType = "image/jpeg"
-		case ".png":
-			contentType = "image/png"
-		default:
-			contentType = "application/octet-stream"
-		}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
 	}
-
-	url, err := storage.UploadFileToS3(src, objectKey, contentType)
-	if err != nil {
-		return "", err
+	if !verified {
+		accountStates.forget(user.ID)
 	}
 
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return "", err
-	}
+	return user, nil
+}
 
-	user.Avatar = url
-	if err := s.userRepo.Update(user); err != nil {
-		return "", err
-	}
+// resetEmailVerification marks a changed email address unverified; the caller saves the user
+func resetEmailVerification(user *models.User) {
+	now := time.Now()
+	user.EmailVerifiedAt = nil
+	user.VerificationSentAt = &now
+}
 
-	return url, nil
+// notifyEmailChange tells the previous address about the change and sends a verification
+// link to the new one
+func notifyEmailChange(user *models.User, previousEmail string) {
+	sendNotice(user, previousEmail, mail.NoticeEmailChanged)
+	_ = sendVerificationLink(user)
 }
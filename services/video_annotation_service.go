@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	authPkg "go-gin-starter/pkg/auth"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+)
+
+// VideoAnnotationService defines the interface for timecoded annotations on match videos
+type VideoAnnotationService interface {
+	CreateAnnotation(matchID, videoID, authorID uuid.UUID, input *dto.CreateAnnotationInput) (*dto.AnnotationResponse, error)
+	GetAnnotations(matchID, videoID, viewerID uuid.UUID, filter *dto.AnnotationFilterInput) ([]dto.AnnotationResponse, error)
+	GetAdjacentAnnotation(matchID, videoID, viewerID uuid.UUID, atMs int64, direction string, filter *dto.AnnotationFilterInput) (*dto.AnnotationResponse, error)
+	UpdateAnnotation(matchID, videoID, annotationID, userID uuid.UUID, input *dto.UpdateAnnotationInput) (*dto.AnnotationResponse, error)
+	DeleteAnnotation(matchID, videoID, annotationID, userID uuid.UUID) error
+}
+
+// VideoAnnotationServiceImpl implements VideoAnnotationService
+type VideoAnnotationServiceImpl struct {
+	annotationRepo repositories.VideoAnnotationRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewVideoAnnotationService creates a new instance of VideoAnnotationService
+func NewVideoAnnotationService(
+	annotationRepo repositories.VideoAnnotationRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	userRepo repositories.UserRepository,
+) VideoAnnotationService {
+	return &VideoAnnotationServiceImpl{
+		annotationRepo: annotationRepo,
+		matchVideoRepo: matchVideoRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// CreateAnnotation adds an annotation to a match video
+func (s *VideoAnnotationServiceImpl) CreateAnnotation(matchID, videoID, authorID uuid.UUID, input *dto.CreateAnnotationInput) (*dto.AnnotationResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAnnotationRange(input.StartMs, input.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	visibility := input.Visibility
+	if visibility == "" {
+		visibility = models.AnnotationTeam
+	}
+	if !models.IsValidAnnotationVisibility(visibility) {
+		return nil, errors.New(constants.ErrInvalidVisibility)
+	}
+
+	shapes, err := buildAnnotationShapes(input.Shapes)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := &models.VideoAnnotation{
+		MatchID:      matchVideo.MatchID,
+		MatchVideoID: matchVideo.ID,
+		AuthorID:     authorID,
+		StartMs:      input.StartMs,
+		EndMs:        input.EndMs,
+		Note:         input.Note,
+		Tags:         models.StringArray(input.Tags),
+		Shapes:       shapes,
+		Visibility:   visibility,
+	}
+
+	if err := s.annotationRepo.Create(annotation); err != nil {
+		return nil, err
+	}
+
+	response := buildAnnotationResponse(annotation)
+	return &response, nil
+}
+
+// GetAnnotations lists the annotations of a match video the viewer may see, in timecode order
+func (s *VideoAnnotationServiceImpl) GetAnnotations(matchID, videoID, viewerID uuid.UUID, filter *dto.AnnotationFilterInput) ([]dto.AnnotationResponse, error) {
+	query, err := s.buildQuery(matchID, videoID, viewerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := s.annotationRepo.Find(query)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AnnotationResponse, 0, len(annotations))
+	for i := range annotations {
+		responses = append(responses, buildAnnotationResponse(&annotations[i]))
+	}
+	return responses, nil
+}
+
+// GetAdjacentAnnotation returns the next or previous visible annotation relative to atMs,
+// so the player can jump between annotations matching the same filter
+func (s *VideoAnnotationServiceImpl) GetAdjacentAnnotation(matchID, videoID, viewerID uuid.UUID, atMs int64, direction string, filter *dto.AnnotationFilterInput) (*dto.AnnotationResponse, error) {
+	var forward bool
+	switch direction {
+	case "next", "":
+		forward = true
+	case "previous":
+		forward = false
+	default:
+		return nil, errors.New(constants.ErrInvalidJumpDirection)
+	}
+
+	query, err := s.buildQuery(matchID, videoID, viewerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := s.annotationRepo.FindAdjacent(query, atMs, forward)
+	if err != nil {
+		return nil, errors.New(constants.ErrAnnotationNotFound)
+	}
+
+	response := buildAnnotationResponse(annotation)
+	return &response, nil
+}
+
+// UpdateAnnotation changes an annotation; only its author may edit it
+func (s *VideoAnnotationServiceImpl) UpdateAnnotation(matchID, videoID, annotationID, userID uuid.UUID, input *dto.UpdateAnnotationInput) (*dto.AnnotationResponse, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := s.annotationRepo.GetByID(annotationID)
+	if err != nil || annotation.MatchVideoID != matchVideo.ID {
+		return nil, errors.New(constants.ErrAnnotationNotFound)
+	}
+	if annotation.AuthorID != userID {
+		return nil, errors.New(constants.ErrForbidden)
+	}
+
+	if input.StartMs != nil {
+		annotation.StartMs = *input.StartMs
+	}
+	if input.EndMs != nil {
+		annotation.EndMs = *input.EndMs
+	}
+	if err := validateAnnotationRange(annotation.StartMs, annotation.EndMs, matchVideo); err != nil {
+		return nil, err
+	}
+
+	if input.Note != nil {
+		annotation.Note = *input.Note
+	}
+	if input.Tags != nil {
+		annotation.Tags = models.StringArray(*input.Tags)
+	}
+	if input.Shapes != nil {
+		shapes, err := buildAnnotationShapes(*input.Shapes)
+		if err != nil {
+			return nil, err
+		}
+		annotation.Shapes = shapes
+	}
+	if input.Visibility != nil {
+		if !models.IsValidAnnotationVisibility(*input.Visibility) {
+			return nil, errors.New(constants.ErrInvalidVisibility)
+		}
+		annotation.Visibility = *input.Visibility
+	}
+
+	if err := s.annotationRepo.Update(annotation); err != nil {
+		return nil, err
+	}
+
+	response := buildAnnotationResponse(annotation)
+	return &response, nil
+}
+
+// DeleteAnnotation removes an annotation; allowed for its author and match managers
+func (s *VideoAnnotationServiceImpl) DeleteAnnotation(matchID, videoID, annotationID, userID uuid.UUID) error {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return err
+	}
+
+	annotation, err := s.annotationRepo.GetByID(annotationID)
+	if err != nil || annotation.MatchVideoID != matchVideo.ID {
+		return errors.New(constants.ErrAnnotationNotFound)
+	}
+
+	if annotation.AuthorID != userID {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || !authPkg.HasPermission(user, "manage_matches") {
+			return errors.New(constants.ErrForbidden)
+		}
+	}
+
+	return s.annotationRepo.Delete(annotation.ID)
+}
+
+// getMatchVideo loads a match video and checks that it belongs to the match
+func (s *VideoAnnotationServiceImpl) getMatchVideo(matchID, videoID uuid.UUID) (*models.MatchVideo, error) {
+	matchVideo, err := s.matchVideoRepo.GetByID(videoID)
+	if err != nil || matchVideo.MatchID != matchID {
+		return nil, errors.New(constants.ErrMatchVideoNotFound)
+	}
+	return matchVideo, nil
+}
+
+// buildQuery turns the request filter into a repository query scoped to what the viewer may see
+func (s *VideoAnnotationServiceImpl) buildQuery(matchID, videoID, viewerID uuid.UUID, filter *dto.AnnotationFilterInput) (repositories.AnnotationQuery, error) {
+	matchVideo, err := s.getMatchVideo(matchID, videoID)
+	if err != nil {
+		return repositories.AnnotationQuery{}, err
+	}
+
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return repositories.AnnotationQuery{}, errors.New(constants.ErrUserNotFound)
+	}
+
+	query := repositories.AnnotationQuery{
+		MatchVideoID:  matchVideo.ID,
+		ViewerID:      viewer.ID,
+		ViewerIsStaff: authPkg.HasPermission(viewer, "annotate_video"),
+		Tags:          filter.Tags,
+		FromMs:        filter.FromMs,
+		ToMs:          filter.ToMs,
+	}
+
+	if filter.AuthorID != "" {
+		authorID, err := uuid.Parse(filter.AuthorID)
+		if err != nil {
+			return repositories.AnnotationQuery{}, errors.New(constants.ErrInvalidUserID)
+		}
+		query.AuthorID = &authorID
+	}
+
+	if filter.Visibility != "" {
+		visibility := models.AnnotationVisibilityEnum(filter.Visibility)
+		if !models.IsValidAnnotationVisibility(visibility) {
+			return repositories.AnnotationQuery{}, errors.New(constants.ErrInvalidVisibility)
+		}
+		query.Visibility = visibility
+	}
+
+	return query, nil
+}
+
+// validateAnnotationRange checks that a timecode range is ordered and, once the video
+// has been probed, inside its duration
+func validateAnnotationRange(startMs, endMs int64, matchVideo *models.MatchVideo) error {
+	if startMs < 0 || endMs < startMs {
+		return errors.New(constants.ErrInvalidAnnotationTime)
+	}
+	if duration := matchVideo.Metadata.DurationMs; duration > 0 && endMs > duration {
+		return errors.New(constants.ErrInvalidAnnotationTime)
+	}
+	return nil
+}
+
+// buildAnnotationShapes validates drawing input and maps it to the stored representation
+func buildAnnotationShapes(inputs []dto.AnnotationShapeInput) (models.AnnotationShapes, error) {
+	shapes := make(models.AnnotationShapes, 0, len(inputs))
+	for _, input := range inputs {
+		if !models.IsValidAnnotationShape(input.Type) {
+			return nil, errors.New(constants.ErrInvalidAnnotationDraw)
+		}
+
+		switch input.Type {
+		case models.AnnotationShapeArrow, models.AnnotationShapeLine:
+			if len(input.Points) != 2 {
+				return nil, errors.New(constants.ErrInvalidAnnotationDraw)
+			}
+		case models.AnnotationShapeCircle:
+			if len(input.Points) != 1 || input.Radius <= 0 {
+				return nil, errors.New(constants.ErrInvalidAnnotationDraw)
+			}
+		}
+
+		points := make([]models.AnnotationPoint, 0, len(input.Points))
+		for _, point := range input.Points {
+			points = append(points, models.AnnotationPoint{X: point.X, Y: point.Y})
+		}
+
+		shapes = append(shapes, models.AnnotationShape{
+			Type:   input.Type,
+			Points: points,
+			Radius: input.Radius,
+			Color:  input.Color,
+		})
+	}
+	return shapes, nil
+}
+
+// buildAnnotationResponse maps an annotation to its DTO
+func buildAnnotationResponse(annotation *models.VideoAnnotation) dto.AnnotationResponse {
+	shapes := make([]dto.AnnotationShapeResponse, 0, len(annotation.Shapes))
+	for _, shape := range annotation.Shapes {
+		points := make([]dto.AnnotationPointResponse, 0, len(shape.Points))
+		for _, point := range shape.Points {
+			points = append(points, dto.AnnotationPointResponse{X: point.X, Y: point.Y})
+		}
+
+		shapes = append(shapes, dto.AnnotationShapeResponse{
+			Type:   shape.Type,
+			Points: points,
+			Radius: shape.Radius,
+			Color:  shape.Color,
+		})
+	}
+
+	tags := []string(annotation.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return dto.AnnotationResponse{
+		ID:           annotation.ID,
+		MatchID:      annotation.MatchID,
+		MatchVideoID: annotation.MatchVideoID,
+		AuthorID:     annotation.AuthorID,
+		StartMs:      annotation.StartMs,
+		EndMs:        annotation.EndMs,
+		Note:         annotation.Note,
+		Tags:         tags,
+		Shapes:       shapes,
+		Visibility:   annotation.Visibility,
+		CreatedAt:    annotation.CreatedAt,
+		UpdatedAt:    annotation.UpdatedAt,
+	}
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-gin-starter/dto"
+	"go-gin-starter/models"
+	"go-gin-starter/pkg/constants"
+	"go-gin-starter/pkg/logger"
+	storagePkg "go-gin-starter/pkg/storage"
+	uploadPkg "go-gin-starter/pkg/upload"
+	"go-gin-starter/pkg/video"
+	"go-gin-starter/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// allowedVideoContentTypes maps accepted raw video extensions to their MIME types
+var allowedVideoContentTypes = map[string]string{
+	".mp4": "video/mp4",
+	".mov": "video/quicktime",
+	".mkv": "video/x-matroska",
+}
+
+// VideoUploadService defines the interface for presigned direct-to-storage video uploads
+type VideoUploadService interface {
+	InitiateUpload(matchID, userID uuid.UUID, input *dto.InitiateVideoUploadInput) (*dto.VideoUploadResponse, error)
+	CompleteUpload(matchID, uploadID uuid.UUID, input *dto.CompleteVideoUploadInput) (*dto.CompletedVideoUploadResponse, error)
+	AbortUpload(matchID, uploadID uuid.UUID) error
+}
+
+// VideoUploadServiceImpl implements VideoUploadService
+type VideoUploadServiceImpl struct {
+	uploadRepo     repositories.VideoUploadRepository
+	matchRepo      repositories.MatchRepository
+	matchVideoRepo repositories.MatchVideoRepository
+	artifactRepo   repositories.VideoArtifactRepository
+	seasonRepo     repositories.SeasonRepository
+	store          storagePkg.Store
+	videoQueue     *video.QueueManager
+}
+
+// NewVideoUploadService creates a new instance of VideoUploadService
+func NewVideoUploadService(
+	uploadRepo repositories.VideoUploadRepository,
+	matchRepo repositories.MatchRepository,
+	matchVideoRepo repositories.MatchVideoRepository,
+	artifactRepo repositories.VideoArtifactRepository,
+	seasonRepo repositories.SeasonRepository,
+	store storagePkg.Store,
+	videoQueue *video.QueueManager,
+) VideoUploadService {
+	return &VideoUploadServiceImpl{
+		uploadRepo:     uploadRepo,
+		matchRepo:      matchRepo,
+		matchVideoRepo: matchVideoRepo,
+		artifactRepo:   artifactRepo,
+		seasonRepo:     seasonRepo,
+		store:          store,
+		videoQueue:     videoQueue,
+	}
+}
+
+// InitiateUpload starts a multipart upload for the match's raw video and presigns all part URLs
+func (s *VideoUploadServiceImpl) InitiateUpload(matchID, userID uuid.UUID, input *dto.InitiateVideoUploadInput) (*dto.VideoUploadResponse, error) {
+	ext := strings.ToLower(filepath.Ext(input.FileName))
+	expectedType, ok := allowedVideoContentTypes[ext]
+	if !ok || input.ContentType != expectedType {
+		return nil, errors.New(constants.ErrInvalidVideoType)
+	}
+
+	if input.FileSize > constants.MaxVideoFileSize {
+		return nil, errors.New(constants.ErrVideoTooLarge)
+	}
+
+	angle, err := normalizeVideoAngle(input.Angle)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchNotFound)
+	}
+
+	season, err := s.seasonRepo.GetByID(match.SeasonID)
+	if err != nil {
+		return nil, errors.New(constants.ErrSeasonNotFound)
+	}
+
+	partCount := (input.FileSize + constants.VideoUploadPartSize - 1) / constants.VideoUploadPartSize
+	if partCount > constants.VideoUploadMaxParts {
+		return nil, errors.New(constants.ErrVideoTooLarge)
+	}
+
+	basePath := video.BuildMatchBasePath(season, match.ID)
+	rawKey := video.BuildRawVideoKey(basePath, ext)
+	compressedKey := video.BuildCompressedVideoKey(basePath)
+
+	// Tag as raw so the lifecycle transition applies to direct uploads too
+	s3UploadID, err := s.store.CreateMultipartUpload(rawKey, input.ContentType, "storage=raw")
+	if err != nil {
+		logger.Error("Failed to create multipart upload", zap.String("key", rawKey), zap.Error(err))
+		return nil, errors.New(constants.ErrUploadFailed)
+	}
+
+	parts, err := s.store.PresignUploadParts(rawKey, s3UploadID, partCount, constants.VideoUploadExpiry)
+	if err != nil {
+		logger.Error("Failed to presign upload parts", zap.String("key", rawKey), zap.Error(err))
+		_ = s.store.AbortMultipartUpload(rawKey, s3UploadID)
+		return nil, errors.New(constants.ErrUploadFailed)
+	}
+
+	upload := &models.VideoUpload{
+		MatchID:     match.ID,
+		Angle:       angle,
+		UploadedBy:  userID,
+		S3UploadID:  s3UploadID,
+		ObjectKey:   rawKey,
+		OutputKey:   compressedKey,
+		FileName:    input.FileName,
+		ContentType: input.ContentType,
+		FileSize:    input.FileSize,
+		PartSize:    constants.VideoUploadPartSize,
+		PartCount:   int(partCount),
+		Status:      models.VideoUploadPending,
+		ExpiresAt:   time.Now().Add(constants.VideoUploadExpiry),
+	}
+
+	if err := s.uploadRepo.Create(upload); err != nil {
+		_ = s.store.AbortMultipartUpload(rawKey, s3UploadID)
+		return nil, err
+	}
+
+	partResponses := make([]dto.PresignedPartResponse, 0, len(parts))
+	for _, part := range parts {
+		partResponses = append(partResponses, dto.PresignedPartResponse{
+			PartNumber: part.PartNumber,
+			URL:        part.URL,
+		})
+	}
+
+	return &dto.VideoUploadResponse{
+		UploadID:  upload.ID,
+		MatchID:   match.ID,
+		Angle:     upload.Angle,
+		ObjectKey: rawKey,
+		PartSize:  upload.PartSize,
+		Parts:     partResponses,
+		ExpiresAt: upload.ExpiresAt,
+	}, nil
+}
+
+// CompleteUpload assembles the parts, verifies the stored object and enqueues processing
+func (s *VideoUploadServiceImpl) CompleteUpload(matchID, uploadID uuid.UUID, input *dto.CompleteVideoUploadInput) (*dto.CompletedVideoUploadResponse, error) {
+	upload, err := s.getPendingUpload(matchID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, errors.New(constants.ErrMatchNotFound)
+	}
+
+	completedParts := make([]storagePkg.CompletedPart, 0, len(input.Parts))
+	for _, part := range input.Parts {
+		completedParts = append(completedParts, storagePkg.CompletedPart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		})
+	}
+	sort.Slice(completedParts, func(i, j int) bool {
+		return completedParts[i].PartNumber < completedParts[j].PartNumber
+	})
+
+	if err := s.store.CompleteMultipartUpload(upload.ObjectKey, upload.S3UploadID, completedParts); err != nil {
+		logger.Error("Failed to complete multipart upload",
+			zap.String("upload_id", upload.ID.String()),
+			zap.Error(err))
+		return nil, errors.New(constants.ErrUploadFailed)
+	}
+
+	// Verify what actually landed in storage before trusting it
+	if err := s.verifyUploadedObject(upload); err != nil {
+		_ = s.store.Delete(upload.ObjectKey)
+		s.markUpload(upload, models.VideoUploadFailed, err.Error())
+		return nil, err
+	}
+
+	matchVideo, err := attachMatchVideo(s.matchVideoRepo, s.matchRepo, s.artifactRepo, match, upload.Angle, upload.ObjectKey, upload.OutputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &video.VideoProcessingJob{
+		MatchID:      match.ID.String(),
+		MatchVideoID: matchVideo.ID.String(),
+		InputKey:     upload.ObjectKey,
+		OutputKey:    upload.OutputKey,
+	}
+	if err := s.videoQueue.EnqueueVideo(job); err != nil {
+		logger.Error("Failed to enqueue video job",
+			zap.String("match_id", match.ID.String()),
+			zap.Error(err))
+	}
+
+	now := time.Now()
+	upload.CompletedAt = &now
+	s.markUpload(upload, models.VideoUploadCompleted, "")
+
+	return &dto.CompletedVideoUploadResponse{
+		UploadID:     upload.ID,
+		MatchID:      match.ID,
+		MatchVideoID: matchVideo.ID,
+		Angle:        matchVideo.Angle,
+		VideoURL:     fullMediaAccess.videoURL(matchVideo.VideoURL),
+		Status:       string(upload.Status),
+	}, nil
+}
+
+// AbortUpload cancels a pending upload and discards the parts uploaded so far
+func (s *VideoUploadServiceImpl) AbortUpload(matchID, uploadID uuid.UUID) error {
+	upload, err := s.getPendingUpload(matchID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.AbortMultipartUpload(upload.ObjectKey, upload.S3UploadID); err != nil {
+		logger.Error("Failed to abort multipart upload",
+			zap.String("upload_id", upload.ID.String()),
+			zap.Error(err))
+		return errors.New(constants.ErrUploadFailed)
+	}
+
+	s.markUpload(upload, models.VideoUploadAborted, "")
+	return nil
+}
+
+// getPendingUpload loads an upload and checks that it belongs to the match and can still be used
+func (s *VideoUploadServiceImpl) getPendingUpload(matchID, uploadID uuid.UUID) (*models.VideoUpload, error) {
+	upload, err := s.uploadRepo.GetByID(uploadID)
+	if err != nil || upload.MatchID != matchID {
+		return nil, errors.New(constants.ErrVideoUploadNotFound)
+	}
+
+	if upload.Status != models.VideoUploadPending {
+		return nil, errors.New(constants.ErrVideoUploadNotPending)
+	}
+
+	if upload.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New(constants.ErrVideoUploadExpired)
+	}
+
+	return upload, nil
+}
+
+// verifyUploadedObject checks existence, size and type of the assembled object
+func (s *VideoUploadServiceImpl) verifyUploadedObject(upload *models.VideoUpload) error {
+	head, err := s.store.Head(upload.ObjectKey)
+	if err != nil {
+		return errors.New(constants.ErrVideoObjectMissing)
+	}
+
+	size := head.Size
+	if size > constants.MaxVideoFileSize {
+		return errors.New(constants.ErrVideoTooLarge)
+	}
+	if size != upload.FileSize {
+		return errors.New(constants.ErrVideoSizeMismatch)
+	}
+
+	if head.ContentType != upload.ContentType {
+		return errors.New(constants.ErrInvalidVideoType)
+	}
+
+	// The client chose the declared type; the bytes it uploaded must agree with it
+	body, _, err := s.store.Get(upload.ObjectKey)
+	if err != nil {
+		return errors.New(constants.ErrVideoObjectMissing)
+	}
+	defer body.Close()
+
+	prefix := make([]byte, uploadPkg.SniffLength)
+	n, err := io.ReadFull(body, prefix)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	contentType, err := uploadPkg.SniffContentType(uploadPkg.MatchVideo, prefix[:n])
+	if err != nil || contentType != upload.ContentType {
+		return errors.New(constants.ErrFileContentMismatch)
+	}
+
+	return nil
+}
+
+// markUpload persists a status change, logging instead of failing the request on error
+func (s *VideoUploadServiceImpl) markUpload(upload *models.VideoUpload, status models.VideoUploadStatusEnum, reason string) {
+	upload.Status = status
+	upload.Error = reason
+	if err := s.uploadRepo.Update(upload); err != nil {
+		logger.Error("Failed to update video upload status",
+			zap.String("upload_id", upload.ID.String()),
+			zap.String("status", string(status)),
+			zap.Error(err))
+	}
+}
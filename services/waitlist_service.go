@@ -22,13 +22,15 @@ type WaitlistService interface {
 type WaitlistServiceImpl struct {
 	waitlistRepo repositories.WaitlistRepository
 	userService  UserService
+	authService  AuthService
 }
 
 // NewWaitlistService creates a new instance of WaitlistService
-func NewWaitlistService(waitlistRepo repositories.WaitlistRepository, userService UserService) WaitlistService {
+func NewWaitlistService(waitlistRepo repositories.WaitlistRepository, userService UserService, authService AuthService) WaitlistService {
 	return &WaitlistServiceImpl{
 		waitlistRepo: waitlistRepo,
 		userService:  userService,
+		authService:  authService,
 	}
 }
 
@@ -66,7 +68,7 @@ func (s *WaitlistServiceImpl) GetAllWaitlistEntries() ([]dto.WaitlistEntryRespon
 	return responses, nil
 }
 
-// ApproveWaitlistEntry creates a user and removes the waitlist entry
+// ApproveWaitlistEntry creates a user, invites them by email and removes the waitlist entry
 func (s *WaitlistServiceImpl) ApproveWaitlistEntry(id uuid.UUID) error {
 	entry, err := s.waitlistRepo.FindWaitlistEntryByID(id)
 	if err != nil {
@@ -77,11 +79,16 @@ func (s *WaitlistServiceImpl) ApproveWaitlistEntry(id uuid.UUID) error {
 	randomPassword := authPkg.GenerateRandomPassword()
 
 	// Use the user service to create the user
-	_, err = s.userService.CreateUser(entry.Email, entry.Email, randomPassword, "other")
+	user, err := s.userService.CreateUser(entry.Email, entry.Email, randomPassword, "other")
 	if err != nil {
 		return err
 	}
 
+	// The random password is never shown; the invitation lets the user choose their own
+	if err := s.authService.InviteUser(user); err != nil {
+		return err
+	}
+
 	// Remove waitlist entry
 	return s.waitlistRepo.DeleteWaitlistEntryByID(id)
 }